@@ -29,6 +29,7 @@ func newAppCmd(cfg config, out io.Writer, packSvc *pack.Client, configDefaultBui
 		KubeClient:     cfg.KubernetesClient(),
 		Builder:        build.GetSourceHandler(packSvc),
 		GetImageConfig: deploy.GetImageConfig,
+		GetImageDigest: deploy.GetImageDigest,
 		Wait:           deploy.WaitForDeployment,
 		Writer:         out,
 	}
@@ -41,6 +42,10 @@ func newAppCmd(cfg config, out io.Writer, packSvc *pack.Client, configDefaultBui
 	cmd.AddCommand(newAppStartCmd(cfg, out, appStart))
 	cmd.AddCommand(newAppStopCmd(cfg, out, appStop))
 	cmd.AddCommand(newAppExportCmd(cfg, exportApp, out))
+	cmd.AddCommand(newAppMoveCmd(cfg, out))
+	cmd.AddCommand(newAppPatchCmd(cfg, out))
+	cmd.AddCommand(newAppRenderCmd(cfg, params, out))
+	cmd.AddCommand(newAppDiffCmd(cfg, out))
 	return cmd
 }
 
@@ -1,8 +1,18 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/build"
 	"github.com/theketchio/ketch/internal/deploy"
 	"github.com/theketchio/ketch/internal/validation"
 )
@@ -25,15 +35,35 @@ Deploy from an image:
   ketch app deploy <app name> -i myregistry/myimage:latest
 
 Users can deploy from image or source code by passing a filename such as app.yaml containing fields like:
-	name: test
-	image: gcr.io/shipa-ci/sample-go-app:latest
-	framework: myframework
+  name: test
+  image: gcr.io/shipa-ci/sample-go-app:latest
+  framework: myframework
+
+The file describes the whole app: cnames, labels, annotations, service bindings, environment and serviceAccountName
+omitted from the file are removed from the app, a warning lists the ones the app has. Older versions of ketch kept them,
+so deploying a file written for an older version, e.g. in CI, wipes cnames and labels added with the CLI since.
+Run "ketch app export" to get a file with the current state of the app. Healthcheck and hooks omitted from the file
+are read from ketch.yaml of the source directory.
+
+Print manifests the deployment produces without changing the app, the image isn't built when deploying from source:
+  ketch app deploy <app name> -i myregistry/myimage:latest --dry-run
+
+Merge an application.<env>.yaml overlay into an application.yaml file, e.g. application.prod.yaml to deploy to prod.
+Fields of the overlay override fields of application.yaml, processes are merged by name, environment by variable name,
+cnames and other lists are replaced and a null value removes a field. With --dry-run the merged file is printed
+as a comment before manifests:
+  ketch app deploy application.yaml --env-overlay prod
+
+Set the app's env variables from a dotenv file, or a .json or .yaml file with an object of names and values.
+Variables of --env override variables of the file:
+  ketch app deploy <app name> -i myregistry/myimage:latest --env-file .env
 `
 )
 
 // NewCommand creates a command that will run the app deploy
 func newAppDeployCmd(cfg config, params *deploy.Services, configDefaultBuilder string) *cobra.Command {
 	var options deploy.Options
+	var dryRun bool
 
 	cmd := &cobra.Command{
 		Use:   "deploy [APPNAME|FILENAME] [SOURCE DIRECTORY]",
@@ -48,6 +78,9 @@ func newAppDeployCmd(cfg config, params *deploy.Services, configDefaultBuilder s
 			if configDefaultBuilder != "" {
 				deploy.DefaultBuilder = configDefaultBuilder
 			}
+			if dryRun {
+				return appDeployDryRun(cmd, cfg, options, params)
+			}
 			return appDeploy(cmd, options, params)
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -66,6 +99,7 @@ func newAppDeployCmd(cfg config, params *deploy.Services, configDefaultBuilder s
 
 	cmd.Flags().StringVarP(&options.Description, deploy.FlagDescription, deploy.FlagDescriptionShort, "", "App description.")
 	cmd.Flags().StringSliceVarP(&options.Envs, deploy.FlagEnvironment, deploy.FlagEnvironmentShort, []string{}, "App env variables.")
+	cmd.Flags().StringVar(&options.EnvFile, deploy.FlagEnvFile, "", "A dotenv, json or yaml file with app env variables.")
 	cmd.Flags().StringVarP(&options.Framework, deploy.FlagFramework, deploy.FlagFrameworkShort, "", "Framework to deploy your app.")
 	cmd.Flags().StringVarP(&options.DockerRegistrySecret, deploy.FlagRegistrySecret, "", "", "A name of a Secret with docker credentials. This secret must be created in the same namespace of the framework.")
 	cmd.Flags().StringVar(&options.Builder, deploy.FlagBuilder, "", "Builder to use when building from source.")
@@ -74,6 +108,8 @@ func newAppDeployCmd(cfg config, params *deploy.Services, configDefaultBuilder s
 	cmd.Flags().IntVar(&options.Units, deploy.FlagUnits, 1, "Set number of units for deployment.")
 	cmd.Flags().IntVar(&options.Version, deploy.FlagVersion, 1, "Specify version whose units to update. Must be used with units flag!")
 	cmd.Flags().StringVar(&options.Process, deploy.FlagProcess, "", "Specify process whose units to update. Must be used with units flag!")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print manifests the deployment produces without changing the app.")
+	cmd.Flags().StringVar(&options.EnvOverlay, deploy.FlagEnvOverlay, "", "Environment of an application.<env>.yaml overlay merged into the application.yaml file.")
 
 	cmd.RegisterFlagCompletionFunc(deploy.FlagFramework, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return autoCompleteFrameworkNames(cfg, toComplete)
@@ -85,16 +121,97 @@ func newAppDeployCmd(cfg config, params *deploy.Services, configDefaultBuilder s
 }
 
 func appDeploy(cmd *cobra.Command, options deploy.Options, params *deploy.Services) error {
-	var changeSet *deploy.ChangeSet
-	var err error
-	switch {
-	case validation.ValidateYamlFilename(options.AppName):
-		changeSet, err = options.GetChangeSetFromYaml(options.AppName)
-		if err != nil {
+	changeSet, err := getChangeSet(cmd, options)
+	if err != nil {
+		return err
+	}
+	if err := warnRemovedFields(cmd.Context(), options, params, changeSet, ""); err != nil {
+		return err
+	}
+	return deploy.New(changeSet).Run(cmd.Context(), params)
+}
+
+func appDeployDryRun(cmd *cobra.Command, cfg config, options deploy.Options, params *deploy.Services) error {
+	changeSet, err := getChangeSet(cmd, options)
+	if err != nil {
+		return err
+	}
+	// the warning is a yaml comment, so the output remains a valid list of manifests
+	if err := warnRemovedFields(cmd.Context(), options, params, changeSet, "# "); err != nil {
+		return err
+	}
+	app, err := dryRunDeploy(cmd.Context(), changeSet, params)
+	if err != nil {
+		return err
+	}
+	if options.EnvOverlay != "" {
+		if err := writeMergedApplication(params.Writer, options, options.AppName); err != nil {
 			return err
 		}
-	default:
-		changeSet = options.GetChangeSet(cmd.Flags())
 	}
-	return deploy.New(changeSet).Run(cmd.Context(), params)
+	return renderAppManifests(cmd.Context(), cfg, app, params.Writer)
+}
+
+func getChangeSet(cmd *cobra.Command, options deploy.Options) (*deploy.ChangeSet, error) {
+	if validation.ValidateYamlFilename(options.AppName) {
+		return options.GetChangeSetFromYaml(options.AppName)
+	}
+	if options.EnvOverlay != "" {
+		return nil, ErrEnvOverlayRequiresFile
+	}
+	return options.GetChangeSet(cmd.Flags()), nil
+}
+
+// warnRemovedFields warns about fields of an existing app the deployment removes because an application.yaml file omits them,
+// e.g. cnames added with the CLI since the file was written. Each line of the warning starts with the prefix.
+func warnRemovedFields(ctx context.Context, options deploy.Options, params *deploy.Services, changeSet *deploy.ChangeSet, prefix string) error {
+	if !validation.ValidateYamlFilename(options.AppName) {
+		return nil
+	}
+	var app ketchv1.App
+	if err := params.Client.Get(ctx, types.NamespacedName{Name: changeSet.AppName()}, &app); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	removed := changeSet.RemovedFields(app)
+	if len(removed) == 0 {
+		return nil
+	}
+	fmt.Fprintf(params.Writer, "%sWarning: the file omits %s of %q app, they are removed from the app.\n", prefix, strings.Join(removed, ", "), app.Name)
+	fmt.Fprintf(params.Writer, "%sRun \"ketch app export\" to get a file with the current state of the app.\n", prefix)
+	return nil
+}
+
+// writeMergedApplication writes the application.yaml file merged with its overlay as a yaml comment,
+// so the output remains a valid list of manifests.
+func writeMergedApplication(out io.Writer, options deploy.Options, filename string) error {
+	merged, err := options.ReadApplicationYaml(filename)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "# %s merged with %s:\n", filename, deploy.OverlayFilename(filename, options.EnvOverlay))
+	for _, line := range strings.Split(strings.TrimSuffix(string(merged), "\n"), "\n") {
+		fmt.Fprintf(out, "# %s\n", line)
+	}
+	return nil
+}
+
+// dryRunDeploy runs the deployment without changing the app and building an image, it returns the app as it would be deployed.
+func dryRunDeploy(ctx context.Context, changeSet *deploy.ChangeSet, params *deploy.Services) (*ketchv1.App, error) {
+	dryRunClient := deploy.NewDryRunClient(params.Client)
+	svc := *params
+	svc.Client = dryRunClient
+	svc.Builder = func(context.Context, *build.CreateImageFromSourceRequest, ...build.Option) error {
+		return nil
+	}
+	svc.Wait = func(context.Context, *deploy.Services, *ketchv1.App, time.Duration) error {
+		return nil
+	}
+	if err := deploy.New(changeSet).Run(ctx, &svc); err != nil {
+		return nil, err
+	}
+	var app ketchv1.App
+	if err := dryRunClient.Get(ctx, types.NamespacedName{Name: changeSet.AppName()}, &app); err != nil {
+		return nil, err
+	}
+	return &app, nil
 }
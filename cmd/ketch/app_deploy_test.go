@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"path/filepath"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -15,13 +16,17 @@ import (
 	v1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 	"github.com/theketchio/ketch/internal/build"
 	"github.com/theketchio/ketch/internal/deploy"
+	"github.com/theketchio/ketch/internal/mocks"
 	"github.com/theketchio/ketch/internal/pack"
+	"github.com/theketchio/ketch/internal/signature/signaturetest"
+	"github.com/theketchio/ketch/internal/utils/conversions"
 )
 
 type getterCreatorMockFn func(m *mockClient, obj runtime.Object) error
@@ -519,6 +524,35 @@ func TestNewCommand(t *testing.T) {
 				Writer:         &bytes.Buffer{},
 			},
 		},
+		{
+			name:      "image rejected by framework image policy",
+			wantError: true,
+			arguments: []string{
+				"myapp",
+				"--framework", "myframework",
+				"--image", "shipa/go-sample:latest",
+			},
+			setup: func(t *testing.T) {
+				dir := t.TempDir()
+				require.Nil(t, os.Chdir(dir))
+			},
+			params: &deploy.Services{
+				Client: func() *mockClient {
+					m := newMockClient()
+					m.get[1] = func(_ *mockClient, _ runtime.Object) error {
+						return errors.NewNotFound(v1.Resource(""), "")
+					}
+					m.framework.Spec.ImagePolicy = &ketchv1.ImagePolicy{AllowedRegistries: []string{"gcr.io/shipa"}}
+					return m
+				}(),
+
+				KubeClient:     fake.NewSimpleClientset(),
+				Builder:        build.GetSourceHandler(&packMocker{}),
+				GetImageConfig: getImageConfig,
+				Wait:           nil,
+				Writer:         &bytes.Buffer{},
+			},
+		},
 		{
 			name:      "missing source path",
 			wantError: true,
@@ -974,3 +1008,204 @@ func TestNewCommand(t *testing.T) {
 		})
 	}
 }
+
+func Test_appDeployDryRun(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-gke",
+			IngressController: ketchv1.IngressControllerSpec{
+				IngressType: ketchv1.TraefikIngressControllerType,
+			},
+		},
+	}
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			Framework:        "gke",
+			DeploymentsCount: 1,
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Image:           "shipasoftware/dashboard:v1",
+					Version:         1,
+					Processes:       []ketchv1.ProcessSpec{{Name: "web", Units: conversions.IntPtr(1), Cmd: []string{"/app"}}},
+					RoutingSettings: ketchv1.RoutingSettings{Weight: 100},
+				},
+			},
+		},
+	}
+	cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{gke, dashboard}}
+	out := &bytes.Buffer{}
+	params := &deploy.Services{
+		Client:         cfg.Client(),
+		GetImageConfig: getImageConfig,
+		Writer:         out,
+	}
+	cmd := newAppDeployCmd(cfg, params, "")
+	cmd.SetArgs([]string{"dashboard", "--image", "shipasoftware/dashboard:v2", "--dry-run"})
+	require.Nil(t, cmd.Execute())
+	require.Contains(t, out.String(), "name: dashboard-web-2")
+	require.Contains(t, out.String(), "image: shipasoftware/dashboard:v2")
+
+	var app ketchv1.App
+	require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+	require.Equal(t, "shipasoftware/dashboard:v1", app.Spec.Deployments[0].Image)
+}
+
+func Test_appDeployWarnsAboutRemovedFields(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-gke",
+			IngressController: ketchv1.IngressControllerSpec{
+				IngressType: ketchv1.TraefikIngressControllerType,
+			},
+		},
+	}
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			Framework: "gke",
+			Ingress:   ketchv1.IngressSpec{Cnames: ketchv1.CnameList{{Name: "dashboard.theketch.io"}}},
+			Labels:    []ketchv1.MetadataItem{{Apply: map[string]string{"team": "dashboard"}}},
+		},
+	}
+	filename := filepath.Join(t.TempDir(), "application.yaml")
+	require.Nil(t, os.WriteFile(filename, []byte(`name: dashboard
+framework: gke
+image: shipasoftware/dashboard:v2
+labels:
+  - apply:
+      team: dashboard
+`), 0644))
+	cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{gke, dashboard}}
+	out := &bytes.Buffer{}
+	params := &deploy.Services{
+		Client:         cfg.Client(),
+		GetImageConfig: getImageConfig,
+		Writer:         out,
+	}
+	cmd := newAppDeployCmd(cfg, params, "")
+	cmd.SetArgs([]string{filename})
+	require.Nil(t, cmd.Execute())
+	require.Contains(t, out.String(), `Warning: the file omits cnames of "dashboard" app, they are removed from the app.`)
+
+	var app ketchv1.App
+	require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+	require.Empty(t, app.Spec.Ingress.Cnames)
+}
+
+func Test_appDeployPinsImageDigest(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-gke",
+			IngressController: ketchv1.IngressControllerSpec{
+				IngressType: ketchv1.TraefikIngressControllerType,
+			},
+		},
+	}
+	digest := "sha256:3b1c2c6d1a6f8e3a2b7c9d0e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e2f3a"
+	cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{gke}}
+	params := &deploy.Services{
+		Client:         cfg.Client(),
+		GetImageConfig: getImageConfig,
+		GetImageDigest: func(context.Context, deploy.ImageConfigRequest) (string, error) {
+			return digest, nil
+		},
+		Writer: &bytes.Buffer{},
+	}
+	cmd := newAppDeployCmd(cfg, params, "")
+	cmd.SetArgs([]string{"dashboard", "--framework", "gke", "--image", "shipasoftware/dashboard:latest"})
+	require.Nil(t, cmd.Execute())
+
+	var app ketchv1.App
+	require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+	require.Equal(t, "shipasoftware/dashboard:latest", app.Spec.Deployments[0].Image)
+	require.Equal(t, digest, app.Spec.Deployments[0].ImageDigest)
+
+	manifests, err := appManifests(context.Background(), cfg, &app)
+	require.Nil(t, err)
+	require.Contains(t, string(manifests), "image: shipasoftware/dashboard:latest@"+digest)
+}
+
+func Test_appDeployRequiredDigestAcceptsPinnedTag(t *testing.T) {
+	production := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "production"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-production",
+			IngressController: ketchv1.IngressControllerSpec{
+				IngressType: ketchv1.TraefikIngressControllerType,
+			},
+			ImagePolicy: &ketchv1.ImagePolicy{RequireDigest: true},
+		},
+	}
+	digest := "sha256:3b1c2c6d1a6f8e3a2b7c9d0e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e2f3a"
+	cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{production}}
+
+	// without a digest to pin the tag to, the tag is rejected
+	params := &deploy.Services{
+		Client:         cfg.Client(),
+		GetImageConfig: getImageConfig,
+		Writer:         &bytes.Buffer{},
+	}
+	cmd := newAppDeployCmd(cfg, params, "")
+	cmd.SetArgs([]string{"dashboard", "--framework", "production", "--image", "shipasoftware/dashboard:latest"})
+	err := cmd.Execute()
+	require.NotNil(t, err)
+	require.ErrorIs(t, err, ketchv1.ErrImageDigestRequired)
+
+	params.GetImageDigest = func(context.Context, deploy.ImageConfigRequest) (string, error) {
+		return digest, nil
+	}
+	cmd = newAppDeployCmd(cfg, params, "")
+	cmd.SetArgs([]string{"dashboard", "--framework", "production", "--image", "shipasoftware/dashboard:latest"})
+	require.Nil(t, cmd.Execute())
+	var app ketchv1.App
+	require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+	require.Equal(t, "shipasoftware/dashboard:latest", app.Spec.Deployments[0].Image)
+	require.Equal(t, digest, app.Spec.Deployments[0].ImageDigest)
+}
+
+func Test_appDeployVerifiesImageSignatures(t *testing.T) {
+	host := signaturetest.NewRegistry(t)
+	key, publicKey := signaturetest.NewKey(t)
+	signedImage := host + "/shipasoftware/dashboard:signed"
+	signaturetest.Sign(t, signedImage, signaturetest.PushImage(t, signedImage), key, "")
+	unsignedImage := host + "/shipasoftware/dashboard:unsigned"
+	signaturetest.PushImage(t, unsignedImage)
+
+	production := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "production"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-production",
+			IngressController: ketchv1.IngressControllerSpec{
+				IngressType: ketchv1.TraefikIngressControllerType,
+			},
+			ImagePolicy: &ketchv1.ImagePolicy{
+				Signatures: &ketchv1.SignaturePolicy{PublicKeys: []string{publicKey}},
+			},
+		},
+	}
+	cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{production}}
+	params := &deploy.Services{
+		Client:         cfg.Client(),
+		GetImageConfig: getImageConfig,
+		GetImageDigest: deploy.GetImageDigest,
+		Writer:         &bytes.Buffer{},
+	}
+
+	cmd := newAppDeployCmd(cfg, params, "")
+	cmd.SetArgs([]string{"dashboard", "--framework", "production", "--image", unsignedImage})
+	err := cmd.Execute()
+	require.NotNil(t, err)
+	require.ErrorIs(t, err, ketchv1.ErrImageSignatureNotVerified)
+
+	cmd = newAppDeployCmd(cfg, params, "")
+	cmd.SetArgs([]string{"dashboard", "--framework", "production", "--image", signedImage})
+	require.Nil(t, cmd.Execute())
+	var app ketchv1.App
+	require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+	require.Equal(t, signedImage, app.Spec.Deployments[0].Image)
+	require.NotEmpty(t, app.Spec.Deployments[0].ImageDigest)
+}
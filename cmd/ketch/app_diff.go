@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+
+	"github.com/theketchio/ketch/cmd/ketch/output"
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+const appDiffHelp = `
+Show differences between objects of an app live in the cluster and manifests rendered the way ketch deploys the app.
+Only fields set in the rendered manifests are compared, fields set by kubernetes like defaults and status are left out.
+Objects are named KIND/NAME.
+
+  ketch app diff dashboard
+
+Show differences between rendered manifests of two deployment versions of an app, e.g. during a canary deployment:
+
+  ketch app diff dashboard --from-version 1 --to-version 2
+
+Use --output json to get a list of changed objects or templates with their diffs.
+`
+
+const (
+	appDiffOutputUnified = "unified"
+	appDiffOutputJSON    = "json"
+)
+
+type appDiffOptions struct {
+	appName        string
+	fromVersion    int
+	fromVersionSet bool
+	toVersion      int
+	toVersionSet   bool
+	output         string
+}
+
+func (o *appDiffOptions) setChanged(flags *pflag.FlagSet) {
+	o.fromVersionSet = flags.Changed("from-version")
+	o.toVersionSet = flags.Changed("to-version")
+}
+
+func newAppDiffCmd(cfg config, out io.Writer) *cobra.Command {
+	options := appDiffOptions{}
+	cmd := &cobra.Command{
+		Use:   "diff APPNAME",
+		Short: "Show differences between live and rendered manifests of an app.",
+		Long:  appDiffHelp,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			options.setChanged(cmd.Flags())
+			return appDiff(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().IntVar(&options.fromVersion, "from-version", 0, "deployment version to diff from")
+	cmd.Flags().IntVar(&options.toVersion, "to-version", 0, "deployment version to diff to")
+	cmd.Flags().StringVarP(&options.output, "output", "o", appDiffOutputUnified, "output format: unified or json")
+	return cmd
+}
+
+func appDiff(ctx context.Context, cfg config, options appDiffOptions, out io.Writer) error {
+	if options.output != appDiffOutputUnified && options.output != appDiffOutputJSON {
+		return ErrInvalidDiffOutput
+	}
+	if options.fromVersionSet != options.toVersionSet {
+		return ErrDiffVersionsRequired
+	}
+	var app ketchv1.App
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
+	var framework ketchv1.Framework
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: app.Spec.Framework}, &framework); err != nil {
+		return fmt.Errorf("failed to get framework: %w", err)
+	}
+
+	var diffs []fileDiff
+	var err error
+	if options.fromVersionSet {
+		diffs, err = appVersionsDiff(ctx, cfg, app, framework, options.fromVersion, options.toVersion)
+	} else {
+		diffs, err = appLiveDiff(ctx, cfg, app, framework)
+	}
+	if err != nil {
+		return err
+	}
+	if options.output == appDiffOutputJSON {
+		if diffs == nil {
+			diffs = []fileDiff{}
+		}
+		return output.Write(diffs, out, appDiffOutputJSON)
+	}
+	fmt.Fprint(out, joinFileDiffs(diffs))
+	return nil
+}
+
+// appLiveDiff returns diffs between objects of the app live in the cluster and the app's rendered manifests.
+func appLiveDiff(ctx context.Context, cfg config, app ketchv1.App, framework ketchv1.Framework) ([]fileDiff, error) {
+	manifests, err := appManifests(ctx, cfg, &app)
+	if err != nil {
+		return nil, err
+	}
+	desired, err := decodeManifests(manifests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode rendered manifests: %w", err)
+	}
+	desiredYamls := make(map[string]string, len(desired))
+	liveYamls := make(map[string]string, len(desired))
+	for _, obj := range desired {
+		name := fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName())
+		content, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return nil, err
+		}
+		desiredYamls[name] = string(content)
+
+		namespace := obj.GetNamespace()
+		if len(namespace) == 0 {
+			namespace = framework.Spec.NamespaceName
+		}
+		live := unstructured.Unstructured{}
+		live.SetGroupVersionKind(obj.GroupVersionKind())
+		err = cfg.Client().Get(ctx, types.NamespacedName{Name: obj.GetName(), Namespace: namespace}, &live)
+		if k8sErrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s: %w", name, err)
+		}
+		content, err = yaml.Marshal(projectOnto(obj.Object, live.Object))
+		if err != nil {
+			return nil, err
+		}
+		liveYamls[name] = string(content)
+	}
+	return fileDiffs(liveYamls, desiredYamls, "live/", "desired/")
+}
+
+// appVersionsDiff returns diffs between rendered manifests of two deployment versions of the app.
+func appVersionsDiff(ctx context.Context, cfg config, app ketchv1.App, framework ketchv1.Framework, fromVersion, toVersion int) ([]fileDiff, error) {
+	from, err := renderAppVersion(ctx, cfg, app, framework, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	to, err := renderAppVersion(ctx, cfg, app, framework, toVersion)
+	if err != nil {
+		return nil, err
+	}
+	return fileDiffs(from, to, fmt.Sprintf("version-%d/", fromVersion), fmt.Sprintf("version-%d/", toVersion))
+}
+
+// renderAppVersion renders manifests of the app as if the deployment version was the only one receiving traffic.
+func renderAppVersion(ctx context.Context, cfg config, app ketchv1.App, framework ketchv1.Framework, version int) (map[string]string, error) {
+	for _, deployment := range app.Spec.Deployments {
+		if deployment.Version != ketchv1.DeploymentVersion(version) {
+			continue
+		}
+		versioned := app.DeepCopy()
+		deployment.RoutingSettings.Weight = 100
+		versioned.Spec.Deployments = []ketchv1.AppDeploymentSpec{deployment}
+		versioned.Spec.Canary = ketchv1.CanarySpec{}
+		manifests, err := renderApp(ctx, cfg, versioned, framework)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render version %d: %w", version, err)
+		}
+		return manifests, nil
+	}
+	return nil, fmt.Errorf("%w: %d", ErrDeploymentVersionNotFound, version)
+}
+
+// decodeManifests decodes a multi-document yaml into objects, empty documents are skipped.
+func decodeManifests(manifests []byte) ([]unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(manifests), 4096)
+	var objects []unstructured.Unstructured
+	for {
+		var obj map[string]interface{}
+		if err := decoder.Decode(&obj); err != nil {
+			if err == io.EOF {
+				return objects, nil
+			}
+			return nil, err
+		}
+		if len(obj) == 0 {
+			continue
+		}
+		objects = append(objects, unstructured.Unstructured{Object: obj})
+	}
+}
+
+// projectOnto returns fields of live that are set in desired.
+// Lists are compared as a whole, so items added to a live list are kept.
+func projectOnto(desired, live interface{}) interface{} {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+		projected := make(map[string]interface{}, len(d))
+		for key, value := range d {
+			if liveValue, ok := l[key]; ok {
+				projected[key] = projectOnto(value, liveValue)
+			}
+		}
+		return projected
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok {
+			return live
+		}
+		projected := make([]interface{}, len(l))
+		for i := range l {
+			projected[i] = l[i]
+			if i < len(d) {
+				projected[i] = projectOnto(d[i], l[i])
+			}
+		}
+		return projected
+	}
+	return live
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/utils/conversions"
+)
+
+func Test_appDiff(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-gke",
+			IngressController: ketchv1.IngressControllerSpec{
+				IngressType: ketchv1.TraefikIngressControllerType,
+			},
+		},
+		Status: ketchv1.FrameworkStatus{Namespace: &v1.ObjectReference{Name: "ketch-gke"}},
+	}
+	deployment := func(image string, version ketchv1.DeploymentVersion, weight uint8) ketchv1.AppDeploymentSpec {
+		return ketchv1.AppDeploymentSpec{
+			Image:   image,
+			Version: version,
+			Processes: []ketchv1.ProcessSpec{
+				{Name: "web", Units: conversions.IntPtr(1), Cmd: []string{"/app"}},
+			},
+			RoutingSettings: ketchv1.RoutingSettings{Weight: weight},
+		}
+	}
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			Framework:        "gke",
+			DeploymentsCount: 2,
+			Deployments: []ketchv1.AppDeploymentSpec{
+				deployment("shipasoftware/dashboard:v1", 1, 70),
+				deployment("shipasoftware/dashboard:v2", 2, 30),
+			},
+		},
+	}
+	replicas := int32(3)
+	liveDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard-web-1", Namespace: "ketch-gke"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+		},
+	}
+	tests := []struct {
+		name            string
+		options         appDiffOptions
+		wantContains    []string
+		wantNotContains []string
+		wantErr         error
+	}{
+		{
+			name:    "live objects",
+			options: appDiffOptions{appName: "dashboard", output: appDiffOutputUnified},
+			wantContains: []string{
+				"--- live/Deployment/dashboard-web-1",
+				"+++ desired/Deployment/dashboard-web-1",
+				"-  replicas: 3",
+				"+  replicas: 1",
+				"--- /dev/null\n+++ desired/Deployment/dashboard-web-2",
+			},
+		},
+		{
+			name: "deployment versions",
+			options: appDiffOptions{
+				appName:        "dashboard",
+				fromVersion:    1,
+				fromVersionSet: true,
+				toVersion:      2,
+				toVersionSet:   true,
+				output:         appDiffOutputUnified,
+			},
+			wantContains: []string{
+				"--- version-1/",
+				"+++ version-2/",
+				"-          image: shipasoftware/dashboard:v1",
+				"+          image: shipasoftware/dashboard:v2",
+			},
+			wantNotContains: []string{"live/"},
+		},
+		{
+			name: "missing deployment version",
+			options: appDiffOptions{
+				appName:        "dashboard",
+				fromVersion:    1,
+				fromVersionSet: true,
+				toVersion:      3,
+				toVersionSet:   true,
+				output:         appDiffOutputUnified,
+			},
+			wantErr: ErrDeploymentVersionNotFound,
+		},
+		{
+			name:    "one deployment version",
+			options: appDiffOptions{appName: "dashboard", fromVersion: 1, fromVersionSet: true, output: appDiffOutputUnified},
+			wantErr: ErrDiffVersionsRequired,
+		},
+		{
+			name:    "invalid output",
+			options: appDiffOptions{appName: "dashboard", output: "yaml"},
+			wantErr: ErrInvalidDiffOutput,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{gke, dashboard, liveDeployment},
+			}
+			out := &bytes.Buffer{}
+			err := appDiff(context.Background(), cfg, tt.options, out)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.Nil(t, err)
+			for _, want := range tt.wantContains {
+				require.Contains(t, out.String(), want)
+			}
+			for _, notWant := range tt.wantNotContains {
+				require.NotContains(t, out.String(), notWant)
+			}
+		})
+	}
+}
+
+func Test_appDiffJSON(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-gke",
+			IngressController: ketchv1.IngressControllerSpec{
+				IngressType: ketchv1.TraefikIngressControllerType,
+			},
+		},
+	}
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			Framework:        "gke",
+			DeploymentsCount: 1,
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Image:           "shipasoftware/dashboard:v1",
+					Version:         1,
+					Processes:       []ketchv1.ProcessSpec{{Name: "web", Units: conversions.IntPtr(1), Cmd: []string{"/app"}}},
+					RoutingSettings: ketchv1.RoutingSettings{Weight: 100},
+				},
+			},
+		},
+	}
+	cfg := &mocks.Configuration{
+		CtrlClientObjects: []runtime.Object{gke, dashboard},
+	}
+	out := &bytes.Buffer{}
+	options := appDiffOptions{appName: "dashboard", output: appDiffOutputJSON}
+	require.Nil(t, appDiff(context.Background(), cfg, options, out))
+
+	var diffs []fileDiff
+	require.Nil(t, json.Unmarshal(out.Bytes(), &diffs))
+	require.NotEmpty(t, diffs)
+	for _, diff := range diffs {
+		require.Equal(t, fileDiffAdded, diff.Status)
+		require.True(t, strings.HasPrefix(diff.Diff, "--- /dev/null"), diff.Diff)
+	}
+}
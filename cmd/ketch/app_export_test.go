@@ -68,6 +68,18 @@ func (m mockStorage) Update(name string, templates templates.Templates) error {
 	panic("implement me")
 }
 
+func (m mockStorage) Reset(name string, defaults templates.Templates) error {
+	panic("implement me")
+}
+
+func (m mockStorage) Delete(name string) error {
+	panic("implement me")
+}
+
+func (m mockStorage) Modified(name string) (bool, error) {
+	panic("implement me")
+}
+
 var _ templates.Client = &mockStorage{}
 
 func Test_appExport(t *testing.T) {
@@ -38,6 +38,12 @@ Address: {{ $address }}
 {{- else }}
 The default cname hasn't assigned yet because "{{ .App.Spec.Framework }}" framework doesn't have ingress service endpoint.
 {{- end }}
+{{- range .App.Status.InternalServices }}
+Internal address: {{ .Host }}:{{ .Port }} ({{ .Process }})
+{{- end }}
+{{- if .App.Status.TemplatesRevision }}
+Templates revision: {{ .App.Status.TemplatesRevision }}
+{{- end }}
 {{- if .App.Spec.DockerRegistry.SecretName }}
 Secret name to pull application's images: {{ .App.Spec.DockerRegistry.SecretName }}
 {{- end }}
@@ -66,6 +72,7 @@ type appInfoOutput struct {
 type deploymentOutput struct {
 	DeploymentVersion string `json:"deploymentVersion" yaml:"deploymentVersion"`
 	Image             string `json:"image" yaml:"image"`
+	Digest            string `json:"digest" yaml:"digest"`
 	ProcessName       string `json:"processName" yaml:"processName"`
 	Weight            string `json:"weight" yaml:"weight"`
 	State             string `json:"state" yaml:"state"`
@@ -137,6 +144,7 @@ func generateAppInfoOutput(app ketchv1.App, appPods *v1.PodList, framework *ketc
 			deployments = append(deployments, deploymentOutput{
 				DeploymentVersion: deployment.Version.String(),
 				Image:             deployment.Image,
+				Digest:            deployment.ImageDigest,
 				ProcessName:       process.Name,
 				Weight:            fmt.Sprintf("%v%%", deployment.RoutingSettings.Weight),
 				State:             state,
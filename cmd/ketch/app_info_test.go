@@ -45,8 +45,9 @@ func Test_appInfo(t *testing.T) {
 		Spec: ketchv1.AppSpec{
 			Deployments: []ketchv1.AppDeploymentSpec{
 				{
-					Version: 1,
-					Image:   "shipasoftware/go-app:v1",
+					Version:     1,
+					Image:       "shipasoftware/go-app:v1",
+					ImageDigest: "sha256:3b1c2c6d1a6f8e3a2b7c9d0e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e2f3a",
 					Processes: []ketchv1.ProcessSpec{
 						{
 							Name: "web",
@@ -68,6 +69,11 @@ func Test_appInfo(t *testing.T) {
 				GenerateDefaultCname: true,
 			},
 		},
+		Status: ketchv1.AppStatus{
+			InternalServices: []ketchv1.InternalService{
+				{Process: "worker", Host: "go-app-worker.ketch-aws.svc.cluster.local", Port: 9090, Published: true},
+			},
+		},
 	}
 	goAppWithSecretName := &ketchv1.App{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+const appMoveHelp = `
+Move an application to another framework.
+Secrets used by the application (docker registry, cname certificates and service bindings) are copied to the namespace of the new framework.
+The application is removed from the old framework once it is ready in the new one.
+`
+
+func newAppMoveCmd(cfg config, out io.Writer) *cobra.Command {
+	options := appMoveOptions{}
+	cmd := &cobra.Command{
+		Use:   "move APPNAME",
+		Args:  cobra.ExactArgs(1),
+		Short: "Move an application to another framework.",
+		Long:  appMoveHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			return appMove(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().StringVar(&options.framework, "to-framework", "", "The framework to move the application to.")
+	cmd.MarkFlagRequired("to-framework")
+	cmd.RegisterFlagCompletionFunc("to-framework", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return autoCompleteFrameworkNames(cfg, toComplete)
+	})
+	return cmd
+}
+
+type appMoveOptions struct {
+	appName   string
+	framework string
+}
+
+func appMove(ctx context.Context, cfg config, options appMoveOptions, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get the app: %w", err)
+	}
+	if app.Spec.Framework == options.framework {
+		return ErrAppAlreadyInFramework
+	}
+	var source, target ketchv1.Framework
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: app.Spec.Framework}, &source); err != nil {
+		return fmt.Errorf("failed to get the framework: %w", err)
+	}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.framework}, &target); err != nil {
+		return fmt.Errorf("failed to get the framework: %w", err)
+	}
+	if err := moveApp(ctx, cfg, &app, source, target); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Successfully moved! The app will be removed from %q framework once it is ready in %q.\n", source.Name, target.Name)
+	return nil
+}
+
+// moveApp copies the app's secrets to the target framework's namespace and assigns the app to the target framework.
+func moveApp(ctx context.Context, cfg config, app *ketchv1.App, source, target ketchv1.Framework) error {
+	for _, name := range appSecretNames(*app) {
+		if err := copySecret(ctx, cfg, name, source.Spec.NamespaceName, target.Spec.NamespaceName); err != nil {
+			return err
+		}
+	}
+	app.Spec.Framework = target.Name
+	if err := cfg.Client().Update(ctx, app); err != nil {
+		return fmt.Errorf("failed to update the app: %w", err)
+	}
+	return nil
+}
+
+// appSecretNames returns names of secrets in the framework's namespace used by the app.
+func appSecretNames(app ketchv1.App) []string {
+	var names []string
+	seen := map[string]bool{}
+	add := func(name string) {
+		if len(name) == 0 || seen[name] {
+			return
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	add(app.Spec.DockerRegistry.SecretName)
+	for _, cname := range app.Spec.Ingress.Cnames {
+		add(cname.SecretName)
+	}
+	for _, binding := range app.Spec.ServiceBindings {
+		add(binding.SecretName)
+	}
+	return names
+}
+
+// copySecret copies the secret to the target namespace unless a secret with the same name already exists there.
+// Only the data and labels owned by ketch are copied, metadata of other tools like helm's ownership annotations stays behind.
+func copySecret(ctx context.Context, cfg config, name, sourceNamespace, targetNamespace string) error {
+	secrets := cfg.KubernetesClient().CoreV1().Secrets(targetNamespace)
+	_, err := secrets.Get(ctx, name, metav1.GetOptions{})
+	if err == nil {
+		return nil
+	}
+	if !k8sErrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get the secret: %w", err)
+	}
+	secret, err := cfg.KubernetesClient().CoreV1().Secrets(sourceNamespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get the secret: %w", err)
+	}
+	copied := v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secret.Name,
+			Namespace: targetNamespace,
+			Labels:    ketchLabels(secret.Labels),
+		},
+		Type: secret.Type,
+		Data: secret.Data,
+	}
+	if _, err := secrets.Create(ctx, &copied, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to copy the secret: %w", err)
+	}
+	return nil
+}
+
+// ketchLabels returns labels prefixed with ketch's group.
+func ketchLabels(labels map[string]string) map[string]string {
+	var result map[string]string
+	for key, value := range labels {
+		if !strings.HasPrefix(key, ketchv1.Group+"/") {
+			continue
+		}
+		if result == nil {
+			result = map[string]string{}
+		}
+		result[key] = value
+	}
+	return result
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+func Test_appMove(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+	}
+	aws := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "aws"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-aws"},
+	}
+	app := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			Framework:       "gke",
+			DockerRegistry:  ketchv1.DockerRegistrySpec{SecretName: "registry"},
+			Ingress:         ketchv1.IngressSpec{Cnames: ketchv1.CnameList{{Name: "theketch.io", Secure: true, SecretName: "cert"}}},
+			ServiceBindings: []ketchv1.ServiceBinding{{Name: "db", SecretName: "postgres"}},
+		},
+	}
+	newSecret := func(name, namespace string, value string) *v1.Secret {
+		return &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   namespace,
+				Labels:      map[string]string{"theketch.io/secret-owner": namespace, "app.kubernetes.io/managed-by": "Helm"},
+				Annotations: map[string]string{"meta.helm.sh/release-name": "secrets"},
+			},
+			Type: v1.SecretTypeOpaque,
+			Data: map[string][]byte{"key": []byte(value)},
+		}
+	}
+	tests := []struct {
+		name        string
+		cfg         config
+		options     appMoveOptions
+		wantSecrets map[string]string
+		wantLabels  map[string]map[string]string
+		wantOut     string
+		wantErr     string
+	}{
+		{
+			name: "move app and copy its secrets",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{gke, aws, app},
+				KubeClientObjects: []runtime.Object{
+					newSecret("registry", "ketch-gke", "gke-registry"),
+					newSecret("cert", "ketch-gke", "gke-cert"),
+					newSecret("postgres", "ketch-gke", "gke-postgres"),
+					newSecret("postgres", "ketch-aws", "aws-postgres"),
+				},
+			},
+			options: appMoveOptions{appName: "dashboard", framework: "aws"},
+			wantSecrets: map[string]string{
+				"registry": "gke-registry",
+				"cert":     "gke-cert",
+				"postgres": "aws-postgres",
+			},
+			wantLabels: map[string]map[string]string{
+				"registry": {"theketch.io/secret-owner": "ketch-gke"},
+				"cert":     {"theketch.io/secret-owner": "ketch-gke"},
+				"postgres": {"theketch.io/secret-owner": "ketch-aws", "app.kubernetes.io/managed-by": "Helm"},
+			},
+			wantOut: "Successfully moved! The app will be removed from \"gke\" framework once it is ready in \"aws\".\n",
+		},
+		{
+			name: "app is already in the framework",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{gke, aws, app},
+			},
+			options: appMoveOptions{appName: "dashboard", framework: "gke"},
+			wantErr: ErrAppAlreadyInFramework.Error(),
+		},
+		{
+			name: "framework not found",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{gke, app},
+			},
+			options: appMoveOptions{appName: "dashboard", framework: "aws"},
+			wantErr: `failed to get the framework: frameworks.theketch.io "aws" not found`,
+		},
+		{
+			name: "secret not found",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{gke, aws, app},
+			},
+			options: appMoveOptions{appName: "dashboard", framework: "aws"},
+			wantErr: `failed to get the secret: secrets "registry" not found`,
+		},
+		{
+			name: "app not found",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{gke, aws},
+			},
+			options: appMoveOptions{appName: "dashboard", framework: "aws"},
+			wantErr: `failed to get the app: apps.theketch.io "dashboard" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := appMove(context.Background(), tt.cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantOut, out.String())
+
+			gotApp := ketchv1.App{}
+			err = tt.cfg.Client().Get(context.Background(), types.NamespacedName{Name: tt.options.appName}, &gotApp)
+			require.Nil(t, err)
+			require.Equal(t, tt.options.framework, gotApp.Spec.Framework)
+			for name, value := range tt.wantSecrets {
+				secret, err := tt.cfg.KubernetesClient().CoreV1().Secrets("ketch-aws").Get(context.Background(), name, metav1.GetOptions{})
+				require.Nil(t, err)
+				require.Equal(t, value, string(secret.Data["key"]))
+				require.Equal(t, tt.wantLabels[name], secret.Labels)
+			}
+		})
+	}
+}
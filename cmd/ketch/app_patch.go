@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/chart"
+	"github.com/theketchio/ketch/internal/deploy"
+)
+
+const appPatchHelp = `
+Manage kustomize post-render patches applied to manifests of apps.
+
+A patch added with --app is applied only to the app.
+A patch added with --framework is applied to apps of the framework matching --selector or to all apps of the framework.
+Patches are stored as configmaps in the namespace of the framework. They aren't applied to jobs.
+Apps a patch applies to are rendered again once the patch is added or removed.
+Configmaps named "*-postrender" created without this command are merged into one kustomization applied to all apps and jobs of the namespace.
+`
+
+func newAppPatchCmd(cfg config, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "patch",
+		Short: "Manage post-render patches of applications",
+		Long:  appPatchHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Usage()
+		},
+	}
+	cmd.AddCommand(newAppPatchAddCmd(cfg, out))
+	cmd.AddCommand(newAppPatchListCmd(cfg, out))
+	cmd.AddCommand(newAppPatchRemoveCmd(cfg, out))
+	return cmd
+}
+
+// appPatchScopeOptions selects apps patches are applied to, it's shared by all "app patch" subcommands.
+type appPatchScopeOptions struct {
+	appName   string
+	framework string
+}
+
+func (o *appPatchScopeOptions) addFlags(cmd *cobra.Command, cfg config) {
+	flags := cmd.Flags()
+	flags.StringVarP(&o.appName, deploy.FlagApp, deploy.FlagAppShort, "", "patches of the app")
+	flags.StringVar(&o.framework, "framework", "", "patches of apps of the framework")
+	cmd.RegisterFlagCompletionFunc(deploy.FlagApp, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return autoCompleteAppNames(cfg, toComplete)
+	})
+}
+
+// appPatchScope is a set of apps patches are applied to.
+type appPatchScope struct {
+	// app is set if patches are applied to the app only.
+	app       *ketchv1.App
+	framework ketchv1.Framework
+}
+
+func (o appPatchScopeOptions) resolve(ctx context.Context, cfg config) (*appPatchScope, error) {
+	if (len(o.appName) > 0) == (len(o.framework) > 0) {
+		return nil, ErrPatchScopeRequired
+	}
+	scope := appPatchScope{}
+	frameworkName := o.framework
+	if len(o.appName) > 0 {
+		var app ketchv1.App
+		if err := cfg.Client().Get(ctx, types.NamespacedName{Name: o.appName}, &app); err != nil {
+			return nil, fmt.Errorf("failed to get app: %w", err)
+		}
+		scope.app = &app
+		frameworkName = app.Spec.Framework
+	}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: frameworkName}, &scope.framework); err != nil {
+		return nil, fmt.Errorf("failed to get framework: %w", err)
+	}
+	return &scope, nil
+}
+
+func (s appPatchScope) namespace() string {
+	return s.framework.Spec.NamespaceName
+}
+
+// configMapName returns the name of the configmap storing the patch.
+func (s appPatchScope) configMapName(patch string) string {
+	if s.app != nil {
+		return fmt.Sprintf("%s-%s%s", s.app.Name, patch, chart.PostRenderSuffix)
+	}
+	return patch + chart.PostRenderSuffix
+}
+
+// rerenderPatchedApps annotates apps of the framework the configmaps apply to.
+// The app controller doesn't watch post-render configmaps, updating the apps makes it render them again.
+func rerenderPatchedApps(ctx context.Context, cfg config, framework ketchv1.Framework, configMaps ...v1.ConfigMap) error {
+	apps := ketchv1.AppList{}
+	if err := cfg.Client().List(ctx, &apps); err != nil {
+		return fmt.Errorf("failed to get list of apps: %w", err)
+	}
+	patchedAt := time.Now().UTC().Format(time.RFC3339Nano)
+	for i := range apps.Items {
+		app := &apps.Items[i]
+		if app.Spec.Framework != framework.Name {
+			continue
+		}
+		patched := false
+		for _, configMap := range configMaps {
+			applies, err := chart.PostRenderPatchApplies(configMap, app.Name, app.Labels)
+			if err != nil {
+				return err
+			}
+			patched = patched || applies
+		}
+		if !patched {
+			continue
+		}
+		if app.Annotations == nil {
+			app.Annotations = map[string]string{}
+		}
+		app.Annotations[chart.PostRenderPatchedAtAnnotation()] = patchedAt
+		if err := cfg.Client().Update(ctx, app); err != nil {
+			return fmt.Errorf("failed to update app %q: %w", app.Name, err)
+		}
+	}
+	return nil
+}
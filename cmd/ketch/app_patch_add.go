@@ -0,0 +1,220 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	kTypes "sigs.k8s.io/kustomize/api/types"
+	"sigs.k8s.io/kustomize/kyaml/resid"
+	"sigs.k8s.io/yaml"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/chart"
+	"github.com/theketchio/ketch/internal/validation"
+)
+
+const appPatchAddHelp = `
+Add a kustomize post-render patch to apps.
+
+Files given with --file are stored with the patch. If none of them is kustomization.yaml,
+a kustomization applying every file as a patch is generated, --target-kind and --target-name select resources to patch.
+A kustomization must list app.yaml, the rendered manifests of an app, as its resource.
+Before the patch is stored, it is applied to the rendered manifests of the apps it patches.
+Once stored, the apps are rendered again with the patch.
+
+  ketch app patch add node-affinity --app dashboard --file affinity.yaml --target-kind Deployment
+  ketch app patch add node-affinity --framework team-a --selector tier=web --file affinity.yaml --target-kind Deployment
+  ketch app patch add custom --app dashboard --file kustomization.yaml --file patch.yaml
+`
+
+const kustomizationFile = "kustomization.yaml"
+
+type appPatchAddOptions struct {
+	scope      appPatchScopeOptions
+	name       string
+	selector   string
+	files      []string
+	targetKind string
+	targetName string
+}
+
+func newAppPatchAddCmd(cfg config, out io.Writer) *cobra.Command {
+	options := appPatchAddOptions{}
+	cmd := &cobra.Command{
+		Use:   "add PATCH",
+		Short: "Add a post-render patch to apps.",
+		Long:  appPatchAddHelp,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.name = args[0]
+			return appPatchAdd(cmd.Context(), cfg, options, out)
+		},
+	}
+	options.scope.addFlags(cmd, cfg)
+	cmd.Flags().StringVar(&options.selector, "selector", "", "label selector of apps of the framework to patch, e.g. tier=web")
+	cmd.Flags().StringArrayVarP(&options.files, "file", "f", nil, "file of the patch, can be specified multiple times")
+	cmd.Flags().StringVar(&options.targetKind, "target-kind", "", "kind of resources to apply a generated patch to")
+	cmd.Flags().StringVar(&options.targetName, "target-name", "", "regular expression matching names of resources to apply a generated patch to")
+	cmd.MarkFlagRequired("file")
+	return cmd
+}
+
+func appPatchAdd(ctx context.Context, cfg config, options appPatchAddOptions, out io.Writer) error {
+	if !validation.ValidateName(options.name) {
+		return ErrInvalidPatchName
+	}
+	scope, err := options.scope.resolve(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if len(options.selector) > 0 {
+		if scope.app != nil {
+			return ErrSelectorRequiresFramework
+		}
+		if _, err := labels.Parse(options.selector); err != nil {
+			return fmt.Errorf("invalid label selector: %w", err)
+		}
+	}
+	data, err := readPatchFiles(options.files, options.targetKind, options.targetName)
+	if err != nil {
+		return err
+	}
+
+	configMap := v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      scope.configMapName(options.name),
+			Namespace: scope.namespace(),
+			Labels:    map[string]string{chart.PostRenderPatchLabel(): options.name},
+		},
+		Data: data,
+	}
+	if scope.app != nil {
+		configMap.Labels[chart.PostRenderAppLabel()] = scope.app.Name
+	}
+	if len(options.selector) > 0 {
+		configMap.Annotations = map[string]string{chart.PostRenderSelectorAnnotation(): options.selector}
+	}
+	if err := validatePatch(ctx, cfg, scope.framework, configMap); err != nil {
+		return err
+	}
+
+	// apps patched by a previous version of the patch are rendered again as well, its selector may differ.
+	patched := []v1.ConfigMap{configMap}
+	var existing v1.ConfigMap
+	err = cfg.Client().Get(ctx, types.NamespacedName{Name: configMap.Name, Namespace: configMap.Namespace}, &existing)
+	switch {
+	case k8sErrors.IsNotFound(err):
+		if err := cfg.Client().Create(ctx, &configMap); err != nil {
+			return fmt.Errorf("failed to add patch: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to get patch: %w", err)
+	default:
+		// names of app and framework patches may collide, e.g. patch "b-c" of app "a" and framework patch "a-b-c".
+		if existing.Labels[chart.PostRenderAppLabel()] != configMap.Labels[chart.PostRenderAppLabel()] ||
+			existing.Labels[chart.PostRenderPatchLabel()] != configMap.Labels[chart.PostRenderPatchLabel()] {
+			return fmt.Errorf("%w: %q", ErrPatchNameConflict, existing.Name)
+		}
+		patched = append(patched, *existing.DeepCopy())
+		existing.Labels = configMap.Labels
+		existing.Annotations = configMap.Annotations
+		existing.Data = configMap.Data
+		if err := cfg.Client().Update(ctx, &existing); err != nil {
+			return fmt.Errorf("failed to update patch: %w", err)
+		}
+	}
+	if err := rerenderPatchedApps(ctx, cfg, scope.framework, patched...); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "Successfully added!")
+	return nil
+}
+
+// readPatchFiles reads files of a patch and generates a kustomization for them if they don't have one.
+func readPatchFiles(paths []string, targetKind, targetName string) (map[string]string, error) {
+	data := map[string]string{}
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.Base(path)
+		if name == chart.PostRenderManifestsFile {
+			return nil, fmt.Errorf("%w: %s", ErrReservedPatchFile, name)
+		}
+		data[name] = string(content)
+	}
+	if len(data) == 0 {
+		return nil, ErrNoPatchFiles
+	}
+	if _, ok := data[kustomizationFile]; ok {
+		return data, nil
+	}
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	kustomization := kTypes.Kustomization{Resources: []string{chart.PostRenderManifestsFile}}
+	for _, name := range names {
+		patch := kTypes.Patch{Path: name}
+		if len(targetKind) > 0 || len(targetName) > 0 {
+			patch.Target = &kTypes.Selector{ResId: resid.ResId{Gvk: resid.Gvk{Kind: targetKind}, Name: targetName}}
+		}
+		kustomization.Patches = append(kustomization.Patches, patch)
+	}
+	content, err := yaml.Marshal(kustomization)
+	if err != nil {
+		return nil, err
+	}
+	data[kustomizationFile] = string(content)
+	return data, nil
+}
+
+// validatePatch applies the patch to the rendered manifests of apps of the framework it patches.
+// If the patch doesn't apply to any app yet, the kustomization is built without manifests.
+func validatePatch(ctx context.Context, cfg config, framework ketchv1.Framework, configMap v1.ConfigMap) error {
+	apps := ketchv1.AppList{}
+	if err := cfg.Client().List(ctx, &apps); err != nil {
+		return fmt.Errorf("failed to get list of apps: %w", err)
+	}
+	sort.Slice(apps.Items, func(i, j int) bool { return apps.Items[i].Name < apps.Items[j].Name })
+	validated := false
+	for i := range apps.Items {
+		app := &apps.Items[i]
+		if app.Spec.Framework != framework.Name {
+			continue
+		}
+		applies, err := chart.PostRenderPatchApplies(configMap, app.Name, app.Labels)
+		if err != nil {
+			return err
+		}
+		if !applies {
+			continue
+		}
+		manifests, err := renderApp(ctx, cfg, app, framework)
+		if err != nil {
+			return fmt.Errorf("failed to render app %q: %w", app.Name, err)
+		}
+		if _, err := chart.Kustomize(joinManifests(manifests), configMap.Data); err != nil {
+			return fmt.Errorf("%w to app %q: %v", ErrPatchNotApplied, app.Name, err)
+		}
+		validated = true
+	}
+	if !validated {
+		if _, err := chart.Kustomize(nil, configMap.Data); err != nil {
+			return fmt.Errorf("%w: %v", ErrPatchNotApplied, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/chart"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+const affinityPatch = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: any
+spec:
+  template:
+    spec:
+      nodeSelector:
+        pool: web
+`
+
+func Test_appPatchAdd(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-gke",
+			IngressController: ketchv1.IngressControllerSpec{
+				IngressType: ketchv1.TraefikIngressControllerType,
+			},
+		},
+	}
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard", Labels: map[string]string{"tier": "web"}},
+		Spec:       ketchv1.AppSpec{Framework: "gke"},
+	}
+	resourcesPatch := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dashboard-resources-postrender",
+			Namespace: "ketch-gke",
+			Labels:    map[string]string{chart.PostRenderPatchLabel(): "resources", chart.PostRenderAppLabel(): "dashboard"},
+		},
+	}
+	generatedKustomization := `patches:
+- path: affinity.yaml
+  target:
+    kind: Deployment
+resources:
+- app.yaml
+`
+	tests := []struct {
+		name          string
+		options       appPatchAddOptions
+		files         map[string]string
+		existing      *v1.ConfigMap
+		wantConfigMap *v1.ConfigMap
+		wantRerender  bool
+		wantErr       error
+	}{
+		{
+			name: "patch of an app",
+			options: appPatchAddOptions{
+				scope:      appPatchScopeOptions{appName: "dashboard"},
+				name:       "affinity",
+				targetKind: "Deployment",
+			},
+			files: map[string]string{"affinity.yaml": affinityPatch},
+			wantConfigMap: &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "dashboard-affinity-postrender",
+					Namespace: "ketch-gke",
+					Labels:    map[string]string{chart.PostRenderPatchLabel(): "affinity", chart.PostRenderAppLabel(): "dashboard"},
+				},
+				Data: map[string]string{"affinity.yaml": affinityPatch, "kustomization.yaml": generatedKustomization},
+			},
+			wantRerender: true,
+		},
+		{
+			name: "patch of apps with matching labels",
+			options: appPatchAddOptions{
+				scope:      appPatchScopeOptions{framework: "gke"},
+				name:       "affinity",
+				selector:   "tier=web",
+				targetKind: "Deployment",
+			},
+			files: map[string]string{"affinity.yaml": affinityPatch},
+			wantConfigMap: &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "affinity-postrender",
+					Namespace:   "ketch-gke",
+					Labels:      map[string]string{chart.PostRenderPatchLabel(): "affinity"},
+					Annotations: map[string]string{chart.PostRenderSelectorAnnotation(): "tier=web"},
+				},
+				Data: map[string]string{"affinity.yaml": affinityPatch, "kustomization.yaml": generatedKustomization},
+			},
+			wantRerender: true,
+		},
+		{
+			name: "patch no longer applying to an app",
+			options: appPatchAddOptions{
+				scope:      appPatchScopeOptions{framework: "gke"},
+				name:       "affinity",
+				selector:   "tier=worker",
+				targetKind: "Deployment",
+			},
+			files: map[string]string{"affinity.yaml": affinityPatch},
+			existing: &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "affinity-postrender",
+					Namespace:   "ketch-gke",
+					Labels:      map[string]string{chart.PostRenderPatchLabel(): "affinity"},
+					Annotations: map[string]string{chart.PostRenderSelectorAnnotation(): "tier=web"},
+				},
+			},
+			wantConfigMap: &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "affinity-postrender",
+					Namespace:   "ketch-gke",
+					Labels:      map[string]string{chart.PostRenderPatchLabel(): "affinity"},
+					Annotations: map[string]string{chart.PostRenderSelectorAnnotation(): "tier=worker"},
+				},
+				Data: map[string]string{"affinity.yaml": affinityPatch, "kustomization.yaml": generatedKustomization},
+			},
+			wantRerender: true,
+		},
+		{
+			name: "kustomization can't be built",
+			options: appPatchAddOptions{
+				scope: appPatchScopeOptions{appName: "dashboard"},
+				name:  "broken",
+			},
+			files:   map[string]string{"kustomization.yaml": "resources:\n- app.yaml\npatches:\n- path: missing.yaml\n"},
+			wantErr: ErrPatchNotApplied,
+		},
+		{
+			name: "kustomization of a patch that doesn't apply to any app can't be built",
+			options: appPatchAddOptions{
+				scope:    appPatchScopeOptions{framework: "gke"},
+				name:     "broken",
+				selector: "tier=worker",
+			},
+			files:   map[string]string{"kustomization.yaml": "resources:\n- app.yaml\npatches:\n- path: missing.yaml\n"},
+			wantErr: ErrPatchNotApplied,
+		},
+		{
+			name: "patch that doesn't apply to any app",
+			options: appPatchAddOptions{
+				scope:      appPatchScopeOptions{framework: "gke"},
+				name:       "affinity",
+				selector:   "tier=worker",
+				targetKind: "Deployment",
+			},
+			files: map[string]string{"affinity.yaml": affinityPatch},
+			wantConfigMap: &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "affinity-postrender",
+					Namespace:   "ketch-gke",
+					Labels:      map[string]string{chart.PostRenderPatchLabel(): "affinity"},
+					Annotations: map[string]string{chart.PostRenderSelectorAnnotation(): "tier=worker"},
+				},
+				Data: map[string]string{"affinity.yaml": affinityPatch, "kustomization.yaml": generatedKustomization},
+			},
+		},
+		{
+			name: "framework patch with the name of an app's patch",
+			options: appPatchAddOptions{
+				scope:      appPatchScopeOptions{framework: "gke"},
+				name:       "dashboard-resources",
+				targetKind: "Deployment",
+			},
+			files:   map[string]string{"affinity.yaml": affinityPatch},
+			wantErr: ErrPatchNameConflict,
+		},
+		{
+			name: "selector of an app's patch",
+			options: appPatchAddOptions{
+				scope:    appPatchScopeOptions{appName: "dashboard"},
+				name:     "affinity",
+				selector: "tier=web",
+			},
+			files:   map[string]string{"affinity.yaml": affinityPatch},
+			wantErr: ErrSelectorRequiresFramework,
+		},
+		{
+			name: "reserved file name",
+			options: appPatchAddOptions{
+				scope: appPatchScopeOptions{appName: "dashboard"},
+				name:  "affinity",
+			},
+			files:   map[string]string{"app.yaml": affinityPatch},
+			wantErr: ErrReservedPatchFile,
+		},
+		{
+			name: "no scope",
+			options: appPatchAddOptions{
+				name: "affinity",
+			},
+			files:   map[string]string{"affinity.yaml": affinityPatch},
+			wantErr: ErrPatchScopeRequired,
+		},
+		{
+			name: "invalid name",
+			options: appPatchAddOptions{
+				scope: appPatchScopeOptions{appName: "dashboard"},
+				name:  "Affinity",
+			},
+			files:   map[string]string{"affinity.yaml": affinityPatch},
+			wantErr: ErrInvalidPatchName,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := []runtime.Object{gke, dashboard, resourcesPatch}
+			if tt.existing != nil {
+				objects = append(objects, tt.existing)
+			}
+			cfg := &mocks.Configuration{CtrlClientObjects: objects}
+			dir := writeTemplates(t, tt.files)
+			for name := range tt.files {
+				tt.options.files = append(tt.options.files, dir+"/"+name)
+			}
+			out := &bytes.Buffer{}
+			err := appPatchAdd(context.Background(), cfg, tt.options, out)
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "Successfully added!\n", out.String())
+			var got v1.ConfigMap
+			key := types.NamespacedName{Name: tt.wantConfigMap.Name, Namespace: tt.wantConfigMap.Namespace}
+			require.Nil(t, cfg.Client().Get(context.Background(), key, &got))
+			require.Equal(t, tt.wantConfigMap.Labels, got.Labels)
+			require.Equal(t, tt.wantConfigMap.Annotations, got.Annotations)
+			require.Equal(t, tt.wantConfigMap.Data, got.Data)
+			var app ketchv1.App
+			require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: dashboard.Name}, &app))
+			_, rerendered := app.Annotations[chart.PostRenderPatchedAtAnnotation()]
+			require.Equal(t, tt.wantRerender, rerendered)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/theketchio/ketch/cmd/ketch/output"
+	"github.com/theketchio/ketch/internal/chart"
+)
+
+const appPatchListHelp = `
+List post-render patches.
+With --app, patches applied to the app are listed, with --framework, all patches in the framework's namespace are listed.
+SELECTOR is the app a patch is applied to, a label selector of apps or "*" for patches applied to all apps of the framework.
+`
+
+type appPatchListOptions struct {
+	scope appPatchScopeOptions
+}
+
+type appPatchListOutput struct {
+	Name      string `json:"name" yaml:"name"`
+	Selector  string `json:"selector" yaml:"selector"`
+	ConfigMap string `json:"configMap" yaml:"configMap"`
+	Files     string `json:"files" yaml:"files"`
+}
+
+func newAppPatchListCmd(cfg config, out io.Writer) *cobra.Command {
+	options := appPatchListOptions{}
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List post-render patches.",
+		Long:  appPatchListHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return appPatchList(cmd.Context(), cfg, options, out)
+		},
+	}
+	options.scope.addFlags(cmd, cfg)
+	return cmd
+}
+
+func appPatchList(ctx context.Context, cfg config, options appPatchListOptions, out io.Writer) error {
+	scope, err := options.scope.resolve(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	configMaps := v1.ConfigMapList{}
+	if err := cfg.Client().List(ctx, &configMaps, client.InNamespace(scope.namespace())); err != nil {
+		return fmt.Errorf("failed to get list of patches: %w", err)
+	}
+	sort.Slice(configMaps.Items, func(i, j int) bool { return configMaps.Items[i].Name < configMaps.Items[j].Name })
+	var rows []appPatchListOutput
+	for _, configMap := range configMaps.Items {
+		if !strings.HasSuffix(configMap.Name, chart.PostRenderSuffix) {
+			continue
+		}
+		if scope.app != nil {
+			applies, err := chart.PostRenderPatchApplies(configMap, scope.app.Name, scope.app.Labels)
+			if err != nil {
+				return err
+			}
+			if !applies {
+				continue
+			}
+		}
+		rows = append(rows, newAppPatchListOutput(configMap))
+	}
+	return output.Write(rows, out, "column")
+}
+
+func newAppPatchListOutput(configMap v1.ConfigMap) appPatchListOutput {
+	row := appPatchListOutput{
+		Name:      configMap.Labels[chart.PostRenderPatchLabel()],
+		Selector:  "*",
+		ConfigMap: configMap.Name,
+	}
+	if len(row.Name) == 0 {
+		row.Name = strings.TrimSuffix(configMap.Name, chart.PostRenderSuffix)
+	}
+	if app, ok := configMap.Labels[chart.PostRenderAppLabel()]; ok {
+		row.Selector = "app=" + app
+	} else if selector, ok := configMap.Annotations[chart.PostRenderSelectorAnnotation()]; ok {
+		row.Selector = selector
+	}
+	files := make([]string, 0, len(configMap.Data))
+	for name := range configMap.Data {
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	row.Files = strings.Join(files, ",")
+	return row
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/chart"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+func Test_appPatchList(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+	}
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard", Labels: map[string]string{"tier": "web"}},
+		Spec:       ketchv1.AppSpec{Framework: "gke"},
+	}
+	newConfigMap := func(name string, labels, annotations map[string]string) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ketch-gke", Labels: labels, Annotations: annotations},
+			Data:       map[string]string{"kustomization.yaml": "", "patch.yaml": ""},
+		}
+	}
+	objects := []runtime.Object{gke, dashboard,
+		newConfigMap("dashboard-affinity-postrender", map[string]string{chart.PostRenderPatchLabel(): "affinity", chart.PostRenderAppLabel(): "dashboard"}, nil),
+		newConfigMap("backend-affinity-postrender", map[string]string{chart.PostRenderPatchLabel(): "affinity", chart.PostRenderAppLabel(): "backend"}, nil),
+		newConfigMap("web-postrender", map[string]string{chart.PostRenderPatchLabel(): "web"}, map[string]string{chart.PostRenderSelectorAnnotation(): "tier=web"}),
+		newConfigMap("workers-postrender", map[string]string{chart.PostRenderPatchLabel(): "workers"}, map[string]string{chart.PostRenderSelectorAnnotation(): "tier=worker"}),
+		newConfigMap("legacy-postrender", nil, nil),
+		newConfigMap("settings", nil, nil),
+	}
+	tests := []struct {
+		name    string
+		options appPatchListOptions
+		want    string
+	}{
+		{
+			name:    "patches of an app",
+			options: appPatchListOptions{scope: appPatchScopeOptions{appName: "dashboard"}},
+			want: `NAME        SELECTOR         CONFIG MAP                       FILES
+affinity    app=dashboard    dashboard-affinity-postrender    kustomization.yaml,patch.yaml
+legacy      *                legacy-postrender                kustomization.yaml,patch.yaml
+web         tier=web         web-postrender                   kustomization.yaml,patch.yaml
+`,
+		},
+		{
+			name:    "patches of a framework",
+			options: appPatchListOptions{scope: appPatchScopeOptions{framework: "gke"}},
+			want: `NAME        SELECTOR         CONFIG MAP                       FILES
+affinity    app=backend      backend-affinity-postrender      kustomization.yaml,patch.yaml
+affinity    app=dashboard    dashboard-affinity-postrender    kustomization.yaml,patch.yaml
+legacy      *                legacy-postrender                kustomization.yaml,patch.yaml
+web         tier=web         web-postrender                   kustomization.yaml,patch.yaml
+workers     tier=worker      workers-postrender               kustomization.yaml,patch.yaml
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{CtrlClientObjects: objects}
+			out := &bytes.Buffer{}
+			require.Nil(t, appPatchList(context.Background(), cfg, tt.options, out))
+			require.Equal(t, tt.want, out.String())
+		})
+	}
+}
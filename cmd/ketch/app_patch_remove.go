@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const appPatchRemoveHelp = `
+Remove a post-render patch added with "ketch app patch add".
+Apps the patch applied to are rendered again without it.
+`
+
+type appPatchRemoveOptions struct {
+	scope appPatchScopeOptions
+	name  string
+}
+
+func newAppPatchRemoveCmd(cfg config, out io.Writer) *cobra.Command {
+	options := appPatchRemoveOptions{}
+	cmd := &cobra.Command{
+		Use:   "remove PATCH",
+		Short: "Remove a post-render patch.",
+		Long:  appPatchRemoveHelp,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.name = args[0]
+			return appPatchRemove(cmd.Context(), cfg, options, out)
+		},
+	}
+	options.scope.addFlags(cmd, cfg)
+	return cmd
+}
+
+func appPatchRemove(ctx context.Context, cfg config, options appPatchRemoveOptions, out io.Writer) error {
+	scope, err := options.scope.resolve(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	var configMap v1.ConfigMap
+	key := types.NamespacedName{Name: scope.configMapName(options.name), Namespace: scope.namespace()}
+	if err := cfg.Client().Get(ctx, key, &configMap); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return fmt.Errorf("%w: %s", ErrPatchNotFound, options.name)
+		}
+		return fmt.Errorf("failed to get patch: %w", err)
+	}
+	if err := cfg.Client().Delete(ctx, &configMap); err != nil {
+		return fmt.Errorf("failed to remove patch: %w", err)
+	}
+	if err := rerenderPatchedApps(ctx, cfg, scope.framework, configMap); err != nil {
+		return err
+	}
+	fmt.Fprintln(out, "Successfully removed!")
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/chart"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+func Test_appPatchRemove(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+	}
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec:       ketchv1.AppSpec{Framework: "gke"},
+	}
+	patch := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dashboard-affinity-postrender",
+			Namespace: "ketch-gke",
+			Labels:    map[string]string{chart.PostRenderPatchLabel(): "affinity", chart.PostRenderAppLabel(): "dashboard"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		options appPatchRemoveOptions
+		wantErr error
+	}{
+		{
+			name:    "patch of an app",
+			options: appPatchRemoveOptions{scope: appPatchScopeOptions{appName: "dashboard"}, name: "affinity"},
+		},
+		{
+			name:    "patch not found",
+			options: appPatchRemoveOptions{scope: appPatchScopeOptions{framework: "gke"}, name: "affinity"},
+			wantErr: ErrPatchNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{gke, dashboard, patch}}
+			out := &bytes.Buffer{}
+			err := appPatchRemove(context.Background(), cfg, tt.options, out)
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "Successfully removed!\n", out.String())
+			err = cfg.Client().Get(context.Background(), types.NamespacedName{Name: patch.Name, Namespace: patch.Namespace}, &v1.ConfigMap{})
+			require.True(t, k8sErrors.IsNotFound(err))
+			var app ketchv1.App
+			require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: dashboard.Name}, &app))
+			require.Contains(t, app.Annotations, chart.PostRenderPatchedAtAnnotation())
+		})
+	}
+}
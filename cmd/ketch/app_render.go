@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/chart"
+	"github.com/theketchio/ketch/internal/deploy"
+	"github.com/theketchio/ketch/internal/validation"
+)
+
+const appRenderHelp = `
+Render manifests of an app locally with chart templates of its framework and print them.
+Templates stored in the cluster are used, templates embedded in ketch are used if they are not stored.
+Post-render patches of the app are applied to the manifests.
+
+Render a deployed app:
+  ketch app render dashboard
+
+Render an app described in an application.yaml file as it would be deployed:
+  ketch app render application.yaml
+
+Render an app described in an application.yaml file merged with its application.prod.yaml overlay,
+the merged file is printed as a comment before manifests:
+  ketch app render application.yaml --env-overlay prod
+`
+
+type appRenderOptions struct {
+	source     string
+	envOverlay string
+}
+
+func newAppRenderCmd(cfg config, params *deploy.Services, out io.Writer) *cobra.Command {
+	options := appRenderOptions{}
+	cmd := &cobra.Command{
+		Use:   "render APPNAME|FILENAME",
+		Short: "Render manifests of an app.",
+		Long:  appRenderHelp,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.source = args[0]
+			return appRender(cmd.Context(), cfg, options, params, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().StringVar(&options.envOverlay, deploy.FlagEnvOverlay, "", "Environment of an application.<env>.yaml overlay merged into the application.yaml file.")
+	return cmd
+}
+
+func appRender(ctx context.Context, cfg config, options appRenderOptions, params *deploy.Services, out io.Writer) error {
+	if !validation.ValidateYamlFilename(options.source) {
+		if options.envOverlay != "" {
+			return ErrEnvOverlayRequiresFile
+		}
+		var app ketchv1.App
+		if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.source}, &app); err != nil {
+			return fmt.Errorf("failed to get app: %w", err)
+		}
+		return renderAppManifests(ctx, cfg, &app, out)
+	}
+	deployOptions := deploy.Options{AppName: options.source, EnvOverlay: options.envOverlay}
+	changeSet, err := deployOptions.GetChangeSetFromYaml(options.source)
+	if err != nil {
+		return err
+	}
+	app, err := dryRunDeploy(ctx, changeSet, params)
+	if err != nil {
+		return err
+	}
+	if options.envOverlay != "" {
+		if err := writeMergedApplication(out, deployOptions, options.source); err != nil {
+			return err
+		}
+	}
+	return renderAppManifests(ctx, cfg, app, out)
+}
+
+// renderAppManifests renders manifests of the app, applies post-render patches of the app and prints the manifests.
+func renderAppManifests(ctx context.Context, cfg config, app *ketchv1.App, out io.Writer) error {
+	manifests, err := appManifests(ctx, cfg, app)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(manifests)
+	return err
+}
+
+// appManifests returns rendered manifests of the app with post-render patches of the app applied.
+func appManifests(ctx context.Context, cfg config, app *ketchv1.App) ([]byte, error) {
+	var framework ketchv1.Framework
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: app.Spec.Framework}, &framework); err != nil {
+		return nil, fmt.Errorf("failed to get framework: %w", err)
+	}
+	manifests, err := renderApp(ctx, cfg, app, framework)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render app: %w", err)
+	}
+	patched, err := chart.PostRender(ctx, cfg.Client(), framework.Spec.NamespaceName, app, joinManifests(manifests))
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply post-render patches: %w", err)
+	}
+	return patched, nil
+}
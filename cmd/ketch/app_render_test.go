@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/chart"
+	"github.com/theketchio/ketch/internal/deploy"
+	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/utils/conversions"
+)
+
+func Test_appRender(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-gke",
+			IngressController: ketchv1.IngressControllerSpec{
+				IngressType: ketchv1.TraefikIngressControllerType,
+			},
+		},
+		Status: ketchv1.FrameworkStatus{
+			Namespace: &v1.ObjectReference{Name: "ketch-gke"},
+			Apps:      []string{"dashboard", "frontend", "api"},
+		},
+	}
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			Framework:        "gke",
+			DeploymentsCount: 1,
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Image:   "shipasoftware/dashboard:v1",
+					Version: 1,
+					Processes: []ketchv1.ProcessSpec{
+						{Name: "web", Units: conversions.IntPtr(1), Cmd: []string{"/app"}},
+					},
+					RoutingSettings: ketchv1.RoutingSettings{Weight: 100},
+				},
+			},
+		},
+	}
+	frontend := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "frontend"},
+		Spec: ketchv1.AppSpec{
+			Framework:        "gke",
+			DeploymentsCount: 1,
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Image:   "shipasoftware/frontend:v1",
+					Version: 1,
+					Processes: []ketchv1.ProcessSpec{
+						{Name: "web", Units: conversions.IntPtr(1), Cmd: []string{"/app"}},
+					},
+					RoutingSettings: ketchv1.RoutingSettings{Weight: 100},
+				},
+			},
+			ServiceBindings: []ketchv1.ServiceBinding{{Name: "db", SecretName: "postgres", Mode: ketchv1.ServiceBindingModeEnv}},
+		},
+	}
+	api := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "api"},
+		Spec:       ketchv1.AppSpec{Framework: "gke"},
+		Status: ketchv1.AppStatus{InternalServices: []ketchv1.InternalService{
+			{Process: "worker", Host: "api-worker.ketch-gke.svc.cluster.local", Port: 9090, Published: true},
+		}},
+	}
+	postgres := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "ketch-gke"},
+		Data:       map[string][]byte{"password": []byte("secret")},
+	}
+	patch := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dashboard-pool-postrender",
+			Namespace: "ketch-gke",
+			Labels:    map[string]string{chart.PostRenderAppLabel(): "dashboard"},
+		},
+		Data: map[string]string{
+			"kustomization.yaml": "resources:\n- app.yaml\npatches:\n- path: pool.yaml\n  target:\n    kind: Deployment\n",
+			"pool.yaml":          affinityPatch,
+		},
+	}
+	application := `
+name: backend
+image: shipasoftware/backend:v2
+framework: gke
+`
+	tests := []struct {
+		name         string
+		source       func(t *testing.T) string
+		envOverlay   string
+		wantContains []string
+	}{
+		{
+			name:   "deployed app",
+			source: func(t *testing.T) string { return "dashboard" },
+			wantContains: []string{
+				"kind: Deployment",
+				"name: dashboard-web-1",
+				"image: shipasoftware/dashboard:v1",
+				"pool: web",
+			},
+		},
+		{
+			name:   "deployed app with cluster state",
+			source: func(t *testing.T) string { return "frontend" },
+			wantContains: []string{
+				"name: frontend-web-1",
+				"name: API_WORKER_URL\n              value: http://api-worker.ketch-gke.svc.cluster.local:9090",
+				ketchv1.ServiceBindingsChecksumAnnotation(ketchv1.Group) + ":",
+			},
+		},
+		{
+			name: "application.yaml",
+			source: func(t *testing.T) string {
+				filename := filepath.Join(t.TempDir(), "application.yaml")
+				require.Nil(t, os.WriteFile(filename, []byte(application), 0600))
+				return filename
+			},
+			wantContains: []string{
+				"kind: Deployment",
+				"name: backend-web-1",
+				"image: shipasoftware/backend:v2",
+			},
+		},
+		{
+			name: "application.yaml with overlay",
+			source: func(t *testing.T) string {
+				dir := t.TempDir()
+				filename := filepath.Join(dir, "application.yaml")
+				require.Nil(t, os.WriteFile(filename, []byte(application), 0600))
+				overlay := "image: shipasoftware/backend:v3\nprocesses:\n  - name: web\n    units: 2\n"
+				require.Nil(t, os.WriteFile(filepath.Join(dir, "application.prod.yaml"), []byte(overlay), 0600))
+				return filename
+			},
+			envOverlay: "prod",
+			wantContains: []string{
+				"application.prod.yaml:\n# framework: gke\n# image: shipasoftware/backend:v3\n# name: backend\n",
+				"name: backend-web-1",
+				"image: shipasoftware/backend:v3",
+				"replicas: 2",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{gke, dashboard, frontend, api, postgres, patch},
+			}
+			params := &deploy.Services{
+				Client:         cfg.Client(),
+				GetImageConfig: getImageConfig,
+			}
+			out := &bytes.Buffer{}
+			options := appRenderOptions{source: tt.source(t), envOverlay: tt.envOverlay}
+			require.Nil(t, appRender(context.Background(), cfg, options, params, out))
+			for _, want := range tt.wantContains {
+				require.Contains(t, out.String(), want)
+			}
+			// rendering doesn't create or update apps
+			err := cfg.Client().Get(context.Background(), types.NamespacedName{Name: "backend"}, &ketchv1.App{})
+			require.True(t, k8sErrors.IsNotFound(err))
+		})
+	}
+}
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+
+	"github.com/theketchio/ketch/cmd/ketch/output"
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/build"
+	"github.com/theketchio/ketch/internal/deploy"
+	"github.com/theketchio/ketch/internal/pack"
+)
+
+const applyHelp = `
+Apply frameworks, applications and jobs described in a file or in all .yaml and .yml files of a directory.
+A file may contain several documents separated by "---", each document has a type field set to
+Framework, Application or Job and the fields of "ketch framework add", "ketch app deploy" or "ketch job deploy" files:
+
+	type: Framework
+	name: framework1
+	---
+	type: Application
+	name: dashboard
+	image: shipasoftware/dashboard:v1
+	framework: framework1
+	---
+	type: Job
+	name: backup
+	framework: framework1
+	containers:
+	  - name: backup
+	    image: shipasoftware/backup:v1
+
+Frameworks are applied before applications and jobs running in them.
+
+  ketch apply -f ./deployments
+
+Labels of --selector are set to all applied resources. With --prune, frameworks, applications and jobs
+matching the selector that are not described in the files are removed:
+
+  ketch apply -f ./deployments --selector team=payments --prune
+`
+
+const (
+	applyTypeFramework   = "Framework"
+	applyTypeApplication = "Application"
+	applyTypeJob         = "Job"
+
+	applyResultCreated   = "created"
+	applyResultChanged   = "changed"
+	applyResultUnchanged = "unchanged"
+	applyResultPruned    = "pruned"
+)
+
+type applyOptions struct {
+	filename string
+	selector string
+	prune    bool
+}
+
+type applyOutput struct {
+	Type   string `json:"type" yaml:"type"`
+	Name   string `json:"name" yaml:"name"`
+	Result string `json:"result" yaml:"result"`
+}
+
+// applyResources contains resources described in files grouped by their type in dependency order.
+type applyResources struct {
+	frameworks []*ketchv1.Framework
+	apps       []*deploy.ChangeSet
+	jobs       []ketchv1.JobSpec
+}
+
+func newApplyCmd(cfg config, out io.Writer, packSvc *pack.Client) *cobra.Command {
+	options := applyOptions{}
+	cmd := &cobra.Command{
+		Use:   "apply -f FILENAME|DIRECTORY",
+		Short: "Apply frameworks, applications and jobs described in files.",
+		Long:  applyHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := &deploy.Services{
+				Client:         cfg.Client(),
+				KubeClient:     cfg.KubernetesClient(),
+				Builder:        build.GetSourceHandler(packSvc),
+				GetImageConfig: deploy.GetImageConfig,
+				GetImageDigest: deploy.GetImageDigest,
+				Wait:           deploy.WaitForDeployment,
+				Writer:         out,
+			}
+			return apply(cmd.Context(), cfg, options, params, out)
+		},
+	}
+	cmd.Flags().StringVarP(&options.filename, "filename", "f", "", "file or directory with resources to apply")
+	cmd.Flags().StringVarP(&options.selector, "selector", "l", "", "labels set to applied resources, e.g. team=payments")
+	cmd.Flags().BoolVar(&options.prune, "prune", false, "remove resources matching the selector that are not described in the files")
+	cmd.MarkFlagRequired("filename")
+	return cmd
+}
+
+func apply(ctx context.Context, cfg config, options applyOptions, params *deploy.Services, out io.Writer) error {
+	selectorLabels, err := labels.ConvertSelectorToLabelsMap(options.selector)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidApplySelector, err)
+	}
+	if options.prune && len(selectorLabels) == 0 {
+		return ErrPruneRequiresSelector
+	}
+	resources, err := readApplyResources(options.filename)
+	if err != nil {
+		return err
+	}
+
+	var rows []applyOutput
+	for _, framework := range resources.frameworks {
+		result, err := applyFramework(ctx, cfg, framework, selectorLabels)
+		if err != nil {
+			return fmt.Errorf("failed to apply framework %q: %w", framework.Name, err)
+		}
+		rows = append(rows, applyOutput{Type: applyTypeFramework, Name: framework.Name, Result: result})
+	}
+	for _, changeSet := range resources.apps {
+		result, err := applyApp(ctx, params, changeSet, selectorLabels)
+		if err != nil {
+			return fmt.Errorf("failed to apply application %q: %w", changeSet.AppName(), err)
+		}
+		rows = append(rows, applyOutput{Type: applyTypeApplication, Name: changeSet.AppName(), Result: result})
+	}
+	for _, spec := range resources.jobs {
+		result, err := applyJob(ctx, cfg, spec, selectorLabels)
+		if err != nil {
+			return fmt.Errorf("failed to apply job %q: %w", spec.Name, err)
+		}
+		rows = append(rows, applyOutput{Type: applyTypeJob, Name: spec.Name, Result: result})
+	}
+	if options.prune {
+		pruned, err := pruneResources(ctx, cfg, resources, selectorLabels)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, pruned...)
+	}
+	return output.Write(rows, out, "column")
+}
+
+// readApplyResources reads resources from the file or from .yaml and .yml files of the directory.
+func readApplyResources(filename string) (*applyResources, error) {
+	stat, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+	filenames := []string{filename}
+	if stat.IsDir() {
+		entries, err := os.ReadDir(filename)
+		if err != nil {
+			return nil, err
+		}
+		filenames = nil
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+				continue
+			}
+			filenames = append(filenames, filepath.Join(filename, entry.Name()))
+		}
+	}
+
+	resources := &applyResources{}
+	names := map[string]bool{}
+	for _, name := range filenames {
+		documents, err := readYamlDocuments(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		for i, document := range documents {
+			resourceType, resourceName, err := resources.add(document)
+			if err != nil {
+				return nil, fmt.Errorf("document %d of %s: %w", i+1, name, err)
+			}
+			key := resourceType + "/" + resourceName
+			if names[key] {
+				return nil, fmt.Errorf("%w: %s %q", ErrDuplicateApplyResource, resourceType, resourceName)
+			}
+			names[key] = true
+		}
+	}
+	return resources, nil
+}
+
+// readYamlDocuments returns non-empty documents of a multi-document yaml file.
+func readYamlDocuments(filename string) ([][]byte, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(f))
+	var documents [][]byte
+	for {
+		document, err := reader.Read()
+		if err == io.EOF {
+			return documents, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var content map[string]interface{}
+		if err := yaml.Unmarshal(document, &content); err != nil {
+			return nil, err
+		}
+		if len(content) > 0 {
+			documents = append(documents, bytes.TrimSpace(document))
+		}
+	}
+}
+
+// add parses the document according to its type and returns the type and the name of the resource.
+func (r *applyResources) add(document []byte) (string, string, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := yaml.Unmarshal(document, &header); err != nil {
+		return "", "", err
+	}
+	switch header.Type {
+	case applyTypeFramework:
+		framework, err := newFrameworkFromYamlData(document)
+		if err != nil {
+			return "", "", err
+		}
+		r.frameworks = append(r.frameworks, framework)
+		return header.Type, framework.Name, nil
+	case applyTypeApplication:
+		changeSet, err := (&deploy.Options{}).GetChangeSetFromYamlData(document)
+		if err != nil {
+			return "", "", err
+		}
+		r.apps = append(r.apps, changeSet)
+		return header.Type, changeSet.AppName(), nil
+	case applyTypeJob:
+		spec, err := newJobSpecFromYamlData(document)
+		if err != nil {
+			return "", "", err
+		}
+		r.jobs = append(r.jobs, spec)
+		return header.Type, spec.Name, nil
+	}
+	return "", "", fmt.Errorf("%w: %q", ErrUnknownApplyType, header.Type)
+}
+
+func applyFramework(ctx context.Context, cfg config, framework *ketchv1.Framework, selectorLabels map[string]string) (string, error) {
+	if len(framework.Spec.IngressController.ClusterIssuer) > 0 {
+		exists, err := clusterIssuerExist(cfg.DynamicClient(), ctx, framework.Spec.IngressController.ClusterIssuer)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return "", ErrClusterIssuerNotFound
+		}
+	}
+	existing := &ketchv1.Framework{ObjectMeta: metav1.ObjectMeta{Name: framework.Name}}
+	result, err := controllerutil.CreateOrUpdate(ctx, cfg.Client(), existing, func() error {
+		existing.Spec = framework.Spec
+		setApplyLabels(&existing.ObjectMeta, selectorLabels)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return applyResult(result), nil
+}
+
+func applyJob(ctx context.Context, cfg config, spec ketchv1.JobSpec, selectorLabels map[string]string) (string, error) {
+	var jobs ketchv1.JobList
+	if err := cfg.Client().List(ctx, &jobs); err != nil {
+		return "", err
+	}
+	job := &ketchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: spec.Name}}
+	if err := (&ketchv1.Job{ObjectMeta: job.ObjectMeta, Spec: spec}).ValidateUniqueName(jobs.Items); err != nil {
+		return "", err
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, cfg.Client(), job, func() error {
+		job.Spec = spec
+		setApplyLabels(&job.ObjectMeta, selectorLabels)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return applyResult(result), nil
+}
+
+// applyApp deploys the app and reports whether the deployment created or changed the app.
+func applyApp(ctx context.Context, params *deploy.Services, changeSet *deploy.ChangeSet, selectorLabels map[string]string) (string, error) {
+	key := types.NamespacedName{Name: changeSet.AppName()}
+	var before ketchv1.App
+	err := params.Client.Get(ctx, key, &before)
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return "", err
+	}
+	created := k8sErrors.IsNotFound(err)
+
+	if err := deploy.New(changeSet).Run(ctx, params); err != nil {
+		return "", err
+	}
+
+	var after ketchv1.App
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := params.Client.Get(ctx, key, &after); err != nil {
+			return err
+		}
+		if !setApplyLabels(&after.ObjectMeta, selectorLabels) {
+			return nil
+		}
+		return params.Client.Update(ctx, &after)
+	})
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case created:
+		return applyResultCreated, nil
+	case equality.Semantic.DeepEqual(before.Spec, after.Spec) && equality.Semantic.DeepEqual(before.Labels, after.Labels):
+		return applyResultUnchanged, nil
+	}
+	return applyResultChanged, nil
+}
+
+// pruneResources removes apps, jobs and frameworks matching the selector that are not described in files.
+func pruneResources(ctx context.Context, cfg config, resources *applyResources, selectorLabels map[string]string) ([]applyOutput, error) {
+	applied := map[string]bool{}
+	for _, framework := range resources.frameworks {
+		applied[applyTypeFramework+"/"+framework.Name] = true
+	}
+	for _, changeSet := range resources.apps {
+		applied[applyTypeApplication+"/"+changeSet.AppName()] = true
+	}
+	for _, spec := range resources.jobs {
+		applied[applyTypeJob+"/"+spec.Name] = true
+	}
+	matchingLabels := client.MatchingLabels(selectorLabels)
+
+	var objects []client.Object
+	var objectTypes []string
+	var apps ketchv1.AppList
+	if err := cfg.Client().List(ctx, &apps, matchingLabels); err != nil {
+		return nil, fmt.Errorf("failed to list apps: %w", err)
+	}
+	for i := range apps.Items {
+		objects = append(objects, &apps.Items[i])
+		objectTypes = append(objectTypes, applyTypeApplication)
+	}
+	var jobs ketchv1.JobList
+	if err := cfg.Client().List(ctx, &jobs, matchingLabels); err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	for i := range jobs.Items {
+		objects = append(objects, &jobs.Items[i])
+		objectTypes = append(objectTypes, applyTypeJob)
+	}
+	// frameworks are removed after apps and jobs running in them.
+	var frameworks ketchv1.FrameworkList
+	if err := cfg.Client().List(ctx, &frameworks, matchingLabels); err != nil {
+		return nil, fmt.Errorf("failed to list frameworks: %w", err)
+	}
+	for i := range frameworks.Items {
+		objects = append(objects, &frameworks.Items[i])
+		objectTypes = append(objectTypes, applyTypeFramework)
+	}
+
+	var rows []applyOutput
+	for i, obj := range objects {
+		if applied[objectTypes[i]+"/"+obj.GetName()] {
+			continue
+		}
+		if err := client.IgnoreNotFound(cfg.Client().Delete(ctx, obj)); err != nil {
+			return nil, fmt.Errorf("failed to prune %s %q: %w", strings.ToLower(objectTypes[i]), obj.GetName(), err)
+		}
+		rows = append(rows, applyOutput{Type: objectTypes[i], Name: obj.GetName(), Result: applyResultPruned})
+	}
+	return rows, nil
+}
+
+// setApplyLabels sets the labels to the object and returns true if any label has changed.
+func setApplyLabels(obj *metav1.ObjectMeta, selectorLabels map[string]string) bool {
+	changed := false
+	for name, value := range selectorLabels {
+		if current, ok := obj.Labels[name]; ok && current == value {
+			continue
+		}
+		if obj.Labels == nil {
+			obj.Labels = map[string]string{}
+		}
+		obj.Labels[name] = value
+		changed = true
+	}
+	return changed
+}
+
+func applyResult(result controllerutil.OperationResult) string {
+	switch result {
+	case controllerutil.OperationResultCreated:
+		return applyResultCreated
+	case controllerutil.OperationResultNone:
+		return applyResultUnchanged
+	}
+	return applyResultChanged
+}
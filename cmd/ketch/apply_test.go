@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/deploy"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+const (
+	applyFrameworkYaml = `type: Framework
+name: payments
+`
+	applyAppYaml = `type: Application
+name: dashboard
+image: shipasoftware/dashboard:v1
+framework: payments
+`
+	applyJobYaml = `type: Job
+name: backup
+framework: payments
+containers:
+  - name: backup
+    image: shipasoftware/backup:v1
+    command: ["backup"]
+`
+)
+
+func writeApplyFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		require.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	return dir
+}
+
+func Test_apply(t *testing.T) {
+	teamPayments := map[string]string{"team": "payments"}
+	oldApp := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "old-dashboard", Labels: teamPayments},
+		Spec:       ketchv1.AppSpec{Framework: "payments"},
+	}
+	otherApp := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "search", Labels: map[string]string{"team": "search"}},
+		Spec:       ketchv1.AppSpec{Framework: "payments"},
+	}
+	changedFramework := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "payments"},
+		Spec:       ketchv1.FrameworkSpec{Name: "payments", NamespaceName: "ketch-old-payments"},
+	}
+	changedJob := &ketchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "backup", Labels: teamPayments},
+		Spec: ketchv1.JobSpec{
+			Name:       "backup",
+			Framework:  "payments",
+			Containers: []ketchv1.Container{{Name: "backup", Image: "shipasoftware/backup:v0", Command: []string{"backup"}}},
+		},
+	}
+	payments := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "payments"},
+		Spec:       ketchv1.FrameworkSpec{Name: "payments", NamespaceName: "ketch-payments"},
+	}
+	boundApp := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			Framework:       "payments",
+			Labels:          []ketchv1.MetadataItem{{Apply: teamPayments}},
+			Annotations:     []ketchv1.MetadataItem{{Apply: map[string]string{"theketch.io/owner": "payments"}}},
+			Ingress:         ketchv1.IngressSpec{Cnames: ketchv1.CnameList{{Name: "dashboard.com"}}},
+			ServiceBindings: []ketchv1.ServiceBinding{{Name: "db", SecretName: "postgres", Mode: ketchv1.ServiceBindingModeMount}},
+		},
+	}
+	conflictingJob := &ketchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly-backup"},
+		Spec:       ketchv1.JobSpec{Name: "backup", Framework: "payments"},
+	}
+
+	tests := []struct {
+		name       string
+		files      map[string]string
+		objects    []runtime.Object
+		options    applyOptions
+		wantOutput []string
+		wantErr    error
+		validate   func(t *testing.T, cfg config)
+	}{
+		{
+			name: "create resources in dependency order",
+			files: map[string]string{
+				"a-job.yaml":  applyJobYaml,
+				"b-apps.yaml": applyAppYaml + "---\n" + applyFrameworkYaml,
+				"notes.txt":   "not a resource",
+			},
+			options: applyOptions{selector: "team=payments"},
+			wantOutput: []string{
+				"TYPE           NAME         RESULT",
+				"Framework      payments     created",
+				"Application    dashboard    created",
+				"Job            backup       created",
+			},
+			validate: func(t *testing.T, cfg config) {
+				var app ketchv1.App
+				require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+				require.Equal(t, teamPayments, app.Labels)
+				require.Equal(t, "shipasoftware/dashboard:v1", app.Spec.Deployments[0].Image)
+				var framework ketchv1.Framework
+				require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "payments"}, &framework))
+				require.Equal(t, "ketch-payments", framework.Spec.NamespaceName)
+				require.Equal(t, teamPayments, framework.Labels)
+			},
+		},
+		{
+			name: "changed framework and pruned app",
+			files: map[string]string{
+				"resources.yaml": applyFrameworkYaml,
+			},
+			objects: []runtime.Object{changedFramework, oldApp, otherApp},
+			options: applyOptions{selector: "team=payments", prune: true},
+			wantOutput: []string{
+				"Framework      payments         changed",
+				"Application    old-dashboard    pruned",
+			},
+			validate: func(t *testing.T, cfg config) {
+				err := cfg.Client().Get(context.Background(), types.NamespacedName{Name: "old-dashboard"}, &ketchv1.App{})
+				require.True(t, k8sErrors.IsNotFound(err))
+				require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "search"}, &ketchv1.App{}))
+			},
+		},
+		{
+			name: "changed app without metadata, cnames and service bindings",
+			files: map[string]string{
+				"resources.yaml": applyAppYaml,
+			},
+			objects: []runtime.Object{payments, boundApp},
+			wantOutput: []string{
+				"Application    dashboard    changed",
+			},
+			validate: func(t *testing.T, cfg config) {
+				var app ketchv1.App
+				require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+				require.Empty(t, app.Spec.Labels)
+				require.Empty(t, app.Spec.Annotations)
+				require.Empty(t, app.Spec.Ingress.Cnames)
+				require.Empty(t, app.Spec.ServiceBindings)
+			},
+		},
+		{
+			name: "changed job",
+			files: map[string]string{
+				"resources.yaml": applyJobYaml,
+			},
+			objects: []runtime.Object{changedJob},
+			wantOutput: []string{
+				"Job     backup    changed",
+			},
+			validate: func(t *testing.T, cfg config) {
+				var job ketchv1.Job
+				require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "backup"}, &job))
+				require.Equal(t, "shipasoftware/backup:v1", job.Spec.Containers[0].Image)
+			},
+		},
+		{
+			name:    "another job with the same name",
+			files:   map[string]string{"resources.yaml": applyJobYaml},
+			objects: []runtime.Object{conflictingJob},
+			wantErr: ketchv1.ErrJobExists,
+		},
+		{
+			name:    "unknown type",
+			files:   map[string]string{"resources.yaml": "type: Service\nname: db\n"},
+			wantErr: ErrUnknownApplyType,
+		},
+		{
+			name:    "duplicate resource",
+			files:   map[string]string{"a.yaml": applyFrameworkYaml, "b.yaml": applyFrameworkYaml},
+			wantErr: ErrDuplicateApplyResource,
+		},
+		{
+			name:    "prune without selector",
+			files:   map[string]string{"resources.yaml": applyFrameworkYaml},
+			options: applyOptions{prune: true},
+			wantErr: ErrPruneRequiresSelector,
+		},
+		{
+			name:    "invalid selector",
+			files:   map[string]string{"resources.yaml": applyFrameworkYaml},
+			options: applyOptions{selector: "team in (payments)"},
+			wantErr: ErrInvalidApplySelector,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{CtrlClientObjects: tt.objects}
+			params := &deploy.Services{
+				Client:         cfg.Client(),
+				GetImageConfig: getImageConfig,
+			}
+			out := &bytes.Buffer{}
+			options := tt.options
+			options.filename = writeApplyFiles(t, tt.files)
+			err := apply(context.Background(), cfg, options, params, out)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.Nil(t, err)
+			for _, want := range tt.wantOutput {
+				require.Contains(t, out.String(), want)
+			}
+			if tt.validate != nil {
+				tt.validate(t, cfg)
+			}
+		})
+	}
+}
+
+func Test_applyUnchanged(t *testing.T) {
+	cfg := &mocks.Configuration{}
+	params := &deploy.Services{
+		Client:         cfg.Client(),
+		GetImageConfig: getImageConfig,
+	}
+	filename := filepath.Join(t.TempDir(), "resources.yaml")
+	content := applyFrameworkYaml + "---\n" + applyAppYaml + "---\n" + applyJobYaml
+	require.Nil(t, os.WriteFile(filename, []byte(content), 0600))
+	options := applyOptions{filename: filename}
+
+	require.Nil(t, apply(context.Background(), cfg, options, params, &bytes.Buffer{}))
+	out := &bytes.Buffer{}
+	require.Nil(t, apply(context.Background(), cfg, options, params, out))
+	require.Contains(t, out.String(), "Framework      payments     unchanged")
+	require.Contains(t, out.String(), "Application    dashboard    unchanged")
+	require.Contains(t, out.String(), "Job            backup       unchanged")
+}
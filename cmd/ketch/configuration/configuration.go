@@ -15,7 +15,6 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
-	"github.com/theketchio/ketch/internal/controllers"
 	"github.com/theketchio/ketch/internal/templates"
 )
 
@@ -85,7 +84,7 @@ func (cfg *Configuration) Storage() templates.Client {
 	if cfg.storage != nil {
 		return cfg.storage
 	}
-	cfg.storage = templates.NewStorage(cfg.Client(), controllers.KetchNamespace)
+	cfg.storage = templates.NewStorage(cfg.Client(), templates.KetchNamespace)
 	return cfg.storage
 }
 
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	fileDiffAdded    = "added"
+	fileDiffRemoved  = "removed"
+	fileDiffModified = "modified"
+)
+
+// fileDiff is a unified diff of one file.
+type fileDiff struct {
+	Name   string `json:"name" yaml:"name"`
+	Status string `json:"status" yaml:"status"`
+	Diff   string `json:"diff" yaml:"diff"`
+}
+
+// fileDiffs returns unified diffs of files that differ between from and to, files are keyed by name.
+// Prefixes are prepended to names of files in diff headers.
+func fileDiffs(from, to map[string]string, fromPrefix, toPrefix string) ([]fileDiff, error) {
+	names := map[string]struct{}{}
+	for name := range from {
+		names[name] = struct{}{}
+	}
+	for name := range to {
+		names[name] = struct{}{}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var diffs []fileDiff
+	for _, name := range sorted {
+		fromContent, inFrom := from[name]
+		toContent, inTo := to[name]
+		if fromContent == toContent && inFrom == inTo {
+			continue
+		}
+		fromFile, toFile, status := fromPrefix+name, toPrefix+name, fileDiffModified
+		if !inFrom {
+			fromFile, status = "/dev/null", fileDiffAdded
+		}
+		if !inTo {
+			toFile, status = "/dev/null", fileDiffRemoved
+		}
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitLines(fromContent),
+			B:        splitLines(toContent),
+			FromFile: fromFile,
+			ToFile:   toFile,
+			Context:  3,
+		})
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, fileDiff{Name: name, Status: status, Diff: diff})
+	}
+	return diffs, nil
+}
+
+// joinFileDiffs joins diffs of files into one unified diff.
+func joinFileDiffs(diffs []fileDiff) string {
+	var b strings.Builder
+	for _, diff := range diffs {
+		b.WriteString(diff.Diff)
+	}
+	return b.String()
+}
+
+// splitLines splits content into lines keeping their line endings, a missing final line ending is added.
+func splitLines(content string) []string {
+	if len(content) == 0 {
+		return nil
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	lines := strings.SplitAfter(content, "\n")
+	return lines[:len(lines)-1]
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+
+	"github.com/theketchio/ketch/cmd/ketch/output"
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/deploy"
+	"github.com/theketchio/ketch/internal/utils"
+)
+
+const envExportHelp = `
+Export environment variables of an application as a dotenv file, or a json or yaml object of names and values.
+The exported file can be passed back to "ketch env set --file" or "ketch app deploy --env-file".
+
+  ketch env export -a dashboard > .env
+  ketch env export -a dashboard --format json > env.json
+`
+
+const (
+	envFormatDotenv = "dotenv"
+	envFormatJSON   = "json"
+	envFormatYAML   = "yaml"
+)
+
+func newEnvExportCmd(cfg config, out io.Writer) *cobra.Command {
+	options := envExportOptions{}
+	cmd := &cobra.Command{
+		Use:   "export",
+		Args:  cobra.NoArgs,
+		Short: "Export environment variables of an application.",
+		Long:  envExportHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return envExport(cmd.Context(), cfg, options, out)
+		},
+	}
+	cmd.Flags().StringVarP(&options.appName, deploy.FlagApp, deploy.FlagAppShort, "", "The name of the app.")
+	cmd.MarkFlagRequired(deploy.FlagApp)
+	cmd.RegisterFlagCompletionFunc(deploy.FlagApp, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return autoCompleteAppNames(cfg, toComplete)
+	})
+	cmd.Flags().StringVar(&options.format, "format", envFormatDotenv, "output format: dotenv, json or yaml")
+	return cmd
+}
+
+type envExportOptions struct {
+	appName string
+	format  string
+}
+
+func envExport(ctx context.Context, cfg config, options envExportOptions, out io.Writer) error {
+	if options.format != envFormatDotenv && options.format != envFormatJSON && options.format != envFormatYAML {
+		return ErrInvalidEnvFormat
+	}
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get the app: %w", err)
+	}
+	if options.format == envFormatDotenv {
+		_, err := out.Write(utils.FormatDotenv(app.Spec.Env))
+		return err
+	}
+	envs := app.Envs(nil)
+	if options.format == envFormatJSON {
+		return output.Write(envs, out, envFormatJSON)
+	}
+	b, err := yaml.Marshal(envs)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(b)
+	return err
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+func Test_envExport(t *testing.T) {
+	envs := []ketchv1.Env{
+		{Name: "LOG_LEVEL", Value: "debug"},
+		{Name: "GREETING", Value: "say \"hi\" to $USER"},
+		{Name: "CERT", Value: "-----BEGIN CERTIFICATE-----\nabc\n-----END CERTIFICATE-----"},
+	}
+	tests := []struct {
+		format   string
+		filename string
+		want     string
+	}{
+		{
+			format:   envFormatDotenv,
+			filename: ".env",
+			want:     "LOG_LEVEL=debug\nGREETING=\"say \\\"hi\\\" to \\$USER\"\nCERT=\"-----BEGIN CERTIFICATE-----\\nabc\\n-----END CERTIFICATE-----\"\n",
+		},
+		{
+			format:   envFormatJSON,
+			filename: "env.json",
+			want:     "{\n  \"CERT\": \"-----BEGIN CERTIFICATE-----\\nabc\\n-----END CERTIFICATE-----\",\n  \"GREETING\": \"say \\\"hi\\\" to $USER\",\n  \"LOG_LEVEL\": \"debug\"\n}\n",
+		},
+		{
+			format:   envFormatYAML,
+			filename: "env.yaml",
+			want:     "CERT: |-\n  -----BEGIN CERTIFICATE-----\n  abc\n  -----END CERTIFICATE-----\nGREETING: say \"hi\" to $USER\nLOG_LEVEL: debug\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			dashboard := &ketchv1.App{
+				ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+				Spec:       ketchv1.AppSpec{Env: envs},
+			}
+			cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{dashboard}}
+			out := &bytes.Buffer{}
+			err := envExport(context.Background(), cfg, envExportOptions{appName: "dashboard", format: tt.format}, out)
+			require.Nil(t, err)
+			require.Equal(t, tt.want, out.String())
+
+			// the exported file replaces env variables set afterwards
+			filename := filepath.Join(t.TempDir(), tt.filename)
+			require.Nil(t, os.WriteFile(filename, out.Bytes(), 0600))
+			var app ketchv1.App
+			require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+			app.Spec.Env = append(app.Spec.Env, ketchv1.Env{Name: "EXTRA", Value: "1"})
+			require.Nil(t, cfg.Client().Update(context.Background(), &app))
+
+			err = envSet(context.Background(), cfg, envSetOptions{appName: "dashboard", filename: filename, replace: true}, out)
+			require.Nil(t, err)
+			require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+			require.ElementsMatch(t, envs, app.Spec.Env)
+		})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"log"
 
 	"github.com/spf13/cobra"
 	"k8s.io/apimachinery/pkg/types"
@@ -16,13 +15,24 @@ import (
 
 const envSetHelp = `
 Set environment variables for an application.
+
+  ketch env set -a dashboard LOG_LEVEL=debug PORT=8080
+
+Set environment variables from a dotenv file, or a .json or .yaml file with an object of names and values.
+Dotenv values may be quoted, double quoted values support escapes and both can span multiple lines.
+Variables passed as arguments override variables of the file:
+
+  ketch env set -a dashboard --file .env
+
+Use --replace to make the app's environment variables exactly match the file and arguments,
+variables missing from them are unset.
 `
 
 func newEnvSetCmd(cfg config, out io.Writer) *cobra.Command {
 	options := envSetOptions{}
 	cmd := &cobra.Command{
 		Use:   "set",
-		Args:  cobra.MinimumNArgs(1),
+		Args:  cobra.ArbitraryArgs,
 		Short: "Set environment variables for an application.",
 		Long:  envSetHelp,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -33,6 +43,8 @@ func newEnvSetCmd(cfg config, out io.Writer) *cobra.Command {
 	}
 	cmd.Flags().StringVarP(&options.appName, deploy.FlagApp, deploy.FlagAppShort, "", "The name of the app.")
 	cmd.MarkFlagRequired(deploy.FlagApp)
+	cmd.Flags().StringVarP(&options.filename, "file", "f", "", "A dotenv, json or yaml file with environment variables.")
+	cmd.Flags().BoolVar(&options.replace, "replace", false, "Unset environment variables missing from the file and arguments.")
 	cmd.RegisterFlagCompletionFunc(deploy.FlagApp, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return autoCompleteAppNames(cfg, toComplete)
 	})
@@ -40,20 +52,37 @@ func newEnvSetCmd(cfg config, out io.Writer) *cobra.Command {
 }
 
 type envSetOptions struct {
-	appName string
-	envs    []string
+	appName  string
+	envs     []string
+	filename string
+	replace  bool
 }
 
 func envSet(ctx context.Context, cfg config, options envSetOptions, out io.Writer) error {
-	envs, err := utils.MakeEnvironments(options.envs)
+	if len(options.envs) == 0 && options.filename == "" {
+		return ErrNoEnvVariables
+	}
+	var envs []ketchv1.Env
+	if options.filename != "" {
+		fileEnvs, err := utils.ReadEnvFile(options.filename)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", options.filename, err)
+		}
+		envs = fileEnvs
+	}
+	argEnvs, err := utils.MakeEnvironments(options.envs)
 	if err != nil {
-		return fmt.Errorf("failed to get kubernetes client: %w", err)
+		return fmt.Errorf("failed to parse env variables: %w", err)
 	}
 	app := ketchv1.App{}
 	if err = cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
-		log.Fatalf("failed to get the app: %v", err)
+		return fmt.Errorf("failed to get the app: %w", err)
+	}
+	if options.replace {
+		app.Spec.Env = nil
 	}
 	app.SetEnvs(envs)
+	app.SetEnvs(argEnvs)
 	if err := cfg.Client().Update(ctx, &app); err != nil {
 		return fmt.Errorf("failed to update the app: %w", err)
 	}
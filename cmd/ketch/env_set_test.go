@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+func Test_envSet(t *testing.T) {
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{Env: []ketchv1.Env{
+			{Name: "LOG_LEVEL", Value: "debug"},
+			{Name: "PORT", Value: "8080"},
+		}},
+	}
+	filename := filepath.Join(t.TempDir(), ".env")
+	require.Nil(t, os.WriteFile(filename, []byte("LOG_LEVEL=warn\nSENTRY_DSN='https://sentry.example.com'\n"), 0600))
+	tests := []struct {
+		name     string
+		options  envSetOptions
+		wantEnvs []ketchv1.Env
+		wantErr  error
+	}{
+		{
+			name:    "file and arguments",
+			options: envSetOptions{appName: "dashboard", filename: filename, envs: []string{"SENTRY_DSN=none"}},
+			wantEnvs: []ketchv1.Env{
+				{Name: "LOG_LEVEL", Value: "warn"},
+				{Name: "PORT", Value: "8080"},
+				{Name: "SENTRY_DSN", Value: "none"},
+			},
+		},
+		{
+			name:    "replace",
+			options: envSetOptions{appName: "dashboard", filename: filename, replace: true},
+			wantEnvs: []ketchv1.Env{
+				{Name: "LOG_LEVEL", Value: "warn"},
+				{Name: "SENTRY_DSN", Value: "https://sentry.example.com"},
+			},
+		},
+		{
+			name:    "no env variables",
+			options: envSetOptions{appName: "dashboard"},
+			wantErr: ErrNoEnvVariables,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{dashboard.DeepCopy()}}
+			err := envSet(context.Background(), cfg, tt.options, &bytes.Buffer{})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.Nil(t, err)
+			var app ketchv1.App
+			require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "dashboard"}, &app))
+			require.Equal(t, tt.wantEnvs, app.Spec.Env)
+		})
+	}
+}
@@ -24,6 +24,46 @@ const (
 	ErrClusterIssuerNotFound cliError = "cluster issuer not found"
 
 	ErrClusterIssuerRequired cliError = "secure cnames require framework.IngressController.ClusterIssuer to be set"
+
+	ErrInvalidServiceBindingMode cliError = "invalid service binding mode, mode should be either mount or env"
+	ErrServiceBindingNotFound    cliError = "service binding not found"
+
+	ErrAppAlreadyInFramework cliError = "app already belongs to the framework"
+
+	ErrCascadeAndReassign      cliError = "--cascade and --reassign-to can't be used together"
+	ErrReassignToSameFramework cliError = "apps and jobs can't be reassigned to the framework being removed"
+
+	ErrInvalidResourceQuantity     cliError = "invalid resource quantity"
+	ErrInvalidNetworkPolicyMode    cliError = "invalid network policy mode, mode should be open, isolated or allow-same-framework"
+	ErrAllowedFrameworksInOpenMode cliError = "--allow-from-frameworks has no effect with open network policy, set --network-policy to isolated or allow-same-framework"
+	ErrInvalidKeyValue             cliError = "invalid value, expected key=value or key-"
+
+	ErrTemplateSetRequired  cliError = "exactly one of --ingress, --job or --framework must be specified"
+	ErrNoTemplates          cliError = "no templates found"
+	ErrTemplatesNotRendered cliError = "templates can't be rendered"
+
+	ErrPatchScopeRequired        cliError = "exactly one of --app or --framework must be specified"
+	ErrSelectorRequiresFramework cliError = "--selector can only be used with --framework"
+	ErrInvalidPatchName          cliError = "invalid patch name, it must be a valid DNS-1123 label"
+	ErrNoPatchFiles              cliError = "no patch files specified"
+	ErrReservedPatchFile         cliError = "patch file name is reserved for rendered manifests"
+	ErrPatchNotApplied           cliError = "patch can't be applied"
+	ErrPatchNotFound             cliError = "patch not found"
+	ErrPatchNameConflict         cliError = "the patch's configmap belongs to a patch of another app or framework"
+
+	ErrInvalidDiffOutput         cliError = "invalid output format, format should be either unified or json"
+	ErrDeploymentVersionNotFound cliError = "deployment version not found"
+	ErrDiffVersionsRequired      cliError = "both --from-version and --to-version must be set"
+
+	ErrInvalidApplySelector   cliError = "invalid selector, selector should be a list of key=value labels"
+	ErrPruneRequiresSelector  cliError = "--prune requires --selector"
+	ErrUnknownApplyType       cliError = "unknown type, type should be either Framework, Application or Job"
+	ErrDuplicateApplyResource cliError = "resource is described more than once"
+
+	ErrEnvOverlayRequiresFile cliError = "--env-overlay requires an application.yaml file"
+
+	ErrNoEnvVariables   cliError = "no env variables specified, pass NAME=VALUE arguments or --file"
+	ErrInvalidEnvFormat cliError = "invalid format, format should be either dotenv, json or yaml"
 )
 
 func unwrappedError(err error) error {
@@ -3,10 +3,13 @@ package main
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 const frameworkHelp = `
@@ -24,6 +27,7 @@ const (
 
 var (
 	defaultAppQuotaLimit = -1
+	defaultJobQuotaLimit = -1
 )
 
 func newFrameworkCmd(cfg config, out io.Writer) *cobra.Command {
@@ -41,10 +45,11 @@ func newFrameworkCmd(cfg config, out io.Writer) *cobra.Command {
 	cmd.AddCommand(newFrameworkRemoveCmd(cfg, out))
 	cmd.AddCommand(newFrameworkUpdateCmd(cfg, out))
 	cmd.AddCommand(newFrameworkExportCmd(cfg, out))
+	cmd.AddCommand(newFrameworkInfoCmd(cfg, out))
 	return cmd
 }
 
-// assignDefaultsToFramework assigns default values for namespace, version, appQuotaLimit,
+// assignDefaultsToFramework assigns default values for namespace, version, appQuotaLimit, jobQuotaLimit,
 // ingress type, and ingress className if they are not assigned. Useful when creating or modifying
 // a framework.
 func assignDefaultsToFramework(framework *ketchv1.Framework) {
@@ -58,6 +63,9 @@ func assignDefaultsToFramework(framework *ketchv1.Framework) {
 	if framework.Spec.AppQuotaLimit == nil {
 		framework.Spec.AppQuotaLimit = &defaultAppQuotaLimit
 	}
+	if framework.Spec.JobQuotaLimit == nil {
+		framework.Spec.JobQuotaLimit = &defaultJobQuotaLimit
+	}
 	if len(framework.Spec.IngressController.IngressType) == 0 {
 		framework.Spec.IngressController.IngressType = defaultTraefikIngressClassName
 	}
@@ -71,3 +79,187 @@ func assignDefaultsToFramework(framework *ketchv1.Framework) {
 		}
 	}
 }
+
+// frameworkResourceOptions contains resource quota and limit range flags shared by "framework add" and "framework update".
+type frameworkResourceOptions struct {
+	quotaCPUSet             bool
+	quotaCPU                string
+	quotaMemorySet          bool
+	quotaMemory             string
+	quotaPodsSet            bool
+	quotaPods               int64
+	defaultCPURequestSet    bool
+	defaultCPURequest       string
+	defaultMemoryRequestSet bool
+	defaultMemoryRequest    string
+	defaultCPULimitSet      bool
+	defaultCPULimit         string
+	defaultMemoryLimitSet   bool
+	defaultMemoryLimit      string
+}
+
+func (o *frameworkResourceOptions) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.quotaCPU, "quota-cpu", "", "Total amount of CPU that can be requested by all pods of this framework, e.g. 4 or 500m. Requires a default CPU request or limit. Empty value removes the limit")
+	flags.StringVar(&o.quotaMemory, "quota-memory", "", "Total amount of memory that can be requested by all pods of this framework, e.g. 8Gi. Requires a default memory request or limit. Empty value removes the limit")
+	flags.Int64Var(&o.quotaPods, "quota-pods", -1, "Maximum number of pods in this framework. Negative value removes the limit")
+	flags.StringVar(&o.defaultCPURequest, "default-cpu-request", "", "Default CPU request of containers that don't specify it")
+	flags.StringVar(&o.defaultMemoryRequest, "default-memory-request", "", "Default memory request of containers that don't specify it")
+	flags.StringVar(&o.defaultCPULimit, "default-cpu-limit", "", "Default CPU limit of containers that don't specify it")
+	flags.StringVar(&o.defaultMemoryLimit, "default-memory-limit", "", "Default memory limit of containers that don't specify it")
+}
+
+func (o *frameworkResourceOptions) setChanged(flags *pflag.FlagSet) {
+	o.quotaCPUSet = flags.Changed("quota-cpu")
+	o.quotaMemorySet = flags.Changed("quota-memory")
+	o.quotaPodsSet = flags.Changed("quota-pods")
+	o.defaultCPURequestSet = flags.Changed("default-cpu-request")
+	o.defaultMemoryRequestSet = flags.Changed("default-memory-request")
+	o.defaultCPULimitSet = flags.Changed("default-cpu-limit")
+	o.defaultMemoryLimitSet = flags.Changed("default-memory-limit")
+}
+
+// applyTo updates the framework's resource quota and limit range with the flags that were set.
+func (o frameworkResourceOptions) applyTo(spec *ketchv1.FrameworkSpec) error {
+	quota := ketchv1.FrameworkResourceQuotaSpec{}
+	if spec.ResourceQuota != nil {
+		quota = *spec.ResourceQuota
+	}
+	limitRange := ketchv1.FrameworkLimitRangeSpec{}
+	if spec.LimitRange != nil {
+		limitRange = *spec.LimitRange
+	}
+	quantities := []struct {
+		set   bool
+		flag  string
+		value string
+		field **resource.Quantity
+	}{
+		{o.quotaCPUSet, "quota-cpu", o.quotaCPU, &quota.CPU},
+		{o.quotaMemorySet, "quota-memory", o.quotaMemory, &quota.Memory},
+		{o.defaultCPURequestSet, "default-cpu-request", o.defaultCPURequest, &limitRange.DefaultCPURequest},
+		{o.defaultMemoryRequestSet, "default-memory-request", o.defaultMemoryRequest, &limitRange.DefaultMemoryRequest},
+		{o.defaultCPULimitSet, "default-cpu-limit", o.defaultCPULimit, &limitRange.DefaultCPULimit},
+		{o.defaultMemoryLimitSet, "default-memory-limit", o.defaultMemoryLimit, &limitRange.DefaultMemoryLimit},
+	}
+	for _, q := range quantities {
+		if !q.set {
+			continue
+		}
+		if len(q.value) == 0 {
+			*q.field = nil
+			continue
+		}
+		quantity, err := resource.ParseQuantity(q.value)
+		if err != nil {
+			return fmt.Errorf("%w: --%s: %v", ErrInvalidResourceQuantity, q.flag, err)
+		}
+		*q.field = &quantity
+	}
+	if o.quotaPodsSet {
+		quota.Pods = nil
+		if o.quotaPods >= 0 {
+			pods := o.quotaPods
+			quota.Pods = &pods
+		}
+	}
+	spec.ResourceQuota = nil
+	if !quota.IsEmpty() {
+		spec.ResourceQuota = &quota
+	}
+	spec.LimitRange = nil
+	if !limitRange.IsEmpty() {
+		spec.LimitRange = &limitRange
+	}
+	return spec.ValidateResources()
+}
+
+// frameworkNetworkPolicyOptions contains network policy flags shared by "framework add" and "framework update".
+type frameworkNetworkPolicyOptions struct {
+	modeSet              bool
+	mode                 string
+	allowedFrameworksSet bool
+	allowedFrameworks    []string
+}
+
+func (o *frameworkNetworkPolicyOptions) addFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&o.mode, "network-policy", "", "network policy mode: open, isolated or allow-same-framework")
+	flags.StringSliceVar(&o.allowedFrameworks, "allow-from-frameworks", nil, "comma separated list of frameworks whose pods can reach pods of this framework, requires isolated or allow-same-framework network policy")
+}
+
+func (o *frameworkNetworkPolicyOptions) setChanged(flags *pflag.FlagSet) {
+	o.modeSet = flags.Changed("network-policy")
+	o.allowedFrameworksSet = flags.Changed("allow-from-frameworks")
+}
+
+// applyTo updates the framework's network policy with the flags that were set.
+func (o frameworkNetworkPolicyOptions) applyTo(spec *ketchv1.FrameworkSpec) error {
+	if !o.modeSet && !o.allowedFrameworksSet {
+		return nil
+	}
+	policy := ketchv1.FrameworkNetworkPolicySpec{Mode: ketchv1.NetworkPolicyModeOpen}
+	if spec.NetworkPolicy != nil {
+		policy = *spec.NetworkPolicy
+	}
+	if o.modeSet {
+		mode := ketchv1.NetworkPolicyMode(o.mode)
+		switch mode {
+		case ketchv1.NetworkPolicyModeOpen, ketchv1.NetworkPolicyModeIsolated, ketchv1.NetworkPolicyModeAllowSameFramework:
+		default:
+			return ErrInvalidNetworkPolicyMode
+		}
+		policy.Mode = mode
+	}
+	if o.allowedFrameworksSet {
+		policy.AllowedFrameworks = o.allowedFrameworks
+	}
+	if policy.Mode == ketchv1.NetworkPolicyModeOpen && len(policy.AllowedFrameworks) > 0 {
+		return ErrAllowedFrameworksInOpenMode
+	}
+	spec.NetworkPolicy = &policy
+	return nil
+}
+
+// frameworkNamespaceMetadataOptions contains namespace label and annotation flags shared by "framework add" and "framework update".
+type frameworkNamespaceMetadataOptions struct {
+	labels      []string
+	annotations []string
+}
+
+func (o *frameworkNamespaceMetadataOptions) addFlags(flags *pflag.FlagSet) {
+	flags.StringArrayVar(&o.labels, "namespace-label", nil, "label of the framework's namespace in key=value format, key- removes the label. Can be repeated")
+	flags.StringArrayVar(&o.annotations, "namespace-annotation", nil, "annotation of the framework's namespace in key=value format, key- removes the annotation. Can be repeated")
+}
+
+// applyTo updates the framework's namespace labels and annotations.
+func (o frameworkNamespaceMetadataOptions) applyTo(spec *ketchv1.FrameworkSpec) error {
+	var err error
+	if spec.NamespaceLabels, err = applyKeyValues(spec.NamespaceLabels, o.labels); err != nil {
+		return err
+	}
+	if spec.NamespaceAnnotations, err = applyKeyValues(spec.NamespaceAnnotations, o.annotations); err != nil {
+		return err
+	}
+	return nil
+}
+
+// applyKeyValues sets key=value items on m and removes keys of key- items.
+func applyKeyValues(m map[string]string, items []string) (map[string]string, error) {
+	for _, item := range items {
+		if strings.HasSuffix(item, "-") && !strings.Contains(item, "=") {
+			delete(m, strings.TrimSuffix(item, "-"))
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidKeyValue, item)
+		}
+		if m == nil {
+			m = map[string]string{}
+		}
+		m[parts[0]] = parts[1]
+	}
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return m, nil
+}
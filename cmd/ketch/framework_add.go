@@ -25,6 +25,29 @@ by passing a filename such as framework.yaml containing fields like:
 	  name: istio
 	  endpoint: 10.10.10.20 # load balancer ingress ip
 	  type: istio
+	resourceQuota:
+	  cpu: "4"
+	  memory: 8Gi
+	  pods: 20
+	limitRange:
+	  defaultCPURequest: 100m
+	  defaultMemoryRequest: 128Mi
+	networkPolicy:
+	  mode: allow-same-framework
+	  allowedFrameworks:
+	  - framework2
+	templatesConfigMapName: framework1-templates # overrides default chart templates file by file
+	namespaceLabels:
+	  pod-security.kubernetes.io/enforce: baseline
+	namespaceAnnotations:
+	  linkerd.io/inject: enabled
+	processDefaults:
+	  serviceAccountName: framework1-apps
+	  securityContext:
+	    runAsNonRoot: true
+	  resources:
+	    requests:
+	      cpu: 100m
 `
 
 type ingressType enumflag.Flag
@@ -53,16 +76,23 @@ func newFrameworkAddCmd(cfg config, out io.Writer, addFramework addFrameworkFn)
 		RunE: func(cmd *cobra.Command, args []string) error {
 			options.name = args[0]
 			options.ingressClassNameSet = cmd.Flags().Changed("ingress-class-name")
+			options.resources.setChanged(cmd.Flags())
+			options.networkPolicy.setChanged(cmd.Flags())
 			return addFramework(cmd.Context(), cfg, options, out)
 		},
 	}
 	cmd.Flags().StringVar(&options.version, "version", defaultVersion, "Version for this framework")
 	cmd.Flags().StringVar(&options.namespace, "namespace", "", "Kubernetes namespace for this framework")
 	cmd.Flags().IntVar(&options.appQuotaLimit, "app-quota-limit", defaultAppQuotaLimit, "Quota limit for app when adding it to this framework")
+	cmd.Flags().IntVar(&options.jobQuotaLimit, "job-quota-limit", defaultJobQuotaLimit, "Quota limit for jobs when adding them to this framework")
 	cmd.Flags().StringVar(&options.ingressClassName, "ingress-class-name", "", `if set, it is used as kubernetes.io/ingress.class annotations. Ketch uses "istio" class name for istio ingress controller, if class name is not specified`)
 	cmd.Flags().StringVar(&options.ingressClusterIssuer, "cluster-issuer", "", "ClusterIssuer to obtain SSL certificates")
 	cmd.Flags().StringVar(&options.ingressServiceEndpoint, "ingress-service-endpoint", "", "an IP address or dns name of the ingress controller's Service")
 	cmd.Flags().Var(enumflag.New(&options.ingressType, "ingress-type", ingressTypeIds, enumflag.EnumCaseInsensitive), "ingress-type", "ingress controller type: traefik, istio or nginx")
+	cmd.Flags().StringVar(&options.ingressNamespace, "ingress-namespace", "", "namespace of the ingress controller, traffic from this namespace is allowed by network policies")
+	options.resources.addFlags(cmd.Flags())
+	options.networkPolicy.addFlags(cmd.Flags())
+	options.namespaceMetadata.addFlags(cmd.Flags())
 	cmd.RegisterFlagCompletionFunc("ingress-type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{defaultIstioIngressClassName, defaultTraefikIngressClassName, defaultNginxIngressClassName}, cobra.ShellCompDirectiveDefault
 	})
@@ -74,6 +104,7 @@ type frameworkAddOptions struct {
 	name    string // name may be a framework name (e.g. myframework) or filename (e.g. framework.yaml)
 
 	appQuotaLimit int
+	jobQuotaLimit int
 	namespace     string
 
 	ingressClassNameSet    bool
@@ -81,6 +112,11 @@ type frameworkAddOptions struct {
 	ingressClusterIssuer   string
 	ingressServiceEndpoint string
 	ingressType            ingressType
+	ingressNamespace       string
+
+	resources         frameworkResourceOptions
+	networkPolicy     frameworkNetworkPolicyOptions
+	namespaceMetadata frameworkNamespaceMetadataOptions
 }
 
 func addFramework(ctx context.Context, cfg config, options frameworkAddOptions, out io.Writer) error {
@@ -94,7 +130,10 @@ func addFramework(ctx context.Context, cfg config, options frameworkAddOptions,
 			return err
 		}
 	case validation.ValidateName(options.name):
-		framework = newFrameworkFromArgs(options)
+		framework, err = newFrameworkFromArgs(options)
+		if err != nil {
+			return err
+		}
 	default:
 		return ErrInvalidFrameworkName
 	}
@@ -123,13 +162,17 @@ func addFramework(ctx context.Context, cfg config, options frameworkAddOptions,
 // It asserts that the framework has a name. It assigns a ketch-prefixed namespaceName, version, appQuotaLimit,
 // ingressController className, and ingressController type (defaulting to traefik) if values are not specified.
 func newFrameworkFromYaml(options frameworkAddOptions) (*ketchv1.Framework, error) {
-	var framework ketchv1.Framework
 	b, err := os.ReadFile(options.name)
 	if err != nil {
 		return nil, err
 	}
+	return newFrameworkFromYamlData(b)
+}
 
-	err = yaml.Unmarshal(b, &framework.Spec)
+// newFrameworkFromYamlData imports a Framework definition from the content of a framework.yaml file.
+func newFrameworkFromYamlData(b []byte) (*ketchv1.Framework, error) {
+	var framework ketchv1.Framework
+	err := yaml.Unmarshal(b, &framework.Spec)
 	if err != nil {
 		return nil, err
 	}
@@ -144,7 +187,7 @@ func newFrameworkFromYaml(options frameworkAddOptions) (*ketchv1.Framework, erro
 
 // newFrameworkFromArgs creates a Framework from options. It creates a ketch-prefixed namespace if
 // one is not specified.
-func newFrameworkFromArgs(options frameworkAddOptions) *ketchv1.Framework {
+func newFrameworkFromArgs(options frameworkAddOptions) (*ketchv1.Framework, error) {
 	namespace := fmt.Sprintf("ketch-%s", options.name)
 	if len(options.namespace) > 0 {
 		namespace = options.namespace
@@ -159,16 +202,27 @@ func newFrameworkFromArgs(options frameworkAddOptions) *ketchv1.Framework {
 			Name:          options.name,
 			NamespaceName: namespace,
 			AppQuotaLimit: &options.appQuotaLimit,
+			JobQuotaLimit: &options.jobQuotaLimit,
 			IngressController: ketchv1.IngressControllerSpec{
 				ClassName:       options.IngressClassName(),
 				ServiceEndpoint: options.ingressServiceEndpoint,
 				ClusterIssuer:   options.ingressClusterIssuer,
 				IngressType:     options.ingressType.ingressControllerType(),
+				Namespace:       options.ingressNamespace,
 			},
 		},
 		Status: ketchv1.FrameworkStatus{},
 	}
-	return framework
+	if err := options.resources.applyTo(&framework.Spec); err != nil {
+		return nil, err
+	}
+	if err := options.networkPolicy.applyTo(&framework.Spec); err != nil {
+		return nil, err
+	}
+	if err := options.namespaceMetadata.applyTo(&framework.Spec); err != nil {
+		return nil, err
+	}
+	return framework, nil
 }
 
 func (o frameworkAddOptions) IngressClassName() string {
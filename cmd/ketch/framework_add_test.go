@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -53,6 +54,7 @@ func Test_addFramework(t *testing.T) {
 			},
 			options: frameworkAddOptions{
 				appQuotaLimit: 10,
+				jobQuotaLimit: -1,
 			},
 			yamlData: `name: hello
 ingressController:
@@ -65,6 +67,7 @@ ingressController:
 				Version:       "v1",
 				NamespaceName: "ketch-hello",
 				AppQuotaLimit: conversions.IntPtr(-1),
+				JobQuotaLimit: conversions.IntPtr(-1),
 				IngressController: ketchv1.IngressControllerSpec{
 					ClassName:       "istio",
 					ServiceEndpoint: "10.10.20.30",
@@ -100,6 +103,7 @@ ingressController:
 			options: frameworkAddOptions{
 				name:                   "hello",
 				appQuotaLimit:          5,
+				jobQuotaLimit:          -1,
 				namespace:              "gke",
 				ingressServiceEndpoint: "10.10.20.30",
 				ingressType:            istio,
@@ -109,6 +113,7 @@ ingressController:
 				Name:          "hello",
 				NamespaceName: "gke",
 				AppQuotaLimit: conversions.IntPtr(5),
+				JobQuotaLimit: conversions.IntPtr(-1),
 				IngressController: ketchv1.IngressControllerSpec{
 					ClassName:       "istio",
 					ServiceEndpoint: "10.10.20.30",
@@ -128,6 +133,7 @@ ingressController:
 			options: frameworkAddOptions{
 				name:                   "hello",
 				appQuotaLimit:          5,
+				jobQuotaLimit:          -1,
 				namespace:              "gke",
 				ingressClassNameSet:    true,
 				ingressClassName:       "custom-class-name",
@@ -139,6 +145,7 @@ ingressController:
 				Name:          "hello",
 				NamespaceName: "gke",
 				AppQuotaLimit: conversions.IntPtr(5),
+				JobQuotaLimit: conversions.IntPtr(-1),
 				IngressController: ketchv1.IngressControllerSpec{
 					ClassName:       "custom-class-name",
 					ServiceEndpoint: "10.10.20.30",
@@ -157,6 +164,7 @@ ingressController:
 			options: frameworkAddOptions{
 				name:                   "aws",
 				appQuotaLimit:          5,
+				jobQuotaLimit:          -1,
 				ingressClassName:       "traefik",
 				ingressServiceEndpoint: "10.10.10.10",
 				ingressType:            traefik,
@@ -165,6 +173,7 @@ ingressController:
 				Name:          "aws",
 				NamespaceName: "ketch-aws",
 				AppQuotaLimit: conversions.IntPtr(5),
+				JobQuotaLimit: conversions.IntPtr(-1),
 				IngressController: ketchv1.IngressControllerSpec{
 					ClassName:       "traefik",
 					ServiceEndpoint: "10.10.10.10",
@@ -284,6 +293,7 @@ ingressController:
 					Name:          "hello",
 					NamespaceName: "my-namespace",
 					AppQuotaLimit: conversions.IntPtr(5),
+					JobQuotaLimit: conversions.IntPtr(-1),
 					IngressController: ketchv1.IngressControllerSpec{
 						IngressType:     "istio",
 						ServiceEndpoint: "10.10.20.30",
@@ -317,6 +327,7 @@ ingressController:
 					Name:          "hello",
 					NamespaceName: "ketch-hello",
 					AppQuotaLimit: conversions.IntPtr(-1),
+					JobQuotaLimit: conversions.IntPtr(-1),
 					IngressController: ketchv1.IngressControllerSpec{
 						IngressType: "traefik",
 						ClassName:   "traefik",
@@ -352,6 +363,7 @@ func TestNewFrameworkFromArgs(t *testing.T) {
 		name      string
 		options   frameworkAddOptions
 		framework *ketchv1.Framework
+		wantErr   error
 	}{
 		{
 			name: "success",
@@ -359,6 +371,7 @@ func TestNewFrameworkFromArgs(t *testing.T) {
 				name:                   "hello",
 				namespace:              "my-namespace",
 				appQuotaLimit:          5,
+				jobQuotaLimit:          -1,
 				ingressType:            ingressType(1),
 				ingressServiceEndpoint: "10.10.20.30",
 				ingressClassName:       "istio",
@@ -373,6 +386,7 @@ func TestNewFrameworkFromArgs(t *testing.T) {
 					Name:          "hello",
 					NamespaceName: "my-namespace",
 					AppQuotaLimit: conversions.IntPtr(5),
+					JobQuotaLimit: conversions.IntPtr(-1),
 					IngressController: ketchv1.IngressControllerSpec{
 						IngressType:     "istio",
 						ServiceEndpoint: "10.10.20.30",
@@ -387,6 +401,7 @@ func TestNewFrameworkFromArgs(t *testing.T) {
 			options: frameworkAddOptions{
 				name:          "hello",
 				appQuotaLimit: 5,
+				jobQuotaLimit: -1,
 			},
 			framework: &ketchv1.Framework{
 				ObjectMeta: metav1.ObjectMeta{
@@ -396,6 +411,7 @@ func TestNewFrameworkFromArgs(t *testing.T) {
 					Name:          "hello",
 					NamespaceName: "ketch-hello",
 					AppQuotaLimit: conversions.IntPtr(5),
+					JobQuotaLimit: conversions.IntPtr(-1),
 					IngressController: ketchv1.IngressControllerSpec{
 						IngressType: "traefik",
 						ClassName:   "traefik",
@@ -403,12 +419,180 @@ func TestNewFrameworkFromArgs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "success - resource quota and limit range",
+			options: frameworkAddOptions{
+				name:          "hello",
+				appQuotaLimit: 5,
+				jobQuotaLimit: -1,
+				resources: frameworkResourceOptions{
+					quotaCPUSet:          true,
+					quotaCPU:             "4",
+					quotaPodsSet:         true,
+					quotaPods:            20,
+					defaultCPURequestSet: true,
+					defaultCPURequest:    "100m",
+				},
+			},
+			framework: &ketchv1.Framework{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "hello",
+				},
+				Spec: ketchv1.FrameworkSpec{
+					Name:          "hello",
+					NamespaceName: "ketch-hello",
+					AppQuotaLimit: conversions.IntPtr(5),
+					JobQuotaLimit: conversions.IntPtr(-1),
+					IngressController: ketchv1.IngressControllerSpec{
+						IngressType: "traefik",
+						ClassName:   "traefik",
+					},
+					ResourceQuota: &ketchv1.FrameworkResourceQuotaSpec{
+						CPU:  quantityRef("4"),
+						Pods: conversions.Int64Ptr(20),
+					},
+					LimitRange: &ketchv1.FrameworkLimitRangeSpec{
+						DefaultCPURequest: quantityRef("100m"),
+					},
+				},
+			},
+		},
+		{
+			name: "success - network policy",
+			options: frameworkAddOptions{
+				name:             "hello",
+				appQuotaLimit:    5,
+				jobQuotaLimit:    -1,
+				ingressNamespace: "kube-system",
+				networkPolicy: frameworkNetworkPolicyOptions{
+					modeSet:              true,
+					mode:                 "isolated",
+					allowedFrameworksSet: true,
+					allowedFrameworks:    []string{"frontend"},
+				},
+			},
+			framework: &ketchv1.Framework{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "hello",
+				},
+				Spec: ketchv1.FrameworkSpec{
+					Name:          "hello",
+					NamespaceName: "ketch-hello",
+					AppQuotaLimit: conversions.IntPtr(5),
+					JobQuotaLimit: conversions.IntPtr(-1),
+					IngressController: ketchv1.IngressControllerSpec{
+						IngressType: "traefik",
+						ClassName:   "traefik",
+						Namespace:   "kube-system",
+					},
+					NetworkPolicy: &ketchv1.FrameworkNetworkPolicySpec{
+						Mode:              ketchv1.NetworkPolicyModeIsolated,
+						AllowedFrameworks: []string{"frontend"},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid network policy mode",
+			options: frameworkAddOptions{
+				name: "hello",
+				networkPolicy: frameworkNetworkPolicyOptions{
+					modeSet: true,
+					mode:    "closed",
+				},
+			},
+			wantErr: ErrInvalidNetworkPolicyMode,
+		},
+		{
+			name: "success - namespace labels and annotations",
+			options: frameworkAddOptions{
+				name:          "hello",
+				appQuotaLimit: 5,
+				jobQuotaLimit: -1,
+				namespaceMetadata: frameworkNamespaceMetadataOptions{
+					labels:      []string{"pod-security.kubernetes.io/enforce=baseline", "cost-center=42"},
+					annotations: []string{"linkerd.io/inject=enabled"},
+				},
+			},
+			framework: &ketchv1.Framework{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "hello",
+				},
+				Spec: ketchv1.FrameworkSpec{
+					Name:          "hello",
+					NamespaceName: "ketch-hello",
+					AppQuotaLimit: conversions.IntPtr(5),
+					JobQuotaLimit: conversions.IntPtr(-1),
+					IngressController: ketchv1.IngressControllerSpec{
+						IngressType: "traefik",
+						ClassName:   "traefik",
+					},
+					NamespaceLabels:      map[string]string{"pod-security.kubernetes.io/enforce": "baseline", "cost-center": "42"},
+					NamespaceAnnotations: map[string]string{"linkerd.io/inject": "enabled"},
+				},
+			},
+		},
+		{
+			name: "invalid namespace label",
+			options: frameworkAddOptions{
+				name: "hello",
+				namespaceMetadata: frameworkNamespaceMetadataOptions{
+					labels: []string{"cost-center"},
+				},
+			},
+			wantErr: ErrInvalidKeyValue,
+		},
+		{
+			name: "invalid resource quantity",
+			options: frameworkAddOptions{
+				name: "hello",
+				resources: frameworkResourceOptions{
+					quotaMemorySet: true,
+					quotaMemory:    "lots",
+				},
+			},
+			wantErr: ErrInvalidResourceQuantity,
+		},
+		{
+			name: "allowed frameworks without network policy",
+			options: frameworkAddOptions{
+				name: "hello",
+				networkPolicy: frameworkNetworkPolicyOptions{
+					allowedFrameworksSet: true,
+					allowedFrameworks:    []string{"frontend"},
+				},
+			},
+			wantErr: ErrAllowedFrameworksInOpenMode,
+		},
+		{
+			name: "quota without default request",
+			options: frameworkAddOptions{
+				name: "hello",
+				resources: frameworkResourceOptions{
+					quotaMemorySet:       true,
+					quotaMemory:          "8Gi",
+					defaultCPURequestSet: true,
+					defaultCPURequest:    "100m",
+				},
+			},
+			wantErr: ketchv1.ErrQuotaWithoutDefaultRequest,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			res := newFrameworkFromArgs(tt.options)
+			res, err := newFrameworkFromArgs(tt.options)
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr))
+				return
+			}
+			require.Nil(t, err)
 			require.Equal(t, tt.framework, res)
 		})
 	}
 }
+
+func quantityRef(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
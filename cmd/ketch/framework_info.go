@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/theketchio/ketch/cmd/ketch/output"
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+const frameworkInfoHelp = `
+Show information about a specific framework including health of its apps and jobs and resource usage against its quota.
+`
+
+var frameworkInfoTemplate = `Framework: {{ .Framework.Name }}
+Namespace: {{ .Framework.Spec.NamespaceName }}
+{{- if .Framework.Status.Phase }}
+Status: {{ .Framework.Status.Phase }}
+{{- end }}
+Ingress controller: {{ .Framework.Spec.IngressController.IngressType }}
+{{- with .Framework.Spec.TemplatesConfigMapName }}
+Templates: {{ . }}
+{{- end }}
+Apps: {{ .Apps }}
+Jobs: {{ .Jobs }}
+{{- with .Framework.Status.Health }}
+Health: {{ .ReadyApps }}/{{ len .Apps }} apps ready, {{ .FailedJobs }} failed jobs
+{{- range .Apps }}
+  {{ .Name }}: {{ .Phase }}, {{ if .Ready }}ready{{ else }}not ready{{ end }}{{ with .Message }} ({{ . }}){{ end }}
+{{- end }}
+{{- end }}
+{{- with .Framework.Spec.NetworkPolicy }}
+Network policy: {{ .Mode }}
+{{- if .AllowedFrameworks }}
+Allowed frameworks: {{ join .AllowedFrameworks ", " }}
+{{- end }}
+{{- end }}
+{{- with .Framework.Spec.ImagePolicy }}
+{{- if .AllowedRegistries }}
+Allowed registries: {{ join .AllowedRegistries ", " }}
+{{- end }}
+{{- if .RequireDigest }}
+Image digest required: true
+{{- end }}
+{{- with .Signatures }}
+Signatures required:
+{{- if .PublicKeys }}
+  public keys: {{ len .PublicKeys }}
+{{- end }}
+{{- range .Keyless }}
+  keyless: {{ .Subject }} ({{ .Issuer }})
+{{- end }}
+{{- end }}
+{{- end }}
+{{- if .Framework.Spec.NamespaceLabels }}
+Namespace labels:
+{{- range $key, $value := .Framework.Spec.NamespaceLabels }}
+  {{ $key }}={{ $value }}
+{{- end }}
+{{- end }}
+{{- if .Framework.Spec.NamespaceAnnotations }}
+Namespace annotations:
+{{- range $key, $value := .Framework.Spec.NamespaceAnnotations }}
+  {{ $key }}={{ $value }}
+{{- end }}
+{{- end }}
+{{- if .Framework.Spec.Members }}
+Members:
+{{- range .Framework.Spec.Members }}
+  {{ .Kind }} {{ .Name }} ({{ .Role }})
+{{- end }}
+{{- end }}
+{{- with .Framework.Spec.LimitRange }}
+Default container requests: cpu={{ or .DefaultCPURequest "-" }} memory={{ or .DefaultMemoryRequest "-" }}
+Default container limits: cpu={{ or .DefaultCPULimit "-" }} memory={{ or .DefaultMemoryLimit "-" }}
+{{- end }}
+{{ if .Quota }}
+Resource quota:
+{{- else }}
+No resource quota.
+{{- end }}
+`
+
+type frameworkInfoContext struct {
+	Framework ketchv1.Framework
+	Apps      string
+	Jobs      string
+	Quota     []frameworkQuotaOutput
+}
+
+type frameworkQuotaOutput struct {
+	Resource string `json:"resource" yaml:"resource"`
+	Used     string `json:"used" yaml:"used"`
+	Hard     string `json:"hard" yaml:"hard"`
+}
+
+func newFrameworkInfoCmd(cfg config, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "info FRAMEWORK",
+		Short: "Show information about a specific framework.",
+		Args:  cobra.ExactArgs(1),
+		Long:  frameworkInfoHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return frameworkInfo(cmd.Context(), cfg, args[0], out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return autoCompleteFrameworkNames(cfg, toComplete)
+		},
+	}
+	return cmd
+}
+
+func frameworkInfo(ctx context.Context, cfg config, frameworkName string, out io.Writer) error {
+	var framework ketchv1.Framework
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: frameworkName}, &framework); err != nil {
+		return fmt.Errorf("failed to get framework: %w", err)
+	}
+	quota, err := cfg.KubernetesClient().CoreV1().ResourceQuotas(framework.Spec.NamespaceName).Get(ctx, ketchv1.FrameworkResourceQuotaName, metav1.GetOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get resource quota: %w", err)
+	}
+	if k8sErrors.IsNotFound(err) {
+		quota = nil
+	}
+
+	data := generateFrameworkInfoOutput(framework, quota)
+	buf := bytes.Buffer{}
+	t := template.Must(template.New("framework-info").Funcs(template.FuncMap{"join": strings.Join}).Parse(frameworkInfoTemplate))
+	if err := t.Execute(&buf, data); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%v", buf.String())
+	if len(data.Quota) == 0 {
+		return nil
+	}
+	return output.Write(data.Quota, out, "column")
+}
+
+func generateFrameworkInfoOutput(framework ketchv1.Framework, quota *v1.ResourceQuota) frameworkInfoContext {
+	apps := fmt.Sprintf("%d", len(framework.Status.Apps))
+	if framework.Spec.AppQuotaLimit != nil && *framework.Spec.AppQuotaLimit > 0 {
+		apps = fmt.Sprintf("%d/%d", len(framework.Status.Apps), *framework.Spec.AppQuotaLimit)
+	}
+	jobs := fmt.Sprintf("%d", len(framework.Status.Jobs))
+	if framework.Spec.JobQuotaLimit != nil && *framework.Spec.JobQuotaLimit > 0 {
+		jobs = fmt.Sprintf("%d/%d", len(framework.Status.Jobs), *framework.Spec.JobQuotaLimit)
+	}
+	infoContext := frameworkInfoContext{
+		Framework: framework,
+		Apps:      apps,
+		Jobs:      jobs,
+	}
+	if quota == nil {
+		return infoContext
+	}
+	for name, hard := range quota.Spec.Hard {
+		used := "0"
+		if quantity, ok := quota.Status.Used[name]; ok {
+			used = quantity.String()
+		}
+		infoContext.Quota = append(infoContext.Quota, frameworkQuotaOutput{
+			Resource: name.String(),
+			Used:     used,
+			Hard:     hard.String(),
+		})
+	}
+	sort.Slice(infoContext.Quota, func(i, j int) bool {
+		return infoContext.Quota[i].Resource < infoContext.Quota[j].Resource
+	})
+	return infoContext
+}
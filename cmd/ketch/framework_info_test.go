@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/utils/conversions"
+)
+
+func Test_frameworkInfo(t *testing.T) {
+	framework := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "team-a",
+		},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-team-a",
+			AppQuotaLimit: conversions.IntPtr(10),
+			IngressController: ketchv1.IngressControllerSpec{
+				IngressType: ketchv1.TraefikIngressControllerType,
+			},
+			ResourceQuota: &ketchv1.FrameworkResourceQuotaSpec{
+				CPU:  quantityRef("4"),
+				Pods: conversions.Int64Ptr(20),
+			},
+			LimitRange: &ketchv1.FrameworkLimitRangeSpec{
+				DefaultCPURequest: quantityRef("100m"),
+			},
+			NetworkPolicy: &ketchv1.FrameworkNetworkPolicySpec{
+				Mode:              ketchv1.NetworkPolicyModeIsolated,
+				AllowedFrameworks: []string{"team-b", "team-c"},
+			},
+			Members: []ketchv1.FrameworkMember{
+				{Kind: ketchv1.FrameworkMemberGroup, Name: "team-a", Role: ketchv1.FrameworkRoleDeveloper},
+			},
+		},
+		Status: ketchv1.FrameworkStatus{
+			Phase: ketchv1.FrameworkCreated,
+			Apps:  []string{"app-1", "app-2"},
+			Health: &ketchv1.FrameworkHealth{
+				Apps: []ketchv1.FrameworkAppHealth{
+					{Name: "app-1", Phase: ketchv1.AppRunning, Ready: true},
+					{Name: "app-2", Phase: ketchv1.AppError, Message: "failed to render chart"},
+				},
+				ReadyApps:  1,
+				FailedJobs: 1,
+			},
+		},
+	}
+	quota := &v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ketchv1.FrameworkResourceQuotaName,
+			Namespace: "ketch-team-a",
+		},
+		Spec: v1.ResourceQuotaSpec{
+			Hard: framework.Spec.ResourceQuota.Hard(),
+		},
+		Status: v1.ResourceQuotaStatus{
+			Used: v1.ResourceList{
+				v1.ResourceCPU:  resource.MustParse("1500m"),
+				v1.ResourcePods: resource.MustParse("3"),
+			},
+		},
+	}
+	noQuotaFramework := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "team-b",
+		},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-team-b",
+			AppQuotaLimit: conversions.IntPtr(-1),
+			IngressController: ketchv1.IngressControllerSpec{
+				IngressType: ketchv1.IstioIngressControllerType,
+			},
+			ImagePolicy: &ketchv1.ImagePolicy{
+				AllowedRegistries: []string{"gcr.io/team-b"},
+				Signatures: &ketchv1.SignaturePolicy{
+					PublicKeys: []string{"-----BEGIN PUBLIC KEY-----"},
+					Keyless: []ketchv1.KeylessIdentity{
+						{Issuer: "https://token.actions.githubusercontent.com", Subject: "ci@team-b.io"},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		frameworkName string
+		cfg           config
+		wantOut       string
+		wantErr       bool
+	}{
+		{
+			name:          "framework with resource quota",
+			frameworkName: "team-a",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{framework},
+				KubeClientObjects: []runtime.Object{quota},
+			},
+			wantOut: `Framework: team-a
+Namespace: ketch-team-a
+Status: Created
+Ingress controller: traefik
+Apps: 2/10
+Jobs: 0
+Health: 1/2 apps ready, 1 failed jobs
+  app-1: Running, ready
+  app-2: Error, not ready (failed to render chart)
+Network policy: isolated
+Allowed frameworks: team-b, team-c
+Members:
+  Group team-a (developer)
+Default container requests: cpu=100m memory=-
+Default container limits: cpu=- memory=-
+
+Resource quota:
+RESOURCE    USED     HARD
+cpu         1500m    4
+pods        3        20
+`,
+		},
+		{
+			name:          "framework without resource quota",
+			frameworkName: "team-b",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{noQuotaFramework},
+			},
+			wantOut: `Framework: team-b
+Namespace: ketch-team-b
+Ingress controller: istio
+Apps: 0
+Jobs: 0
+Allowed registries: gcr.io/team-b
+Signatures required:
+  public keys: 1
+  keyless: ci@team-b.io (https://token.actions.githubusercontent.com)
+
+No resource quota.
+`,
+		},
+		{
+			name:          "no framework",
+			frameworkName: "team-c",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{framework},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := frameworkInfo(context.Background(), tt.cfg, tt.frameworkName, out)
+			if tt.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantOut, out.String())
+		})
+	}
+}
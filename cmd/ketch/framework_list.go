@@ -24,6 +24,7 @@ type frameworkListOutput struct {
 	IngressClassName string `json:"ingressClassName" yaml:"ingressClassName"`
 	ClusterIssuer    string `json:"clusterIssuer" yaml:"clusterIssuer"`
 	Apps             string `json:"apps" yaml:"apps"`
+	Jobs             string `json:"jobs" yaml:"jobs"`
 }
 
 func newFrameworkListCmd(cfg config, out io.Writer) *cobra.Command {
@@ -53,6 +54,10 @@ func generateFrameworkListOutput(frameworks ketchv1.FrameworkList) []frameworkLi
 		if item.Spec.AppQuotaLimit != nil && *item.Spec.AppQuotaLimit > 0 {
 			apps = fmt.Sprintf("%d/%d", len(item.Status.Apps), *item.Spec.AppQuotaLimit)
 		}
+		jobs := fmt.Sprintf("%d", len(item.Status.Jobs))
+		if item.Spec.JobQuotaLimit != nil && *item.Spec.JobQuotaLimit > 0 {
+			jobs = fmt.Sprintf("%d/%d", len(item.Status.Jobs), *item.Spec.JobQuotaLimit)
+		}
 		output = append(output, frameworkListOutput{
 			Name:             item.Name,
 			Status:           string(item.Status.Phase),
@@ -61,6 +66,7 @@ func generateFrameworkListOutput(frameworks ketchv1.FrameworkList) []frameworkLi
 			IngressClassName: item.Spec.IngressController.ClassName,
 			ClusterIssuer:    item.Spec.IngressController.ClusterIssuer,
 			Apps:             apps,
+			Jobs:             jobs,
 		})
 	}
 	return output
@@ -23,6 +23,7 @@ func Test_frameworkList(t *testing.T) {
 		Spec: ketchv1.FrameworkSpec{
 			NamespaceName: "a",
 			AppQuotaLimit: conversions.IntPtr(30),
+			JobQuotaLimit: conversions.IntPtr(5),
 			IngressController: ketchv1.IngressControllerSpec{
 				ClassName:       "istio",
 				ServiceEndpoint: "192.168.1.17",
@@ -46,6 +47,9 @@ func Test_frameworkList(t *testing.T) {
 				IngressType:     ketchv1.TraefikIngressControllerType,
 			},
 		},
+		Status: ketchv1.FrameworkStatus{
+			Jobs: []string{"job-1"},
+		},
 	}
 	tests := []struct {
 		name string
@@ -59,9 +63,9 @@ func Test_frameworkList(t *testing.T) {
 			cfg: &mocks.Configuration{
 				CtrlClientObjects: []runtime.Object{frameworkA, frameworkB},
 			},
-			wantOut: `NAME           STATUS    NAMESPACE    INGRESS TYPE    INGRESS CLASS NAME    CLUSTER ISSUER    APPS
-framework-a              a            istio           istio                 letsencrypt       0/30
-framework-b              b            traefik         classname-b           letsencrypt       0/30
+			wantOut: `NAME           STATUS    NAMESPACE    INGRESS TYPE    INGRESS CLASS NAME    CLUSTER ISSUER    APPS    JOBS
+framework-a              a            istio           istio                 letsencrypt       0/30    0/5
+framework-b              b            traefik         classname-b           letsencrypt       0/30    1
 `,
 		},
 	}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -17,8 +19,14 @@ import (
 const (
 	frameworkRemoveHelp = `
 Remove an existing framework.
+Use "--cascade" to remove the framework's apps and jobs first, or "--reassign-to" to move them to another framework.
+The framework is kept until all its apps and jobs are removed or moved.
+Removing the framework's namespace is offered only once the framework has no apps or jobs left.
 `
 	skipNsRemovalMsg = "Skipping namespace removal..."
+
+	defaultFrameworkRemoveTimeout = 5 * time.Minute
+	frameworkRemovePollInterval   = time.Second
 )
 
 func newFrameworkRemoveCmd(cfg config, out io.Writer) *cobra.Command {
@@ -36,11 +44,20 @@ func newFrameworkRemoveCmd(cfg config, out io.Writer) *cobra.Command {
 			return autoCompleteFrameworkNames(cfg, toComplete)
 		},
 	}
+	cmd.Flags().BoolVar(&options.cascade, "cascade", false, "Remove the framework's apps and jobs and wait until they are uninstalled.")
+	cmd.Flags().StringVar(&options.reassignTo, "reassign-to", "", "Move the framework's apps and jobs to another framework.")
+	cmd.Flags().DurationVar(&options.timeout, "timeout", defaultFrameworkRemoveTimeout, "How long to wait for the framework's apps and jobs to be removed or moved.")
+	cmd.RegisterFlagCompletionFunc("reassign-to", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return autoCompleteFrameworkNames(cfg, toComplete)
+	})
 	return cmd
 }
 
 type frameworkRemoveOptions struct {
-	Name string
+	Name       string
+	cascade    bool
+	reassignTo string
+	timeout    time.Duration
 }
 
 func frameworkRemove(ctx context.Context, cfg config, options frameworkRemoveOptions, out io.Writer) error {
@@ -50,13 +67,40 @@ func frameworkRemove(ctx context.Context, cfg config, options frameworkRemoveOpt
 		return fmt.Errorf("failed to get framework: %w", err)
 	}
 
+	if options.cascade && len(options.reassignTo) > 0 {
+		return ErrCascadeAndReassign
+	}
+	if options.cascade || len(options.reassignTo) > 0 {
+		var err error
+		if options.cascade {
+			err = removeFrameworkWorkloads(ctx, cfg, framework, out)
+		} else {
+			err = reassignFrameworkWorkloads(ctx, cfg, framework, options.reassignTo, out)
+		}
+		if err != nil {
+			return err
+		}
+		if err := waitForFrameworkWorkloads(ctx, cfg, framework.Name, options.timeout); err != nil {
+			return err
+		}
+		if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.Name}, &framework); err != nil {
+			return fmt.Errorf("failed to get framework: %w", err)
+		}
+	}
+
 	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		return pruneRemovedAppsFromStatus(ctx, cfg, framework)
 	}); err != nil {
 		return fmt.Errorf("failed to prune framework's apps: %w", err)
 	}
 
-	if userWantsToRemoveNamespace(framework.Spec.NamespaceName, out) {
+	apps, jobs, err := frameworkWorkloads(ctx, cfg, framework)
+	if err != nil {
+		return err
+	}
+
+	// the namespace runs the remaining apps and jobs, so it isn't removed until they are removed or moved
+	if len(apps) == 0 && len(jobs) == 0 && userWantsToRemoveNamespace(framework.Spec.NamespaceName, out) {
 		if err := checkNamespaceAdditionalFrameworks(ctx, cfg, &framework); err != nil {
 			printNsRemovalErr(out, err)
 		} else {
@@ -72,11 +116,28 @@ func frameworkRemove(ctx context.Context, cfg config, options frameworkRemoveOpt
 		return fmt.Errorf("failed to remove the framework: %w", err)
 	}
 
+	if len(apps) > 0 || len(jobs) > 0 {
+		printPendingFrameworkRemoval(out, apps, jobs)
+		return nil
+	}
+
 	fmt.Fprintln(out, "Framework successfully removed!")
 
 	return nil
 }
 
+// printPendingFrameworkRemoval lists apps and jobs that keep the framework from being removed.
+func printPendingFrameworkRemoval(out io.Writer, apps []ketchv1.App, jobs []ketchv1.Job) {
+	fmt.Fprintln(out, "Framework removal is pending until its apps and jobs are removed or moved to another framework:")
+	for _, app := range apps {
+		fmt.Fprintf(out, "  app %q\n", app.Name)
+	}
+	for _, job := range jobs {
+		fmt.Fprintf(out, "  job %q\n", job.Name)
+	}
+	fmt.Fprintln(out, `Use "--cascade" to remove them or "--reassign-to" to move them.`)
+}
+
 func userWantsToRemoveNamespace(ns string, out io.Writer) bool {
 	response := promptToRemoveNamespace(ns, out)
 	return handleNamespaceRemovalResponse(response, ns, out)
@@ -155,3 +216,103 @@ func pruneRemovedAppsFromStatus(ctx context.Context, cfg config, framework ketch
 	patch := client.MergeFrom(&framework)
 	return cfg.Client().Status().Patch(ctx, &patchedFramework, patch)
 }
+
+// frameworkWorkloads returns apps and jobs owned by the framework.
+func frameworkWorkloads(ctx context.Context, cfg config, framework ketchv1.Framework) ([]ketchv1.App, []ketchv1.Job, error) {
+	var apps ketchv1.AppList
+	if err := cfg.Client().List(ctx, &apps); err != nil {
+		return nil, nil, fmt.Errorf("failed to list framework apps: %w", err)
+	}
+	var jobs ketchv1.JobList
+	if err := cfg.Client().List(ctx, &jobs); err != nil {
+		return nil, nil, fmt.Errorf("failed to list framework jobs: %w", err)
+	}
+	var ownedApps []ketchv1.App
+	for _, app := range apps.Items {
+		if framework.OwnsApp(app) {
+			ownedApps = append(ownedApps, app)
+		}
+	}
+	var ownedJobs []ketchv1.Job
+	for _, job := range jobs.Items {
+		if framework.OwnsJob(job) {
+			ownedJobs = append(ownedJobs, job)
+		}
+	}
+	return ownedApps, ownedJobs, nil
+}
+
+// removeFrameworkWorkloads removes apps and jobs of the framework.
+func removeFrameworkWorkloads(ctx context.Context, cfg config, framework ketchv1.Framework, out io.Writer) error {
+	apps, jobs, err := frameworkWorkloads(ctx, cfg, framework)
+	if err != nil {
+		return err
+	}
+	for i := range apps {
+		if err := client.IgnoreNotFound(cfg.Client().Delete(ctx, &apps[i])); err != nil {
+			return fmt.Errorf("failed to remove app %q: %w", apps[i].Name, err)
+		}
+		fmt.Fprintf(out, "Removing app %q...\n", apps[i].Name)
+	}
+	for i := range jobs {
+		if err := client.IgnoreNotFound(cfg.Client().Delete(ctx, &jobs[i])); err != nil {
+			return fmt.Errorf("failed to remove job %q: %w", jobs[i].Name, err)
+		}
+		fmt.Fprintf(out, "Removing job %q...\n", jobs[i].Name)
+	}
+	return nil
+}
+
+// reassignFrameworkWorkloads moves apps and jobs of the framework to the target framework.
+func reassignFrameworkWorkloads(ctx context.Context, cfg config, framework ketchv1.Framework, targetName string, out io.Writer) error {
+	if targetName == framework.Name {
+		return ErrReassignToSameFramework
+	}
+	var target ketchv1.Framework
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: targetName}, &target); err != nil {
+		return fmt.Errorf("failed to get the framework: %w", err)
+	}
+	apps, jobs, err := frameworkWorkloads(ctx, cfg, framework)
+	if err != nil {
+		return err
+	}
+	for i := range apps {
+		if apps[i].Spec.Framework != framework.Name {
+			continue
+		}
+		if err := moveApp(ctx, cfg, &apps[i], framework, target); err != nil {
+			return fmt.Errorf("failed to move app %q: %w", apps[i].Name, err)
+		}
+		fmt.Fprintf(out, "Moving app %q to %q framework...\n", apps[i].Name, target.Name)
+	}
+	for i := range jobs {
+		if jobs[i].Spec.Framework != framework.Name {
+			continue
+		}
+		jobs[i].Spec.Framework = target.Name
+		if err := cfg.Client().Update(ctx, &jobs[i]); err != nil {
+			return fmt.Errorf("failed to move job %q: %w", jobs[i].Name, err)
+		}
+		fmt.Fprintf(out, "Moving job %q to %q framework...\n", jobs[i].Name, target.Name)
+	}
+	return nil
+}
+
+// waitForFrameworkWorkloads waits until the framework doesn't own any apps or jobs.
+func waitForFrameworkWorkloads(ctx context.Context, cfg config, name string, timeout time.Duration) error {
+	err := wait.PollImmediate(frameworkRemovePollInterval, timeout, func() (bool, error) {
+		var framework ketchv1.Framework
+		if err := cfg.Client().Get(ctx, types.NamespacedName{Name: name}, &framework); err != nil {
+			return false, fmt.Errorf("failed to get framework: %w", err)
+		}
+		apps, jobs, err := frameworkWorkloads(ctx, cfg, framework)
+		if err != nil {
+			return false, err
+		}
+		return len(apps) == 0 && len(jobs) == 0, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out waiting for the framework's apps and jobs to be removed")
+	}
+	return err
+}
@@ -3,11 +3,15 @@ package main
 import (
 	"bytes"
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"gotest.tools/assert"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 	"github.com/theketchio/ketch/internal/mocks"
@@ -90,3 +94,122 @@ func TestFrameworkRemove(t *testing.T) {
 		})
 	}
 }
+
+func TestFrameworkRemoveWorkloads(t *testing.T) {
+	newFramework := func(name string) *ketchv1.Framework {
+		return &ketchv1.Framework{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-" + name},
+		}
+	}
+	newApp := func(name, framework string) *ketchv1.App {
+		return &ketchv1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       ketchv1.AppSpec{Framework: framework},
+		}
+	}
+	newJob := func(name, framework string) *ketchv1.Job {
+		return &ketchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       ketchv1.JobSpec{Framework: framework},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		cfg      config
+		options  frameworkRemoveOptions
+		wantApps map[string]string
+		wantJobs map[string]string
+		wantOut  string
+		wantErr  string
+	}{
+		{
+			name: "cascade",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{newFramework("gke"), newApp("app-1", "gke"), newApp("app-2", "aws"), newJob("job-1", "gke")},
+			},
+			options:  frameworkRemoveOptions{Name: "gke", cascade: true, timeout: time.Second},
+			wantApps: map[string]string{"app-2": "aws"},
+			wantJobs: map[string]string{},
+			wantOut:  "Removing app \"app-1\"...\nRemoving job \"job-1\"...\n",
+		},
+		{
+			name: "reassign",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{newFramework("gke"), newFramework("aws"), newApp("app-1", "gke"), newJob("job-1", "gke")},
+			},
+			options:  frameworkRemoveOptions{Name: "gke", reassignTo: "aws", timeout: time.Second},
+			wantApps: map[string]string{"app-1": "aws"},
+			wantJobs: map[string]string{"job-1": "aws"},
+			wantOut:  "Moving app \"app-1\" to \"aws\" framework...\nMoving job \"job-1\" to \"aws\" framework...\n",
+		},
+		{
+			name: "without cascade the removal is pending",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{newFramework("gke"), newApp("app-1", "gke"), newJob("job-1", "gke")},
+			},
+			options:  frameworkRemoveOptions{Name: "gke", timeout: time.Second},
+			wantApps: map[string]string{"app-1": "gke"},
+			wantJobs: map[string]string{"job-1": "gke"},
+			// the namespace removal isn't offered while the framework owns apps and jobs
+			wantOut: "Framework removal is pending until its apps and jobs are removed or moved to another framework:\n  app \"app-1\"\n  job \"job-1\"\nUse \"--cascade\" to remove them or \"--reassign-to\" to move them.\n",
+		},
+		{
+			name: "reassign to missing framework",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{newFramework("gke"), newApp("app-1", "gke")},
+			},
+			options: frameworkRemoveOptions{Name: "gke", reassignTo: "aws", timeout: time.Second},
+			wantErr: `failed to get the framework: frameworks.theketch.io "aws" not found`,
+		},
+		{
+			name: "reassign to the same framework",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{newFramework("gke")},
+			},
+			options: frameworkRemoveOptions{Name: "gke", reassignTo: "gke", timeout: time.Second},
+			wantErr: ErrReassignToSameFramework.Error(),
+		},
+		{
+			name: "cascade and reassign",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{newFramework("gke")},
+			},
+			options: frameworkRemoveOptions{Name: "gke", cascade: true, reassignTo: "aws"},
+			wantErr: ErrCascadeAndReassign.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := frameworkRemove(context.Background(), tt.cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				assert.Error(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Assert(t, strings.HasPrefix(out.String(), tt.wantOut))
+
+			var apps ketchv1.AppList
+			assert.NilError(t, tt.cfg.Client().List(context.Background(), &apps))
+			gotApps := map[string]string{}
+			for _, app := range apps.Items {
+				gotApps[app.Name] = app.Spec.Framework
+			}
+			assert.DeepEqual(t, tt.wantApps, gotApps)
+
+			var jobs ketchv1.JobList
+			assert.NilError(t, tt.cfg.Client().List(context.Background(), &jobs))
+			gotJobs := map[string]string{}
+			for _, job := range jobs.Items {
+				gotJobs[job.Name] = job.Spec.Framework
+			}
+			assert.DeepEqual(t, tt.wantJobs, gotJobs)
+
+			var framework ketchv1.Framework
+			err = tt.cfg.Client().Get(context.Background(), types.NamespacedName{Name: tt.options.Name}, &framework)
+			assert.Assert(t, errors.IsNotFound(err))
+		})
+	}
+}
@@ -24,6 +24,10 @@ by passing a filename such as framework.yaml containing fields like:
 	  name: istio
 	  endpoint: 10.10.10.20 # load balancer ingress ip
 	  type: istio
+	resourceQuota:
+	  cpu: "4"
+	  memory: 8Gi
+	  pods: 20
 `
 
 func newFrameworkUpdateCmd(cfg config, out io.Writer) *cobra.Command {
@@ -36,11 +40,15 @@ func newFrameworkUpdateCmd(cfg config, out io.Writer) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			options.name = args[0]
 			options.appQuotaLimitSet = cmd.Flags().Changed("app-quota-limit")
+			options.jobQuotaLimitSet = cmd.Flags().Changed("job-quota-limit")
 			options.namespaceSet = cmd.Flags().Changed("namespace")
 			options.ingressClassNameSet = cmd.Flags().Changed("ingress-class-name")
 			options.ingressServiceEndpointSet = cmd.Flags().Changed("ingress-service-endpoint")
 			options.ingressTypeSet = cmd.Flags().Changed("ingress-type")
 			options.ingressClusterIssuerSet = cmd.Flags().Changed("cluster-issuer")
+			options.ingressNamespaceSet = cmd.Flags().Changed("ingress-namespace")
+			options.resources.setChanged(cmd.Flags())
+			options.networkPolicy.setChanged(cmd.Flags())
 			return frameworkUpdate(cmd.Context(), cfg, options, out)
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -49,10 +57,15 @@ func newFrameworkUpdateCmd(cfg config, out io.Writer) *cobra.Command {
 	}
 	cmd.Flags().StringVar(&options.namespace, "namespace", "", "Kubernetes namespace for this framework")
 	cmd.Flags().IntVar(&options.appQuotaLimit, "app-quota-limit", 0, "Quota limit for app when adding it to this framework")
+	cmd.Flags().IntVar(&options.jobQuotaLimit, "job-quota-limit", 0, "Quota limit for jobs when adding them to this framework")
 	cmd.Flags().StringVar(&options.ingressClassName, "ingress-class-name", "", "if set, it is used as kubernetes.io/ingress.class annotations")
 	cmd.Flags().StringVar(&options.ingressServiceEndpoint, "ingress-service-endpoint", "", "an IP address or dns name of the ingress controller's Service")
 	cmd.Flags().StringVar(&options.ingressClusterIssuer, "cluster-issuer", "", "ClusterIssuer to obtain SSL certificates")
 	cmd.Flags().Var(enumflag.New(&options.ingressType, "ingress-type", ingressTypeIds, enumflag.EnumCaseInsensitive), "ingress-type", "ingress controller type: traefik or istio")
+	cmd.Flags().StringVar(&options.ingressNamespace, "ingress-namespace", "", "namespace of the ingress controller, traffic from this namespace is allowed by network policies")
+	options.resources.addFlags(cmd.Flags())
+	options.networkPolicy.addFlags(cmd.Flags())
+	options.namespaceMetadata.addFlags(cmd.Flags())
 	cmd.RegisterFlagCompletionFunc("ingress-type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{defaultIstioIngressClassName, defaultTraefikIngressClassName, defaultNginxIngressClassName}, cobra.ShellCompDirectiveDefault
 	})
@@ -64,6 +77,8 @@ type frameworkUpdateOptions struct {
 
 	appQuotaLimitSet          bool
 	appQuotaLimit             int
+	jobQuotaLimitSet          bool
+	jobQuotaLimit             int
 	namespaceSet              bool
 	namespace                 string
 	ingressClassNameSet       bool
@@ -74,6 +89,12 @@ type frameworkUpdateOptions struct {
 	ingressServiceEndpoint    string
 	ingressTypeSet            bool
 	ingressType               ingressType
+	ingressNamespaceSet       bool
+	ingressNamespace          string
+
+	resources         frameworkResourceOptions
+	networkPolicy     frameworkNetworkPolicyOptions
+	namespaceMetadata frameworkNamespaceMetadataOptions
 }
 
 func frameworkUpdate(ctx context.Context, cfg config, options frameworkUpdateOptions, out io.Writer) error {
@@ -150,6 +171,9 @@ func updateFrameworkFromArgs(ctx context.Context, cfg config, options frameworkU
 	if options.appQuotaLimitSet {
 		framework.Spec.AppQuotaLimit = &options.appQuotaLimit
 	}
+	if options.jobQuotaLimitSet {
+		framework.Spec.JobQuotaLimit = &options.jobQuotaLimit
+	}
 	if options.namespaceSet {
 		framework.Spec.NamespaceName = options.namespace
 	}
@@ -165,5 +189,17 @@ func updateFrameworkFromArgs(ctx context.Context, cfg config, options frameworkU
 	if options.ingressClusterIssuerSet {
 		framework.Spec.IngressController.ClusterIssuer = options.ingressClusterIssuer
 	}
+	if options.ingressNamespaceSet {
+		framework.Spec.IngressController.Namespace = options.ingressNamespace
+	}
+	if err := options.resources.applyTo(&framework.Spec); err != nil {
+		return nil, err
+	}
+	if err := options.networkPolicy.applyTo(&framework.Spec); err != nil {
+		return nil, err
+	}
+	if err := options.namespaceMetadata.applyTo(&framework.Spec); err != nil {
+		return nil, err
+	}
 	return &framework, nil
 }
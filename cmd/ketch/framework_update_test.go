@@ -92,6 +92,7 @@ ingressController:
 				Version:       "v1",
 				NamespaceName: "ketch-frontend-framework",
 				AppQuotaLimit: conversions.IntPtr(30),
+				JobQuotaLimit: conversions.IntPtr(-1),
 				IngressController: ketchv1.IngressControllerSpec{
 					ClassName:       "default-classname",
 					ServiceEndpoint: "192.168.1.18",
@@ -124,6 +125,32 @@ ingressController:
 				},
 			},
 		},
+		{
+			name:          "update namespace labels",
+			frameworkName: "frontend-framework",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{&ketchv1.Framework{
+					ObjectMeta: metav1.ObjectMeta{Name: "frontend-framework"},
+					Spec: ketchv1.FrameworkSpec{
+						NamespaceName:   "frontend",
+						NamespaceLabels: map[string]string{"cost-center": "42", "team": "web"},
+					},
+				}},
+			},
+			options: frameworkUpdateOptions{
+				name: "frontend-framework",
+				namespaceMetadata: frameworkNamespaceMetadataOptions{
+					labels:      []string{"team-", "pod-security.kubernetes.io/enforce=baseline"},
+					annotations: []string{"linkerd.io/inject=enabled"},
+				},
+			},
+			wantOut: "Successfully updated!\n",
+			wantFrameworkSpec: ketchv1.FrameworkSpec{
+				NamespaceName:        "frontend",
+				NamespaceLabels:      map[string]string{"cost-center": "42", "pod-security.kubernetes.io/enforce": "baseline"},
+				NamespaceAnnotations: map[string]string{"linkerd.io/inject": "enabled"},
+			},
+		},
 		{
 			name:          "update ingress class name",
 			frameworkName: "frontend-framework",
@@ -196,6 +223,97 @@ ingressController:
 				},
 			},
 		},
+		{
+			name:          "update job quota",
+			frameworkName: "frontend-framework",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects:    []runtime.Object{frontendFramework},
+				DynamicClientObjects: []runtime.Object{clusterIssuerStaging},
+			},
+			options: frameworkUpdateOptions{
+				name:             "frontend-framework",
+				jobQuotaLimitSet: true,
+				jobQuotaLimit:    3,
+			},
+			wantOut: "Successfully updated!\n",
+			wantFrameworkSpec: ketchv1.FrameworkSpec{
+				NamespaceName: "frontend",
+				AppQuotaLimit: conversions.IntPtr(30),
+				JobQuotaLimit: conversions.IntPtr(3),
+				IngressController: ketchv1.IngressControllerSpec{
+					ClassName:       "default-classname",
+					ServiceEndpoint: "192.168.1.17",
+					IngressType:     ketchv1.IstioIngressControllerType,
+					ClusterIssuer:   "le-staging",
+				},
+			},
+		},
+		{
+			name:          "update resource quota and default limits",
+			frameworkName: "frontend-framework",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects:    []runtime.Object{frontendFramework},
+				DynamicClientObjects: []runtime.Object{clusterIssuerStaging},
+			},
+			options: frameworkUpdateOptions{
+				name: "frontend-framework",
+				resources: frameworkResourceOptions{
+					quotaMemorySet:        true,
+					quotaMemory:           "8Gi",
+					defaultMemoryLimitSet: true,
+					defaultMemoryLimit:    "1Gi",
+				},
+			},
+			wantOut: "Successfully updated!\n",
+			wantFrameworkSpec: ketchv1.FrameworkSpec{
+				NamespaceName: "frontend",
+				AppQuotaLimit: conversions.IntPtr(30),
+				IngressController: ketchv1.IngressControllerSpec{
+					ClassName:       "default-classname",
+					ServiceEndpoint: "192.168.1.17",
+					IngressType:     ketchv1.IstioIngressControllerType,
+					ClusterIssuer:   "le-staging",
+				},
+				ResourceQuota: &ketchv1.FrameworkResourceQuotaSpec{
+					Memory: quantityRef("8Gi"),
+				},
+				LimitRange: &ketchv1.FrameworkLimitRangeSpec{
+					DefaultMemoryLimit: quantityRef("1Gi"),
+				},
+			},
+		},
+		{
+			name:          "update network policy",
+			frameworkName: "frontend-framework",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects:    []runtime.Object{frontendFramework},
+				DynamicClientObjects: []runtime.Object{clusterIssuerStaging},
+			},
+			options: frameworkUpdateOptions{
+				name:                "frontend-framework",
+				ingressNamespaceSet: true,
+				ingressNamespace:    "istio-ingress",
+				networkPolicy: frameworkNetworkPolicyOptions{
+					modeSet: true,
+					mode:    "allow-same-framework",
+				},
+			},
+			wantOut: "Successfully updated!\n",
+			wantFrameworkSpec: ketchv1.FrameworkSpec{
+				NamespaceName: "frontend",
+				AppQuotaLimit: conversions.IntPtr(30),
+				IngressController: ketchv1.IngressControllerSpec{
+					ClassName:       "default-classname",
+					ServiceEndpoint: "192.168.1.17",
+					IngressType:     ketchv1.IstioIngressControllerType,
+					ClusterIssuer:   "le-staging",
+					Namespace:       "istio-ingress",
+				},
+				NetworkPolicy: &ketchv1.FrameworkNetworkPolicySpec{
+					Mode: ketchv1.NetworkPolicyModeAllowSameFramework,
+				},
+			},
+		},
 		{
 			name:          "update ingress type",
 			frameworkName: "frontend-framework",
@@ -352,6 +470,7 @@ ingressController:
 					Name:          "frontend-framework",
 					NamespaceName: "my-namespace",
 					AppQuotaLimit: conversions.IntPtr(5),
+					JobQuotaLimit: conversions.IntPtr(-1),
 					IngressController: ketchv1.IngressControllerSpec{
 						IngressType:     "traefik",
 						ServiceEndpoint: "192.168.1.18",
@@ -382,6 +501,7 @@ ingressController:
 					Name:          "frontend-framework",
 					NamespaceName: "ketch-frontend-framework",
 					AppQuotaLimit: conversions.IntPtr(-1),
+					JobQuotaLimit: conversions.IntPtr(-1),
 					IngressController: ketchv1.IngressControllerSpec{
 						IngressType: "traefik",
 						ClassName:   "traefik",
@@ -9,7 +9,9 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/yaml"
 
@@ -18,6 +20,9 @@ import (
 
 const jobDeployHelp = `
 Deploy a job.
+
+If the job's framework requires signed images, container images must be referenced by digest,
+e.g. "gcr.io/shipa/job@sha256:<digest>", job images aren't pinned to digests like app images.
 `
 
 const (
@@ -46,13 +51,12 @@ func jobDeploy(ctx context.Context, cfg config, filename string, out io.Writer)
 	if err != nil {
 		return err
 	}
-	var spec ketchv1.JobSpec
-	err = yaml.Unmarshal(b, &spec)
+	spec, err := newJobSpecFromYamlData(b)
 	if err != nil {
 		return err
 	}
-	setJobSpecDefaults(&spec)
-	if err = validateJobSpec(&spec); err != nil {
+
+	if err := validateJobImages(ctx, cfg, spec); err != nil {
 		return err
 	}
 
@@ -75,6 +79,37 @@ func jobDeploy(ctx context.Context, cfg config, filename string, out io.Writer)
 	return nil
 }
 
+// validateJobImages returns an error if an image of the job violates the image policy of its framework.
+// A missing framework is reported by the controller.
+func validateJobImages(ctx context.Context, cfg config, spec ketchv1.JobSpec) error {
+	framework := ketchv1.Framework{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: spec.Framework}, &framework); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	for _, container := range spec.Containers {
+		if err := framework.Spec.ImagePolicy.ValidateJobImage(container.Image); err != nil {
+			return fmt.Errorf("image rejected by %q framework: %w", framework.Name, err)
+		}
+	}
+	return nil
+}
+
+// newJobSpecFromYamlData returns a JobSpec with defaults from the content of a job yaml file.
+func newJobSpecFromYamlData(b []byte) (ketchv1.JobSpec, error) {
+	var spec ketchv1.JobSpec
+	if err := yaml.Unmarshal(b, &spec); err != nil {
+		return spec, err
+	}
+	setJobSpecDefaults(&spec)
+	if err := validateJobSpec(&spec); err != nil {
+		return spec, err
+	}
+	return spec, nil
+}
+
 // setJobSpecDefaults sets defaults on job.Spec for some unset fields
 func setJobSpecDefaults(jobSpec *ketchv1.JobSpec) {
 	jobSpec.Type = "Job"
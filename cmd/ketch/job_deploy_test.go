@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 
@@ -86,6 +87,30 @@ framework: NOFRAMEWORK
 description: test`,
 			wantErr: "job.name and job.framework are required",
 		},
+		{
+			name:    "error - framework requires signed images referenced by digest",
+			jobName: "hello",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{&ketchv1.Framework{
+					ObjectMeta: metav1.ObjectMeta{Name: "signed"},
+					Spec: ketchv1.FrameworkSpec{
+						NamespaceName: "ketch-signed",
+						ImagePolicy:   &ketchv1.ImagePolicy{Signatures: &ketchv1.SignaturePolicy{PublicKeys: []string{"key"}}},
+					},
+				}},
+				DynamicClientObjects: []runtime.Object{},
+			},
+			filename: "job.yaml",
+			yamlData: `name: hello
+framework: signed
+containers:
+  - name: lister
+    image: ubuntu:22.04
+    command:
+      - ls
+`,
+			wantErr: `image rejected by "signed" framework: image digest is required by the framework's image policy: job image "ubuntu:22.04" must be referenced as <image>@sha256:<digest> to verify its signature`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
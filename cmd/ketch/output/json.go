@@ -0,0 +1,23 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// jsonOutput represents data and a writer for json output type
+type jsonOutput struct {
+	data   interface{}
+	writer io.Writer
+}
+
+// write implements Writer for type JSON
+func (j *jsonOutput) write() error {
+	b, err := json.MarshalIndent(j.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(j.writer, string(b))
+	return err
+}
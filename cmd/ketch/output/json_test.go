@@ -0,0 +1,17 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteJSON(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := Write([]struct {
+		Name string `json:"name"`
+	}{{Name: "test"}}, out, "json")
+	require.Nil(t, err)
+	require.Equal(t, "[\n  {\n    \"name\": \"test\"\n  }\n]\n", out.String())
+}
@@ -18,6 +18,11 @@ var ErrFileExists = errors.New("file already exists")
 func Write(data interface{}, out io.Writer, outputFlag string) error {
 	var w writer
 	switch outputFlag {
+	case "json":
+		w = &jsonOutput{
+			data:   data,
+			writer: out,
+		}
 	default:
 		w = &columnOutput{
 			data:   data,
@@ -39,6 +39,9 @@ func newRootCmd(cfg config, out io.Writer, packSvc *pack.Client, ketchConfig con
 	cmd.AddCommand(newFrameworkCmd(cfg, out))
 	cmd.AddCommand(newEnvCmd(cfg, out))
 	cmd.AddCommand(newJobCmd(cfg, out))
+	cmd.AddCommand(newServiceCmd(cfg, out))
+	cmd.AddCommand(newTemplateCmd(cfg, out))
+	cmd.AddCommand(newApplyCmd(cfg, out, packSvc))
 	cmd.AddCommand(newCompletionCmd())
 	return cmd
 }
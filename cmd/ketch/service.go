@@ -0,0 +1,26 @@
+package main
+
+import (
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+const serviceCmdHelp = `
+Manage backing services bound to applications.
+`
+
+func newServiceCmd(cfg config, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "service",
+		Short: "Manage backing services bound to applications",
+		Long:  serviceCmdHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Usage()
+		},
+	}
+	cmd.AddCommand(newServiceBindCmd(cfg, out))
+	cmd.AddCommand(newServiceUnbindCmd(cfg, out))
+	return cmd
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/chart"
+)
+
+const serviceBindHelp = `
+Bind a backing service to an application.
+Credentials are read from a secret in the namespace of the application's framework and are provided to every process of the application.
+By default, the secret is mounted to ` + chart.ServiceBindingRoot + `/<binding name>, use "--mode env" to inject its keys as environment variables.
+Processes are restarted when the secret changes.
+`
+
+func newServiceBindCmd(cfg config, out io.Writer) *cobra.Command {
+	options := serviceBindOptions{}
+	cmd := &cobra.Command{
+		Use:   "bind APPNAME SECRET",
+		Args:  cobra.ExactArgs(2),
+		Short: "Bind a backing service to an application.",
+		Long:  serviceBindHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			options.secretName = args[1]
+			return serviceBind(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	cmd.Flags().StringVar(&options.bindingName, "name", "", "The name of the binding. Defaults to the secret name.")
+	cmd.Flags().StringVar(&options.mode, "mode", string(ketchv1.ServiceBindingModeMount), "How credentials are provided to processes, mount or env.")
+	return cmd
+}
+
+type serviceBindOptions struct {
+	appName     string
+	secretName  string
+	bindingName string
+	mode        string
+}
+
+func serviceBind(ctx context.Context, cfg config, options serviceBindOptions, out io.Writer) error {
+	mode := ketchv1.ServiceBindingMode(options.mode)
+	if mode != ketchv1.ServiceBindingModeMount && mode != ketchv1.ServiceBindingModeEnv {
+		return ErrInvalidServiceBindingMode
+	}
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get the app: %w", err)
+	}
+	var framework ketchv1.Framework
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: app.Spec.Framework}, &framework); err != nil {
+		return fmt.Errorf("failed to get the framework: %w", err)
+	}
+	if _, err := cfg.KubernetesClient().CoreV1().Secrets(framework.Spec.NamespaceName).Get(ctx, options.secretName, metav1.GetOptions{}); err != nil {
+		return fmt.Errorf("failed to get the secret: %w", err)
+	}
+	name := options.bindingName
+	if len(name) == 0 {
+		name = options.secretName
+	}
+	binding := ketchv1.ServiceBinding{Name: name, SecretName: options.secretName, Mode: mode}
+	if err := binding.Validate(); err != nil {
+		return err
+	}
+	app.BindService(binding)
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update the app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully bound!")
+	return nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+func Test_serviceBind(t *testing.T) {
+	framework := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "ketch-gke"},
+	}
+	newApp := func(bindings ...ketchv1.ServiceBinding) *ketchv1.App {
+		return &ketchv1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+			Spec:       ketchv1.AppSpec{Framework: "gke", ServiceBindings: bindings},
+		}
+	}
+	tests := []struct {
+		name         string
+		cfg          config
+		options      serviceBindOptions
+		wantBindings []ketchv1.ServiceBinding
+		wantOut      string
+		wantErr      string
+	}{
+		{
+			name: "bind secret",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{framework, newApp()},
+				KubeClientObjects: []runtime.Object{secret},
+			},
+			options: serviceBindOptions{appName: "dashboard", secretName: "postgres", mode: "mount"},
+			wantBindings: []ketchv1.ServiceBinding{
+				{Name: "postgres", SecretName: "postgres", Mode: ketchv1.ServiceBindingModeMount},
+			},
+			wantOut: "Successfully bound!\n",
+		},
+		{
+			name: "replace binding with the same name",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{framework, newApp(ketchv1.ServiceBinding{Name: "db", SecretName: "mysql", Mode: ketchv1.ServiceBindingModeMount})},
+				KubeClientObjects: []runtime.Object{secret},
+			},
+			options: serviceBindOptions{appName: "dashboard", secretName: "postgres", bindingName: "db", mode: "env"},
+			wantBindings: []ketchv1.ServiceBinding{
+				{Name: "db", SecretName: "postgres", Mode: ketchv1.ServiceBindingModeEnv},
+			},
+			wantOut: "Successfully bound!\n",
+		},
+		{
+			name: "secret not found",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{framework, newApp()},
+			},
+			options: serviceBindOptions{appName: "dashboard", secretName: "postgres", mode: "mount"},
+			wantErr: `failed to get the secret: secrets "postgres" not found`,
+		},
+		{
+			name: "invalid mode",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{framework, newApp()},
+				KubeClientObjects: []runtime.Object{secret},
+			},
+			options: serviceBindOptions{appName: "dashboard", secretName: "postgres", mode: "file"},
+			wantErr: ErrInvalidServiceBindingMode.Error(),
+		},
+		{
+			name: "binding name isn't a DNS label",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{framework, newApp()},
+				KubeClientObjects: []runtime.Object{secret},
+			},
+			options: serviceBindOptions{appName: "dashboard", secretName: "postgres", bindingName: "db.primary", mode: "mount"},
+			wantErr: `service binding name must be a DNS label of at most 55 characters: "db.primary"`,
+		},
+		{
+			name: "app not found",
+			cfg: &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{framework},
+				KubeClientObjects: []runtime.Object{secret},
+			},
+			options: serviceBindOptions{appName: "dashboard", secretName: "postgres", mode: "mount"},
+			wantErr: `failed to get the app: apps.theketch.io "dashboard" not found`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := serviceBind(context.Background(), tt.cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantOut, out.String())
+
+			gotApp := ketchv1.App{}
+			err = tt.cfg.Client().Get(context.Background(), types.NamespacedName{Name: tt.options.appName}, &gotApp)
+			require.Nil(t, err)
+			require.Equal(t, tt.wantBindings, gotApp.Spec.ServiceBindings)
+		})
+	}
+}
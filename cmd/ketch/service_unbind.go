@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+const serviceUnbindHelp = `
+Unbind a backing service from an application.
+`
+
+func newServiceUnbindCmd(cfg config, out io.Writer) *cobra.Command {
+	options := serviceUnbindOptions{}
+	cmd := &cobra.Command{
+		Use:   "unbind APPNAME BINDING",
+		Args:  cobra.ExactArgs(2),
+		Short: "Unbind a backing service from an application.",
+		Long:  serviceUnbindHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.appName = args[0]
+			options.bindingName = args[1]
+			return serviceUnbind(cmd.Context(), cfg, options, out)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return autoCompleteAppNames(cfg, toComplete)
+		},
+	}
+	return cmd
+}
+
+type serviceUnbindOptions struct {
+	appName     string
+	bindingName string
+}
+
+func serviceUnbind(ctx context.Context, cfg config, options serviceUnbindOptions, out io.Writer) error {
+	app := ketchv1.App{}
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: options.appName}, &app); err != nil {
+		return fmt.Errorf("failed to get the app: %w", err)
+	}
+	if !app.UnbindService(options.bindingName) {
+		return ErrServiceBindingNotFound
+	}
+	if err := cfg.Client().Update(ctx, &app); err != nil {
+		return fmt.Errorf("failed to update the app: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully unbound!")
+	return nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+)
+
+func Test_serviceUnbind(t *testing.T) {
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec: ketchv1.AppSpec{
+			Framework: "gke",
+			ServiceBindings: []ketchv1.ServiceBinding{
+				{Name: "db", SecretName: "postgres", Mode: ketchv1.ServiceBindingModeMount},
+				{Name: "cache", SecretName: "redis", Mode: ketchv1.ServiceBindingModeEnv},
+			},
+		},
+	}
+	tests := []struct {
+		name         string
+		cfg          config
+		options      serviceUnbindOptions
+		wantBindings []ketchv1.ServiceBinding
+		wantOut      string
+		wantErr      string
+	}{
+		{
+			name:    "unbind",
+			cfg:     &mocks.Configuration{CtrlClientObjects: []runtime.Object{dashboard}},
+			options: serviceUnbindOptions{appName: "dashboard", bindingName: "db"},
+			wantBindings: []ketchv1.ServiceBinding{
+				{Name: "cache", SecretName: "redis", Mode: ketchv1.ServiceBindingModeEnv},
+			},
+			wantOut: "Successfully unbound!\n",
+		},
+		{
+			name:    "binding not found",
+			cfg:     &mocks.Configuration{CtrlClientObjects: []runtime.Object{dashboard}},
+			options: serviceUnbindOptions{appName: "dashboard", bindingName: "queue"},
+			wantErr: ErrServiceBindingNotFound.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := serviceUnbind(context.Background(), tt.cfg, tt.options, out)
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Equal(t, tt.wantErr, err.Error())
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.wantOut, out.String())
+
+			gotApp := ketchv1.App{}
+			err = tt.cfg.Client().Get(context.Background(), types.NamespacedName{Name: tt.options.appName}, &gotApp)
+			require.Nil(t, err)
+			require.Equal(t, tt.wantBindings, gotApp.Spec.ServiceBindings)
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/thediveo/enumflag"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/chart"
+	"github.com/theketchio/ketch/internal/templates"
+)
+
+const templateHelp = `
+Manage chart templates used to render apps and jobs.
+
+Templates are stored per ingress controller type, jobs have their own templates.
+A framework can override templates of its ingress controller type file by file.
+`
+
+func newTemplateCmd(cfg config, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Manage chart templates",
+		Long:  templateHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Usage()
+		},
+	}
+	cmd.AddCommand(newTemplateListCmd(cfg, out))
+	cmd.AddCommand(newTemplateExportCmd(cfg, out))
+	cmd.AddCommand(newTemplateApplyCmd(cfg, out))
+	cmd.AddCommand(newTemplateDiffCmd(cfg, out))
+	cmd.AddCommand(newTemplateResetCmd(cfg, out))
+	return cmd
+}
+
+// templateSetOptions selects a set of templates, it's shared by all "template" subcommands working with one set.
+type templateSetOptions struct {
+	ingressSet  bool
+	ingressType ingressType
+	job         bool
+	framework   string
+}
+
+func (o *templateSetOptions) addFlags(flags *pflag.FlagSet) {
+	flags.Var(enumflag.New(&o.ingressType, "ingress", ingressTypeIds, enumflag.EnumCaseInsensitive), "ingress", "templates of the ingress controller type: traefik, istio or nginx")
+	flags.BoolVar(&o.job, "job", false, "templates of jobs")
+	flags.StringVar(&o.framework, "framework", "", "templates of the framework, they override templates of the framework's ingress controller type")
+}
+
+func (o *templateSetOptions) setChanged(flags *pflag.FlagSet) {
+	o.ingressSet = flags.Changed("ingress")
+}
+
+// templateSet is a set of templates stored in a configmap.
+type templateSet struct {
+	configMapName string
+	// defaults are templates provided by ketch, they are empty for frameworks.
+	defaults templates.Templates
+	// framework is set if the templates override templates of the framework's ingress controller type.
+	framework *ketchv1.Framework
+}
+
+func (o templateSetOptions) resolve(ctx context.Context, cfg config) (*templateSet, error) {
+	selected := 0
+	for _, set := range []bool{o.ingressSet, o.job, len(o.framework) > 0} {
+		if set {
+			selected++
+		}
+	}
+	if selected != 1 {
+		return nil, ErrTemplateSetRequired
+	}
+	switch {
+	case o.job:
+		return &templateSet{configMapName: templates.JobConfigMapName(), defaults: templates.JobTemplates}, nil
+	case o.ingressSet:
+		ingress := o.ingressType.ingressControllerType().String()
+		defaults, _ := templates.IngressDefaultTemplates(ingress)
+		return &templateSet{configMapName: templates.IngressConfigMapName(ingress), defaults: defaults}, nil
+	}
+	var framework ketchv1.Framework
+	if err := cfg.Client().Get(ctx, types.NamespacedName{Name: o.framework}, &framework); err != nil {
+		return nil, fmt.Errorf("failed to get framework: %w", err)
+	}
+	name := framework.Spec.TemplatesConfigMapName
+	if len(name) == 0 {
+		name = frameworkTemplatesConfigMapName(framework.Name)
+	}
+	return &templateSet{configMapName: name, framework: &framework}, nil
+}
+
+func frameworkTemplatesConfigMapName(framework string) string {
+	return fmt.Sprintf("framework-%s-templates", framework)
+}
+
+// ingressTemplates returns templates of the ingress controller type stored in the cluster or ketch's defaults if they are not stored.
+func ingressTemplates(storage templates.Reader, ingress ketchv1.IngressControllerType) (*templates.Templates, error) {
+	tpls, err := storage.Get(templates.IngressConfigMapName(ingress.String()))
+	if k8sErrors.IsNotFound(err) {
+		defaults, _ := templates.IngressDefaultTemplates(ingress.String())
+		return &defaults, nil
+	}
+	return tpls, err
+}
+
+// base returns templates the set is layered over, for frameworks it's templates of the framework's ingress controller type.
+func (s templateSet) base(storage templates.Reader) (*templates.Templates, error) {
+	if s.framework == nil {
+		return &templates.Templates{}, nil
+	}
+	return ingressTemplates(storage, s.framework.Spec.IngressController.IngressType)
+}
+
+// current returns the effective templates of the set.
+func (s templateSet) current(storage templates.Reader) (*templates.Templates, error) {
+	base, err := s.base(storage)
+	if err != nil {
+		return nil, err
+	}
+	if s.framework != nil && len(s.framework.Spec.TemplatesConfigMapName) == 0 {
+		return base, nil
+	}
+	tpls, err := storage.Get(s.configMapName)
+	if k8sErrors.IsNotFound(err) {
+		if s.framework != nil {
+			return base, nil
+		}
+		return &s.defaults, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return base.WithOverrides(*tpls), nil
+}
+
+// frameworkTemplates returns the effective templates used to render apps of the framework.
+func frameworkTemplates(storage templates.Reader, framework ketchv1.Framework) (*templates.Templates, error) {
+	set := templateSet{configMapName: framework.Spec.TemplatesConfigMapName, framework: &framework}
+	return set.current(storage)
+}
+
+// renderApp renders manifests of the app with templates of its framework the same way the controller does,
+// manifests are keyed by template name.
+func renderApp(ctx context.Context, cfg config, app *ketchv1.App, framework ketchv1.Framework) (map[string]string, error) {
+	tpls, err := frameworkTemplates(cfg.Storage(), framework)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get templates: %w", err)
+	}
+	stateOptions, _, err := chart.AppStateOptions(ctx, cfg.Client(), app, framework)
+	if err != nil {
+		return nil, err
+	}
+	options := append([]chart.Option{chart.WithExposedPorts(app.ExposedPorts()), chart.WithTemplates(*tpls)}, stateOptions...)
+	appChrt, err := chart.New(app, &framework, options...)
+	if err != nil {
+		return nil, err
+	}
+	return chart.Render(appChrt, chart.NewChartConfig(*app), framework.Spec.NamespaceName)
+}
+
+// joinManifests joins manifests into one multi-document yaml sorted by template name.
+func joinManifests(manifests map[string]string) []byte {
+	names := make([]string, 0, len(manifests))
+	for name := range manifests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var buf bytes.Buffer
+	for _, name := range names {
+		content := strings.TrimPrefix(strings.TrimSpace(manifests[name]), "---")
+		fmt.Fprintf(&buf, "---\n%s\n", strings.TrimSpace(content))
+	}
+	return buf.Bytes()
+}
+
+// readTemplatesDirectory reads every file of the directory as a template.
+func readTemplatesDirectory(directory string) (*templates.Templates, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, err
+	}
+	tpls := templates.Templates{Yamls: map[string]string{}}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(directory, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		tpls.Yamls[entry.Name()] = string(content)
+	}
+	return &tpls, nil
+}
+
+// renderWithTemplates renders the charts of apps or jobs using the templates to make sure the templates can be used.
+// For frameworks, only their apps are rendered. For an ingress controller type, apps of all frameworks of this type are rendered
+// with frameworks' overrides layered over the templates.
+func renderWithTemplates(ctx context.Context, cfg config, set templateSet, tpls templates.Templates) error {
+	if set.configMapName == templates.JobConfigMapName() {
+		return renderJobsWithTemplates(ctx, cfg, tpls)
+	}
+	frameworks := ketchv1.FrameworkList{}
+	if err := cfg.Client().List(ctx, &frameworks); err != nil {
+		return fmt.Errorf("failed to get list of frameworks: %w", err)
+	}
+	apps := ketchv1.AppList{}
+	if err := cfg.Client().List(ctx, &apps); err != nil {
+		return fmt.Errorf("failed to get list of apps: %w", err)
+	}
+	sort.Slice(apps.Items, func(i, j int) bool { return apps.Items[i].Name < apps.Items[j].Name })
+	for _, framework := range frameworks.Items {
+		frameworkTpls := tpls
+		switch {
+		case set.framework != nil:
+			if framework.Name != set.framework.Name {
+				continue
+			}
+		case templates.IngressConfigMapName(framework.Spec.IngressController.IngressType.String()) != set.configMapName:
+			continue
+		case len(framework.Spec.TemplatesConfigMapName) > 0:
+			overrides, err := cfg.Storage().Get(framework.Spec.TemplatesConfigMapName)
+			if err != nil && !k8sErrors.IsNotFound(err) {
+				return err
+			}
+			if err == nil {
+				frameworkTpls = *tpls.WithOverrides(*overrides)
+			}
+		}
+		for i := range apps.Items {
+			app := &apps.Items[i]
+			if app.Spec.Framework != framework.Name {
+				continue
+			}
+			appChrt, err := chart.New(app, &framework, chart.WithExposedPorts(app.ExposedPorts()), chart.WithTemplates(frameworkTpls))
+			if err != nil {
+				return fmt.Errorf("failed to render app %q: %w", app.Name, err)
+			}
+			if _, err := chart.Render(appChrt, chart.NewChartConfig(*app), framework.Spec.NamespaceName); err != nil {
+				return fmt.Errorf("failed to render app %q: %w", app.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func renderJobsWithTemplates(ctx context.Context, cfg config, tpls templates.Templates) error {
+	jobs := ketchv1.JobList{}
+	if err := cfg.Client().List(ctx, &jobs); err != nil {
+		return fmt.Errorf("failed to get list of jobs: %w", err)
+	}
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		var framework ketchv1.Framework
+		if err := cfg.Client().Get(ctx, types.NamespacedName{Name: job.Spec.Framework}, &framework); err != nil {
+			return fmt.Errorf("failed to get framework of job %q: %w", job.Name, err)
+		}
+		jobChrt := chart.NewJobChart(job, chart.WithTemplates(tpls))
+		if _, err := chart.Render(jobChrt, chart.NewJobChartConfig(*job), framework.Spec.NamespaceName); err != nil {
+			return fmt.Errorf("failed to render job %q: %w", job.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theketchio/ketch/internal/templates"
+)
+
+const templateApplyHelp = `
+Apply chart templates from a directory, one file per template.
+Before the templates are saved, they are rendered against existing apps or jobs using them.
+Templates of a framework are saved as overrides of templates of its ingress controller type,
+only files that differ from the ingress controller type's templates are stored.
+
+  ketch template export --framework team-a ./team-a-templates
+  # edit ./team-a-templates/deployment.yaml
+  ketch template apply --framework team-a ./team-a-templates
+`
+
+type templateApplyOptions struct {
+	set       templateSetOptions
+	directory string
+}
+
+func newTemplateApplyCmd(cfg config, out io.Writer) *cobra.Command {
+	options := templateApplyOptions{}
+	cmd := &cobra.Command{
+		Use:   "apply DIRECTORY",
+		Short: "Apply chart templates from a directory.",
+		Long:  templateApplyHelp,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.directory = args[0]
+			options.set.setChanged(cmd.Flags())
+			return templateApply(cmd.Context(), cfg, options, out)
+		},
+	}
+	options.set.addFlags(cmd.Flags())
+	return cmd
+}
+
+func templateApply(ctx context.Context, cfg config, options templateApplyOptions, out io.Writer) error {
+	set, err := options.set.resolve(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	tpls, err := readTemplatesDirectory(options.directory)
+	if err != nil {
+		return fmt.Errorf("failed to read templates: %w", err)
+	}
+	if len(tpls.Yamls) == 0 {
+		return fmt.Errorf("%w: %s", ErrNoTemplates, options.directory)
+	}
+	if err := renderWithTemplates(ctx, cfg, *set, *tpls); err != nil {
+		return fmt.Errorf("%w: %v", ErrTemplatesNotRendered, err)
+	}
+	if set.framework == nil {
+		if err := cfg.Storage().Update(set.configMapName, *tpls); err != nil {
+			return fmt.Errorf("failed to save templates: %w", err)
+		}
+		fmt.Fprintln(out, "Successfully applied!")
+		return nil
+	}
+
+	base, err := set.base(cfg.Storage())
+	if err != nil {
+		return fmt.Errorf("failed to get templates: %w", err)
+	}
+	if err := cfg.Storage().Update(set.configMapName, templateOverrides(*base, *tpls)); err != nil {
+		return fmt.Errorf("failed to save templates: %w", err)
+	}
+	if set.framework.Spec.TemplatesConfigMapName != set.configMapName {
+		set.framework.Spec.TemplatesConfigMapName = set.configMapName
+		if err := cfg.Client().Update(ctx, set.framework); err != nil {
+			return fmt.Errorf("failed to update framework: %w", err)
+		}
+	}
+	fmt.Fprintln(out, "Successfully applied!")
+	return nil
+}
+
+// templateOverrides returns files of tpls that differ from base, files of base missing in tpls are returned empty to remove them.
+func templateOverrides(base, tpls templates.Templates) templates.Templates {
+	overrides := templates.Templates{Yamls: map[string]string{}}
+	for name, content := range tpls.Yamls {
+		if baseContent, ok := base.Yamls[name]; !ok || baseContent != content {
+			overrides.Yamls[name] = content
+		}
+	}
+	for name := range base.Yamls {
+		if _, ok := tpls.Yamls[name]; !ok {
+			overrides.Yamls[name] = ""
+		}
+	}
+	return overrides
+}
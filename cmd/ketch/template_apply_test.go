@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/templates"
+)
+
+func writeTemplates(t *testing.T, yamls map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range yamls {
+		require.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	return dir
+}
+
+func Test_templateApply(t *testing.T) {
+	traefikTemplates := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      templates.IngressConfigMapName("traefik"),
+			Namespace: templates.KetchNamespace,
+		},
+		Data: map[string]string{
+			"deployment.yaml": "kind: Deployment\nname: {{ $.Values.app.name }}",
+			"service.yaml":    "kind: Service",
+		},
+	}
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-gke",
+			IngressController: ketchv1.IngressControllerSpec{
+				IngressType: ketchv1.TraefikIngressControllerType,
+			},
+		},
+	}
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec:       ketchv1.AppSpec{Framework: "gke"},
+	}
+
+	tests := []struct {
+		name          string
+		options       templateApplyOptions
+		yamls         map[string]string
+		wantTemplates map[string]string
+		wantConfigMap string
+		wantErr       error
+	}{
+		{
+			name: "ingress templates",
+			options: templateApplyOptions{
+				set: templateSetOptions{ingressSet: true, ingressType: traefik},
+			},
+			yamls: map[string]string{
+				"deployment.yaml": "kind: Deployment\nname: {{ $.Values.app.name }}-custom",
+			},
+			wantConfigMap: templates.IngressConfigMapName("traefik"),
+			wantTemplates: map[string]string{
+				"deployment.yaml": "kind: Deployment\nname: {{ $.Values.app.name }}-custom",
+			},
+		},
+		{
+			name: "framework templates are stored as overrides",
+			options: templateApplyOptions{
+				set: templateSetOptions{framework: "gke"},
+			},
+			yamls: map[string]string{
+				"deployment.yaml": "kind: Deployment\nname: {{ $.Values.app.name }}-gke",
+				"pdb.yaml":        "kind: PodDisruptionBudget",
+			},
+			wantConfigMap: "framework-gke-templates",
+			wantTemplates: map[string]string{
+				"deployment.yaml": "kind: Deployment\nname: {{ $.Values.app.name }}-gke",
+				"pdb.yaml":        "kind: PodDisruptionBudget",
+				"service.yaml":    "",
+			},
+		},
+		{
+			name: "templates can't be rendered",
+			options: templateApplyOptions{
+				set: templateSetOptions{ingressSet: true, ingressType: traefik},
+			},
+			yamls: map[string]string{
+				"deployment.yaml": "name: {{ $.Values.app.name ",
+			},
+			wantErr: ErrTemplatesNotRendered,
+		},
+		{
+			name:    "no templates",
+			options: templateApplyOptions{set: templateSetOptions{job: true}},
+			wantErr: ErrNoTemplates,
+		},
+		{
+			name:    "no template set",
+			wantErr: ErrTemplateSetRequired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{traefikTemplates, gke, dashboard},
+			}
+			tt.options.directory = writeTemplates(t, tt.yamls)
+			out := &bytes.Buffer{}
+			err := templateApply(context.Background(), cfg, tt.options, out)
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, "Successfully applied!\n", out.String())
+			got, err := cfg.Storage().Get(tt.wantConfigMap)
+			require.Nil(t, err)
+			require.Equal(t, tt.wantTemplates, got.Yamls)
+			if len(tt.options.set.framework) > 0 {
+				var framework ketchv1.Framework
+				require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: tt.options.set.framework}, &framework))
+				require.Equal(t, tt.wantConfigMap, framework.Spec.TemplatesConfigMapName)
+			}
+		})
+	}
+}
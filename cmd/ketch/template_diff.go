@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/theketchio/ketch/internal/templates"
+)
+
+const templateDiffHelp = `
+Show differences between chart templates in a directory and templates stored in the cluster.
+`
+
+type templateDiffOptions struct {
+	set       templateSetOptions
+	directory string
+}
+
+func newTemplateDiffCmd(cfg config, out io.Writer) *cobra.Command {
+	options := templateDiffOptions{}
+	cmd := &cobra.Command{
+		Use:   "diff DIRECTORY",
+		Short: "Show differences between chart templates in a directory and in the cluster.",
+		Long:  templateDiffHelp,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.directory = args[0]
+			options.set.setChanged(cmd.Flags())
+			return templateDiff(cmd.Context(), cfg, options, out)
+		},
+	}
+	options.set.addFlags(cmd.Flags())
+	return cmd
+}
+
+func templateDiff(ctx context.Context, cfg config, options templateDiffOptions, out io.Writer) error {
+	set, err := options.set.resolve(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	current, err := set.current(cfg.Storage())
+	if err != nil {
+		return fmt.Errorf("failed to get templates: %w", err)
+	}
+	local, err := readTemplatesDirectory(options.directory)
+	if err != nil {
+		return fmt.Errorf("failed to read templates: %w", err)
+	}
+	diff, err := templatesDiff(*current, *local)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(out, diff)
+	return nil
+}
+
+// templatesDiff returns a unified diff between templates stored in the cluster and local templates.
+func templatesDiff(current, local templates.Templates) (string, error) {
+	diffs, err := fileDiffs(current.Yamls, local.Yamls, "cluster/", "local/")
+	if err != nil {
+		return "", err
+	}
+	return joinFileDiffs(diffs), nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/templates"
+)
+
+func Test_templateDiff(t *testing.T) {
+	job := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: templates.JobConfigMapName(), Namespace: templates.KetchNamespace},
+		Data: map[string]string{
+			"job.yaml":     "kind: Job\nname: job\n",
+			"removed.yaml": "kind: ConfigMap\n",
+			"same.yaml":    "kind: Secret\n",
+		},
+	}
+	cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{job}}
+	dir := writeTemplates(t, map[string]string{
+		"job.yaml":   "kind: Job\nname: custom-job\n",
+		"added.yaml": "kind: Service\n",
+		"same.yaml":  "kind: Secret\n",
+	})
+	out := &bytes.Buffer{}
+	options := templateDiffOptions{set: templateSetOptions{job: true}, directory: dir}
+	require.Nil(t, templateDiff(context.Background(), cfg, options, out))
+	require.Equal(t, `--- /dev/null
++++ local/added.yaml
+@@ -0,0 +1 @@
++kind: Service
+--- cluster/job.yaml
++++ local/job.yaml
+@@ -1,2 +1,2 @@
+ kind: Job
+-name: job
++name: custom-job
+--- cluster/removed.yaml
++++ /dev/null
+@@ -1 +0,0 @@
+-kind: ConfigMap
+`, out.String())
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+const templateExportHelp = `
+Export chart templates to a directory, one file per template.
+Templates of a framework are exported with templates of its ingress controller type layered under them.
+
+  ketch template export --ingress traefik ./templates
+  ketch template export --framework team-a ./team-a-templates
+`
+
+type templateExportOptions struct {
+	set       templateSetOptions
+	directory string
+}
+
+func newTemplateExportCmd(cfg config, out io.Writer) *cobra.Command {
+	options := templateExportOptions{}
+	cmd := &cobra.Command{
+		Use:   "export DIRECTORY",
+		Short: "Export chart templates to a directory.",
+		Long:  templateExportHelp,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.directory = args[0]
+			options.set.setChanged(cmd.Flags())
+			return templateExport(cmd.Context(), cfg, options, out)
+		},
+	}
+	options.set.addFlags(cmd.Flags())
+	return cmd
+}
+
+func templateExport(ctx context.Context, cfg config, options templateExportOptions, out io.Writer) error {
+	set, err := options.set.resolve(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	tpls, err := set.current(cfg.Storage())
+	if err != nil {
+		return fmt.Errorf("failed to get templates: %w", err)
+	}
+	if err := os.MkdirAll(options.directory, 0755); err != nil {
+		return err
+	}
+	for name, content := range tpls.Yamls {
+		if err := os.WriteFile(filepath.Join(options.directory, name), []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(out, "Exported %d templates to %s\n", len(tpls.Yamls), options.directory)
+	return nil
+}
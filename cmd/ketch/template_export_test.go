@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/templates"
+)
+
+func Test_templateExport(t *testing.T) {
+	nginxTemplates := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: templates.IngressConfigMapName("nginx"), Namespace: templates.KetchNamespace},
+		Data:       map[string]string{"deployment.yaml": "deployment", "ingress.yaml": "ingress"},
+	}
+	overrides := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "framework-gke-templates", Namespace: templates.KetchNamespace},
+		Data:       map[string]string{"deployment.yaml": "gke deployment"},
+	}
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec: ketchv1.FrameworkSpec{
+			IngressController:      ketchv1.IngressControllerSpec{IngressType: ketchv1.NginxIngressControllerType},
+			TemplatesConfigMapName: "framework-gke-templates",
+		},
+	}
+	tests := []struct {
+		name    string
+		set     templateSetOptions
+		want    map[string]string
+		wantOut string
+	}{
+		{
+			name:    "ingress templates",
+			set:     templateSetOptions{ingressSet: true, ingressType: nginx},
+			want:    nginxTemplates.Data,
+			wantOut: "Exported 2 templates to ",
+		},
+		{
+			name:    "framework templates",
+			set:     templateSetOptions{framework: "gke"},
+			want:    map[string]string{"deployment.yaml": "gke deployment", "ingress.yaml": "ingress"},
+			wantOut: "Exported 2 templates to ",
+		},
+		{
+			name:    "job templates that are not stored",
+			set:     templateSetOptions{job: true},
+			want:    templates.JobTemplates.Yamls,
+			wantOut: "Exported 1 templates to ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &mocks.Configuration{
+				CtrlClientObjects: []runtime.Object{nginxTemplates, overrides, gke},
+			}
+			dir := filepath.Join(t.TempDir(), "templates")
+			out := &bytes.Buffer{}
+			require.Nil(t, templateExport(context.Background(), cfg, templateExportOptions{set: tt.set, directory: dir}, out))
+			require.Equal(t, tt.wantOut+dir+"\n", out.String())
+			got := map[string]string{}
+			entries, err := os.ReadDir(dir)
+			require.Nil(t, err)
+			for _, entry := range entries {
+				content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+				require.Nil(t, err)
+				got[entry.Name()] = string(content)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/theketchio/ketch/cmd/ketch/output"
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/templates"
+)
+
+const templateListHelp = `
+List chart templates of ingress controller types, jobs and frameworks.
+STATUS is "default" for templates provided by ketch, "modified" for templates changed by a user and
+"override" for frameworks' templates layered over templates of their ingress controller type.
+`
+
+const (
+	templateStatusDefault   = "default"
+	templateStatusModified  = "modified"
+	templateStatusOverride  = "override"
+	templateStatusNotStored = "not stored"
+)
+
+type templateListOutput struct {
+	Templates string `json:"templates" yaml:"templates"`
+	ConfigMap string `json:"configMap" yaml:"configMap"`
+	Status    string `json:"status" yaml:"status"`
+	Files     int    `json:"files" yaml:"files"`
+}
+
+func newTemplateListCmd(cfg config, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List chart templates.",
+		Long:  templateListHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return templateList(cmd.Context(), cfg, out)
+		},
+	}
+	return cmd
+}
+
+func templateList(ctx context.Context, cfg config, out io.Writer) error {
+	var rows []templateListOutput
+	sets := []struct {
+		name          string
+		configMapName string
+	}{
+		{ketchv1.TraefikIngressControllerType.String(), templates.IngressConfigMapName(ketchv1.TraefikIngressControllerType.String())},
+		{ketchv1.IstioIngressControllerType.String(), templates.IngressConfigMapName(ketchv1.IstioIngressControllerType.String())},
+		{ketchv1.NginxIngressControllerType.String(), templates.IngressConfigMapName(ketchv1.NginxIngressControllerType.String())},
+		{"job", templates.JobConfigMapName()},
+	}
+	for _, set := range sets {
+		row, err := newTemplateListOutput(cfg.Storage(), set.name, set.configMapName)
+		if err != nil {
+			return err
+		}
+		if row.Status != templateStatusNotStored {
+			modified, err := cfg.Storage().Modified(set.configMapName)
+			if err != nil {
+				return err
+			}
+			row.Status = templateStatusDefault
+			if modified {
+				row.Status = templateStatusModified
+			}
+		}
+		rows = append(rows, *row)
+	}
+
+	frameworks := ketchv1.FrameworkList{}
+	if err := cfg.Client().List(ctx, &frameworks); err != nil {
+		return fmt.Errorf("failed to get list of frameworks: %w", err)
+	}
+	for _, framework := range frameworks.Items {
+		if len(framework.Spec.TemplatesConfigMapName) == 0 {
+			continue
+		}
+		row, err := newTemplateListOutput(cfg.Storage(), "framework/"+framework.Name, framework.Spec.TemplatesConfigMapName)
+		if err != nil {
+			return err
+		}
+		if row.Status != templateStatusNotStored {
+			row.Status = templateStatusOverride
+		}
+		rows = append(rows, *row)
+	}
+	return output.Write(rows, out, "column")
+}
+
+func newTemplateListOutput(storage templates.Reader, name, configMapName string) (*templateListOutput, error) {
+	row := templateListOutput{Templates: name, ConfigMap: configMapName}
+	tpls, err := storage.Get(configMapName)
+	if k8sErrors.IsNotFound(err) {
+		row.Status = templateStatusNotStored
+		return &row, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	row.Files = len(tpls.Yamls)
+	return &row, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/templates"
+)
+
+func Test_templateList(t *testing.T) {
+	defaults := templates.Templates{Yamls: map[string]string{"deployment.yaml": "deployment", "service.yaml": "service"}}
+	traefikTemplates := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        templates.IngressConfigMapName("traefik"),
+			Namespace:   templates.KetchNamespace,
+			Annotations: map[string]string{templates.DefaultsChecksumAnnotation(): defaults.Checksum()},
+		},
+		Data: defaults.Yamls,
+	}
+	istioTemplates := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        templates.IngressConfigMapName("istio"),
+			Namespace:   templates.KetchNamespace,
+			Annotations: map[string]string{templates.DefaultsChecksumAnnotation(): defaults.Checksum()},
+		},
+		Data: map[string]string{"deployment.yaml": "custom deployment"},
+	}
+	overrides := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "framework-gke-templates", Namespace: templates.KetchNamespace},
+		Data:       map[string]string{"deployment.yaml": "gke deployment"},
+	}
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec:       ketchv1.FrameworkSpec{TemplatesConfigMapName: "framework-gke-templates"},
+	}
+	cfg := &mocks.Configuration{
+		CtrlClientObjects: []runtime.Object{traefikTemplates, istioTemplates, overrides, gke, &ketchv1.Framework{ObjectMeta: metav1.ObjectMeta{Name: "aws"}}},
+	}
+	out := &bytes.Buffer{}
+	require.Nil(t, templateList(context.Background(), cfg, out))
+	require.Equal(t, `TEMPLATES        CONFIG MAP                   STATUS        FILES
+traefik          ingress-traefik-templates    default       2
+istio            ingress-istio-templates      modified      1
+nginx            ingress-nginx-templates      not stored    0
+job              job-templates                not stored    0
+framework/gke    framework-gke-templates      override      1
+`, out.String())
+}
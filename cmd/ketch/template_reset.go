@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+const templateResetHelp = `
+Reset chart templates to the defaults provided by ketch.
+For a framework, its template overrides are removed and the framework uses templates of its ingress controller type.
+`
+
+type templateResetOptions struct {
+	set templateSetOptions
+}
+
+func newTemplateResetCmd(cfg config, out io.Writer) *cobra.Command {
+	options := templateResetOptions{}
+	cmd := &cobra.Command{
+		Use:   "reset",
+		Short: "Reset chart templates to defaults.",
+		Long:  templateResetHelp,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.set.setChanged(cmd.Flags())
+			return templateReset(cmd.Context(), cfg, options, out)
+		},
+	}
+	options.set.addFlags(cmd.Flags())
+	return cmd
+}
+
+func templateReset(ctx context.Context, cfg config, options templateResetOptions, out io.Writer) error {
+	set, err := options.set.resolve(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	if set.framework == nil {
+		if err := cfg.Storage().Reset(set.configMapName, set.defaults); err != nil {
+			return fmt.Errorf("failed to reset templates: %w", err)
+		}
+		fmt.Fprintln(out, "Successfully reset!")
+		return nil
+	}
+	if len(set.framework.Spec.TemplatesConfigMapName) > 0 {
+		set.framework.Spec.TemplatesConfigMapName = ""
+		if err := cfg.Client().Update(ctx, set.framework); err != nil {
+			return fmt.Errorf("failed to update framework: %w", err)
+		}
+	}
+	if err := cfg.Storage().Delete(set.configMapName); err != nil {
+		return fmt.Errorf("failed to reset templates: %w", err)
+	}
+	fmt.Fprintln(out, "Successfully reset!")
+	return nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/mocks"
+	"github.com/theketchio/ketch/internal/templates"
+)
+
+func Test_templateReset(t *testing.T) {
+	istioTemplates := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: templates.IngressConfigMapName("istio"), Namespace: templates.KetchNamespace},
+		Data:       map[string]string{"deployment.yaml": "custom deployment"},
+	}
+	overrides := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "framework-gke-templates", Namespace: templates.KetchNamespace},
+		Data:       map[string]string{"deployment.yaml": "gke deployment"},
+	}
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec:       ketchv1.FrameworkSpec{TemplatesConfigMapName: "framework-gke-templates"},
+	}
+
+	t.Run("ingress templates", func(t *testing.T) {
+		cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{istioTemplates, overrides, gke}}
+		out := &bytes.Buffer{}
+		options := templateResetOptions{set: templateSetOptions{ingressSet: true, ingressType: istio}}
+		require.Nil(t, templateReset(context.Background(), cfg, options, out))
+		require.Equal(t, "Successfully reset!\n", out.String())
+		got, err := cfg.Storage().Get(templates.IngressConfigMapName("istio"))
+		require.Nil(t, err)
+		require.Equal(t, templates.IstioDefaultTemplates.Yamls, got.Yamls)
+		modified, err := cfg.Storage().Modified(templates.IngressConfigMapName("istio"))
+		require.Nil(t, err)
+		require.False(t, modified)
+	})
+
+	t.Run("framework templates", func(t *testing.T) {
+		cfg := &mocks.Configuration{CtrlClientObjects: []runtime.Object{istioTemplates, overrides, gke}}
+		out := &bytes.Buffer{}
+		options := templateResetOptions{set: templateSetOptions{framework: "gke"}}
+		require.Nil(t, templateReset(context.Background(), cfg, options, out))
+		require.Equal(t, "Successfully reset!\n", out.String())
+		_, err := cfg.Storage().Get("framework-gke-templates")
+		require.True(t, k8sErrors.IsNotFound(err))
+		var framework ketchv1.Framework
+		require.Nil(t, cfg.Client().Get(context.Background(), types.NamespacedName{Name: "gke"}, &framework))
+		require.Empty(t, framework.Spec.TemplatesConfigMapName)
+	})
+}
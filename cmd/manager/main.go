@@ -23,20 +23,27 @@ import (
 	"os"
 	"time"
 
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 	"github.com/theketchio/ketch/internal/chart"
 	"github.com/theketchio/ketch/internal/controllers"
+	"github.com/theketchio/ketch/internal/signature"
 	"github.com/theketchio/ketch/internal/templates"
 	// +kubebuilder:scaffold:imports
 )
@@ -53,13 +60,15 @@ func main() {
 	var disableWebhooks bool
 	var group string
 	var namespace string
+	var serviceAccount string
 	flag.StringVar(&metricsAddr, "metrics-addr", ":8080", "The address the metric endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", true,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
 	flag.BoolVar(&disableWebhooks, "disable-webhooks", false, "Disable webhooks.")
 	flag.StringVar(&group, "group", ketchv1.TheKetchGroup, "specify a non-default group")
-	flag.StringVar(&namespace, "namespace", controllers.KetchNamespace, "specify a non-default namespace")
+	flag.StringVar(&namespace, "namespace", templates.KetchNamespace, "specify a non-default namespace")
+	flag.StringVar(&serviceAccount, "service-account", "default", "service account the manager runs as in its namespace")
 	flag.Parse()
 
 	_ = clientgoscheme.AddToScheme(scheme)
@@ -74,6 +83,18 @@ func main() {
 		Port:               9443,
 		LeaderElection:     enableLeaderElection,
 		LeaderElectionID:   "dcbf0335.theketch.io",
+		// secrets bound to apps are read directly, so the cache doesn't hold data of all secrets in the cluster.
+		// configmaps are cached only in the manager's namespace to watch templates,
+		// post-render patches in frameworks' namespaces are read directly.
+		// deployments and jobs are cached only if they are rendered by ketch for apps and jobs.
+		ClientDisableCacheFor: []client.Object{&v1.Secret{}, &v1.ConfigMap{}},
+		NewCache: cache.BuilderWithOptions(cache.Options{
+			SelectorsByObject: cache.SelectorsByObject{
+				&v1.ConfigMap{}:      {Field: fields.OneTermEqualSelector("metadata.namespace", namespace)},
+				&appsv1.Deployment{}: {Label: labelExists(ketchv1.Group + "/app-name")},
+				&batchv1.Job{}:       {Label: labelExists(ketchv1.Group + "/job-name")},
+			},
+		}),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -86,23 +107,23 @@ func main() {
 	}
 	// Storage uses its own client.Client
 	// because mgr.GetClient() returns a client that requires some time to initialize its internal cache,
-	// and storage.Update() operation fails.
+	// and storage.Seed() operation fails.
 	storage := templates.NewStorage(storageClient, namespace)
-	if err = storage.Update(templates.IngressConfigMapName(ketchv1.TraefikIngressControllerType.String()), templates.TraefikDefaultTemplates); err != nil {
-		setupLog.Error(err, "unable to set default templates")
-		os.Exit(1)
-	}
-	if err = storage.Update(templates.IngressConfigMapName(ketchv1.IstioIngressControllerType.String()), templates.IstioDefaultTemplates); err != nil {
-		setupLog.Error(err, "unable to set default templates")
-		os.Exit(1)
-	}
-	if err = storage.Update(templates.IngressConfigMapName(ketchv1.NginxIngressControllerType.String()), templates.NginxDefaultTemplates); err != nil {
-		setupLog.Error(err, "unable to set default templates")
-		os.Exit(1)
+	defaultTemplates := map[string]templates.Templates{
+		templates.IngressConfigMapName(ketchv1.TraefikIngressControllerType.String()): templates.TraefikDefaultTemplates,
+		templates.IngressConfigMapName(ketchv1.IstioIngressControllerType.String()):   templates.IstioDefaultTemplates,
+		templates.IngressConfigMapName(ketchv1.NginxIngressControllerType.String()):   templates.NginxDefaultTemplates,
+		templates.JobConfigMapName(): templates.JobTemplates,
 	}
-	if err = storage.Update(templates.JobConfigMapName(), templates.JobTemplates); err != nil {
-		setupLog.Error(err, "unable to set default templates")
-		os.Exit(1)
+	for name, defaults := range defaultTemplates {
+		seeded, err := storage.Seed(name, defaults)
+		if err != nil {
+			setupLog.Error(err, "unable to set default templates")
+			os.Exit(1)
+		}
+		if !seeded {
+			setupLog.Info("keeping templates modified by user, use \"ketch template reset\" to restore the defaults", "configmap", name)
+		}
 	}
 
 	logg := ctrl.Log.WithName("controllers").WithName("App")
@@ -122,10 +143,11 @@ func main() {
 	factory := chart.NewHelmClientFactory()
 
 	if err = (&controllers.AppReconciler{
-		TemplateReader: storage,
-		Client:         mgr.GetClient(),
-		Log:            logg,
-		Scheme:         mgr.GetScheme(),
+		TemplateReader:     storage,
+		TemplatesNamespace: namespace,
+		Client:             mgr.GetClient(),
+		Log:                logg,
+		Scheme:             mgr.GetScheme(),
 		HelmFactoryFn: func(namespace string) (controllers.Helm, error) {
 			return factory.NewHelmClient(namespace, mgr.GetClient(), logg)
 		},
@@ -172,10 +194,17 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Framework")
 			os.Exit(1)
 		}
-		if err = (&ketchv1.Job{}).SetupWebhookWithManager(mgr); err != nil {
+		// requests of the manager itself are allowed by any framework.
+		managerServiceAccount := fmt.Sprintf("system:serviceaccount:%s:%s", namespace, serviceAccount)
+		verifySignatures := signature.NewWorkloadVerifier(clientSet)
+		if err = (&ketchv1.Job{}).SetupWebhookWithManager(mgr, managerServiceAccount, verifySignatures); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "Job")
 			os.Exit(1)
 		}
+		if err = (&ketchv1.App{}).SetupWebhookWithManager(mgr, managerServiceAccount, verifySignatures); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "App")
+			os.Exit(1)
+		}
 	}
 	// +kubebuilder:scaffold:builder
 
@@ -185,3 +214,13 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// labelExists returns a selector of objects having the label.
+func labelExists(label string) labels.Selector {
+	requirement, err := labels.NewRequirement(label, selection.Exists, nil)
+	if err != nil {
+		setupLog.Error(err, "invalid label", "label", label)
+		os.Exit(1)
+	}
+	return labels.NewSelector().Add(*requirement)
+}
@@ -7,3 +7,9 @@ import "fmt"
 func DontUninstallHelmChartAnnotation(group string) string {
 	return fmt.Sprintf("%s/dont-uninstall-helm-chart", group)
 }
+
+// ServiceBindingsChecksumAnnotation returns a pod annotation that holds a checksum of secrets bound to an App.
+// A change of the checksum restarts the App's processes.
+func ServiceBindingsChecksumAnnotation(group string) string {
+	return fmt.Sprintf("%s/service-bindings-checksum", group)
+}
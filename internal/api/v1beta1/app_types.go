@@ -30,6 +30,7 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
@@ -118,6 +119,9 @@ type AppDeploymentSpec struct {
 	Labels           []Label                   `json:"labels,omitempty"`
 	RoutingSettings  RoutingSettings           `json:"routingSettings,omitempty"`
 	ExposedPorts     []ExposedPort             `json:"exposedPorts,omitempty"`
+	// ImageDigest is the digest the image was resolved to at deploy time.
+	// If set, pods of this deployment run the image by digest instead of by tag.
+	ImageDigest string `json:"imageDigest,omitempty"`
 }
 
 // IngressSpec configures entrypoints to access an application.
@@ -158,8 +162,42 @@ type AppStatus struct {
 	Conditions []Condition `json:"conditions,omitempty"`
 
 	Framework *v1.ObjectReference `json:"framework,omitempty"`
+
+	// InternalServices is a list of cluster-local services of the app's internal processes.
+	InternalServices []InternalService `json:"internalServices,omitempty"`
+
+	// TemplatesRevision is a checksum of chart templates the app was last rendered with.
+	TemplatesRevision string `json:"templatesRevision,omitempty"`
+}
+
+// InternalService describes a ClusterIP Service that exposes a process of an app inside the cluster.
+type InternalService struct {
+	// Process is the name of the exposed process.
+	Process string `json:"process"`
+
+	// Host is a stable cluster-local DNS name of the service.
+	Host string `json:"host"`
+
+	// Port is the port of the published URL.
+	Port int32 `json:"port"`
+
+	// Published is true if the URL of the service is injected into other apps of the same framework.
+	Published bool `json:"published,omitempty"`
+}
+
+// URL returns a cluster-local URL of the service.
+func (s InternalService) URL() string {
+	return fmt.Sprintf("http://%s:%d", s.Host, s.Port)
 }
 
+// InternalServiceEnvName returns a name of an environment variable to hold the URL of the app's process.
+func InternalServiceEnvName(appName, process string) string {
+	name := strings.ToUpper(fmt.Sprintf("%s_%s_URL", appName, process))
+	return envNameReplacer.Replace(name)
+}
+
+var envNameReplacer = strings.NewReplacer("-", "_", ".", "_")
+
 // CanarySpec represents configuration for a canary deployment.
 type CanarySpec struct {
 	// +kubebuilder:validation:Minimum=0
@@ -233,6 +271,38 @@ type AppSpec struct {
 
 	// ServiceAccountName specifies a service account name to be used for this application.
 	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// ServiceBindings is a list of backing services whose credentials are provided to every process of the app.
+	ServiceBindings []ServiceBinding `json:"serviceBindings,omitempty"`
+}
+
+// ServiceBindingMode defines how credentials of a bound service are provided to processes.
+type ServiceBindingMode string
+
+const (
+	// ServiceBindingModeMount mounts the secret's keys as files to /bindings/<binding name>.
+	ServiceBindingModeMount ServiceBindingMode = "mount"
+
+	// ServiceBindingModeEnv injects the secret's keys as environment variables.
+	ServiceBindingModeEnv ServiceBindingMode = "env"
+)
+
+// ServiceBinding describes a Secret with credentials of a backing service bound to an app.
+type ServiceBinding struct {
+	// Name of the binding, it is used as a directory name of the mounted credentials
+	// and in the name of the volume with the credentials, so it must be a DNS label.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=55
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// SecretName is a name of a Secret in the framework's namespace.
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+
+	// Mode defines how credentials are provided to processes. The default is mount.
+	// +kubebuilder:validation:Enum=mount;env
+	Mode ServiceBindingMode `json:"mode,omitempty"`
 }
 
 // MetadataItem represent a request to add label/annotations to processes
@@ -272,6 +342,14 @@ type AppList struct {
 	Items           []App `json:"items"`
 }
 
+// PinnedImage returns the image pinned to its digest if the digest is known, e.g. "nginx:1.21@sha256:...".
+func (s *AppDeploymentSpec) PinnedImage() string {
+	if s.ImageDigest == "" || strings.Contains(s.Image, "@") {
+		return s.Image
+	}
+	return s.Image + "@" + s.ImageDigest
+}
+
 func (s *AppDeploymentSpec) setUnits(process string, units int) error {
 	for i, processSpec := range s.Processes {
 		if processSpec.Name == process {
@@ -312,6 +390,7 @@ func (app *App) SetUnits(selector Selector, units int) error {
 
 // SetEnvs extends the current list of environment variables with the provided list.
 // If the current list has an env variable from the provided list, the env variable will be updated with a new value.
+// New env variables are appended in the order of the provided list.
 func (app *App) SetEnvs(envs []Env) {
 	names := make(map[string]Env, len(envs))
 	for _, env := range envs {
@@ -326,8 +405,11 @@ func (app *App) SetEnvs(envs []Env) {
 		}
 		newEnvs = append(newEnvs, env)
 	}
-	for _, env := range names {
-		newEnvs = append(newEnvs, env)
+	for _, env := range envs {
+		if newEnv, isNew := names[env.Name]; isNew {
+			newEnvs = append(newEnvs, newEnv)
+			delete(names, env.Name)
+		}
 	}
 	app.Spec.Env = newEnvs
 }
@@ -367,6 +449,40 @@ func (app *App) UnsetEnvs(envs []string) {
 	app.Spec.Env = newEnvs
 }
 
+// Validate returns an error if the binding's name can't be used in the name of a volume.
+func (b ServiceBinding) Validate() error {
+	// a volume name of the binding is prefixed with "binding-" and must be a DNS label.
+	if errs := validation.IsDNS1123Label("binding-" + b.Name); len(errs) > 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidServiceBindingName, b.Name)
+	}
+	return nil
+}
+
+// BindService adds the binding to the app or replaces a binding with the same name.
+func (app *App) BindService(binding ServiceBinding) {
+	if binding.Mode == "" {
+		binding.Mode = ServiceBindingModeMount
+	}
+	for i, b := range app.Spec.ServiceBindings {
+		if b.Name == binding.Name {
+			app.Spec.ServiceBindings[i] = binding
+			return
+		}
+	}
+	app.Spec.ServiceBindings = append(app.Spec.ServiceBindings, binding)
+}
+
+// UnbindService removes a binding with the given name and returns true if the binding was found.
+func (app *App) UnbindService(name string) bool {
+	for i, b := range app.Spec.ServiceBindings {
+		if b.Name == name {
+			app.Spec.ServiceBindings = append(app.Spec.ServiceBindings[:i], app.Spec.ServiceBindings[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 // Stop stops processes specified by the selector.
 func (app *App) Stop(selector Selector) error {
 	return app.SetUnits(selector, 0)
@@ -1518,3 +1518,57 @@ func TestParseAppReconcileOutcome_Multiple(t *testing.T) {
 		}
 	}
 }
+
+func TestInternalService(t *testing.T) {
+	service := InternalService{Process: "worker", Host: "go-app-worker.ketch-gke.svc.cluster.local", Port: 9090}
+	require.Equal(t, "http://go-app-worker.ketch-gke.svc.cluster.local:9090", service.URL())
+	require.Equal(t, "GO_APP_WORKER_URL", InternalServiceEnvName("go-app", "worker"))
+	require.Equal(t, "APP_RPC_SERVER_URL", InternalServiceEnvName("app", "rpc.server"))
+}
+
+func TestApp_BindService(t *testing.T) {
+	app := App{}
+	app.BindService(ServiceBinding{Name: "db", SecretName: "postgres"})
+	app.BindService(ServiceBinding{Name: "cache", SecretName: "redis", Mode: ServiceBindingModeEnv})
+	app.BindService(ServiceBinding{Name: "db", SecretName: "mysql", Mode: ServiceBindingModeEnv})
+	require.Equal(t, []ServiceBinding{
+		{Name: "db", SecretName: "mysql", Mode: ServiceBindingModeEnv},
+		{Name: "cache", SecretName: "redis", Mode: ServiceBindingModeEnv},
+	}, app.Spec.ServiceBindings)
+
+	require.True(t, app.UnbindService("db"))
+	require.False(t, app.UnbindService("db"))
+	require.Equal(t, []ServiceBinding{
+		{Name: "cache", SecretName: "redis", Mode: ServiceBindingModeEnv},
+	}, app.Spec.ServiceBindings)
+}
+
+func TestAppDeploymentSpec_PinnedImage(t *testing.T) {
+	digest := "sha256:3b1c2c6d1a6f8e3a2b7c9d0e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e2f3a"
+	tests := []struct {
+		name string
+		spec AppDeploymentSpec
+		want string
+	}{
+		{
+			name: "no digest",
+			spec: AppDeploymentSpec{Image: "shipasoftware/dashboard:latest"},
+			want: "shipasoftware/dashboard:latest",
+		},
+		{
+			name: "tag pinned to digest",
+			spec: AppDeploymentSpec{Image: "shipasoftware/dashboard:latest", ImageDigest: digest},
+			want: "shipasoftware/dashboard:latest@" + digest,
+		},
+		{
+			name: "image with digest",
+			spec: AppDeploymentSpec{Image: "shipasoftware/dashboard@" + digest, ImageDigest: digest},
+			want: "shipasoftware/dashboard@" + digest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.spec.PinnedImage())
+		})
+	}
+}
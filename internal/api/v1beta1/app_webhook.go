@@ -0,0 +1,43 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// applog is for logging in this package.
+var applog = logf.Log.WithName("app-resource")
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-theketch-io-v1beta1-app,mutating=false,failurePolicy=fail,groups=theketch.io,resources=apps,versions=v1beta1,name=vapp.kb.io,sideEffects=none,admissionReviewVersions=v1beta1
+
+// SetupWebhookWithManager registers a webhook that validates apps against the framework they are deployed to.
+// The webhook needs to know who sends a request, so it is implemented as an admission.Handler.
+// Requests of managerServiceAccount, the username of the service account ketch runs as, are allowed by any framework.
+// verifySignatures verifies images of frameworks requiring signatures.
+func (r *App) SetupWebhookWithManager(mgr ctrl.Manager, managerServiceAccount string, verifySignatures SignatureVerifier) error {
+	applog.Info("registering app webhook")
+	validator, err := newMembershipValidator(mgr, func() runtime.Object { return &App{} }, managerServiceAccount, verifySignatures)
+	if err != nil {
+		return err
+	}
+	mgr.GetWebhookServer().Register("/validate-theketch-io-v1beta1-app", &webhook.Admission{Handler: validator})
+	return nil
+}
@@ -12,6 +12,9 @@ const (
 
 	// Scheduled indicates whether the has been processed by ketch-controller.
 	Scheduled ConditionType = "Scheduled"
+
+	// ServiceBindingsReady indicates whether all secrets bound to the app exist.
+	ServiceBindingsReady ConditionType = "ServiceBindingsReady"
 )
 
 // Condition contains details for the current condition of this app.
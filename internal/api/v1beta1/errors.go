@@ -24,6 +24,42 @@ const (
 	// ErrDecreaseQuota is returned when a new quota is too small.
 	ErrDecreaseQuota Error = "failed to decrease quota because the framework has more running apps than the new quota permits"
 
+	// ErrDefaultRequestExceedsLimit is returned when a framework's default request is greater than its default limit.
+	ErrDefaultRequestExceedsLimit Error = "default request must be less than or equal to default limit"
+
+	// ErrQuotaWithoutDefaultRequest is returned when a framework's quota limits cpu or memory but its limit range has no default for the resource.
+	ErrQuotaWithoutDefaultRequest Error = "a cpu or memory quota requires a default request or limit of the same resource, otherwise pods without requests are rejected"
+
+	// ErrNotFrameworkMember is returned when a user who isn't a member of a framework deploys to it.
+	ErrNotFrameworkMember Error = "user is not a member of the framework"
+
+	// ErrDecreaseJobQuota is returned when a new job quota is too small.
+	ErrDecreaseJobQuota Error = "failed to decrease job quota because the framework has more jobs than the new quota permits"
+
+	// ErrJobQuotaExceeded is returned when a job can not be created because its framework has reached the job quota.
+	ErrJobQuotaExceeded Error = "failed to create job because the framework has reached the limit of jobs"
+
+	// ErrImageRegistryNotAllowed is returned when an image doesn't come from a registry allowed by the framework's image policy.
+	ErrImageRegistryNotAllowed Error = "image registry is not allowed by the framework's image policy"
+
+	// ErrImageDigestRequired is returned when an image is referenced by tag but the framework's image policy requires a digest.
+	ErrImageDigestRequired Error = "image digest is required by the framework's image policy"
+
+	// ErrImageSignatureNotVerified is returned when an image has no signature verified by the framework's image policy.
+	ErrImageSignatureNotVerified Error = "image signature is not verified by the framework's image policy"
+
+	// ErrInvalidSignaturePolicy is returned when a framework's signature policy has invalid keys or identities.
+	ErrInvalidSignaturePolicy Error = "invalid signature policy"
+
+	// ErrInvalidNamespaceMetadata is returned when a framework's namespace labels or annotations are invalid.
+	ErrInvalidNamespaceMetadata Error = "invalid namespace metadata"
+
+	// ErrInvalidProcessDefaults is returned when a framework's process defaults have invalid or reserved pod labels or annotations.
+	ErrInvalidProcessDefaults Error = "invalid process defaults"
+
+	// ErrInvalidServiceBindingName is returned when a service binding's name isn't a DNS label.
+	ErrInvalidServiceBindingName Error = "service binding name must be a DNS label of at most 55 characters"
+
 	// ErrJobExists
 	ErrJobExists Error = "failed to create job because the job already exists"
 )
@@ -17,8 +17,17 @@ limitations under the License.
 package v1beta1
 
 import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 // +kubebuilder:object:root=true
@@ -28,6 +37,7 @@ import (
 // +kubebuilder:printcolumn:name="Target Namespace",type=string,JSONPath=`.status.namespace.name`
 // +kubebuilder:printcolumn:name="apps",type=string,JSONPath=`.status.apps`
 // +kubebuilder:printcolumn:name="quota",type=string,JSONPath=`.spec.appQuotaLimit`
+// +kubebuilder:printcolumn:name="job quota",type=string,JSONPath=`.spec.jobQuotaLimit`
 
 // Framework is the Schema for the frameworks API
 type Framework struct {
@@ -57,14 +67,308 @@ type FrameworkSpec struct {
 
 	AppQuotaLimit *int `json:"appQuotaLimit"`
 
+	// JobQuotaLimit is the maximum number of jobs in the framework, -1 means no limit.
+	JobQuotaLimit *int `json:"jobQuotaLimit,omitempty"`
+
 	IngressController IngressControllerSpec `json:"ingressController,omitempty"`
+
+	// ResourceQuota limits the total amount of compute resources and pods in the framework's namespace.
+	ResourceQuota *FrameworkResourceQuotaSpec `json:"resourceQuota,omitempty"`
+
+	// LimitRange defines default requests and limits for containers that don't specify them.
+	LimitRange *FrameworkLimitRangeSpec `json:"limitRange,omitempty"`
+
+	// NetworkPolicy controls which pods can reach pods of the framework.
+	NetworkPolicy *FrameworkNetworkPolicySpec `json:"networkPolicy,omitempty"`
+
+	// ProcessDefaults contains settings applied to processes of all apps of the framework unless an app sets them explicitly.
+	ProcessDefaults *ProcessDefaults `json:"processDefaults,omitempty"`
+
+	// Members is a list of users and groups allowed to work with the framework, if empty, anyone can deploy to the framework.
+	Members []FrameworkMember `json:"members,omitempty"`
+
+	// ImagePolicy restricts images that apps and jobs of the framework can run.
+	ImagePolicy *ImagePolicy `json:"imagePolicy,omitempty"`
+
+	// NamespaceLabels are labels that ketch maintains on the framework's namespace.
+	NamespaceLabels map[string]string `json:"namespaceLabels,omitempty"`
+
+	// NamespaceAnnotations are annotations that ketch maintains on the framework's namespace.
+	NamespaceAnnotations map[string]string `json:"namespaceAnnotations,omitempty"`
+
+	// TemplatesConfigMapName is a name of a ConfigMap in ketch's namespace with chart templates overriding the ingress controller's default templates file by file.
+	TemplatesConfigMapName string `json:"templatesConfigMapName,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=User;Group
+
+// FrameworkMemberKind is a kind of a framework member.
+type FrameworkMemberKind string
+
+const (
+	FrameworkMemberUser  FrameworkMemberKind = "User"
+	FrameworkMemberGroup FrameworkMemberKind = "Group"
+)
+
+// +kubebuilder:validation:Enum=admin;developer;viewer
+
+// FrameworkRole defines what a member can do within a framework.
+type FrameworkRole string
+
+func (r FrameworkRole) String() string { return string(r) }
+
+const (
+	// FrameworkRoleAdmin can deploy apps and jobs and manage all resources in the framework's namespace.
+	FrameworkRoleAdmin FrameworkRole = "admin"
+	// FrameworkRoleDeveloper can deploy apps and jobs and edit resources in the framework's namespace.
+	FrameworkRoleDeveloper FrameworkRole = "developer"
+	// FrameworkRoleViewer has read-only access to the framework's namespace.
+	FrameworkRoleViewer FrameworkRole = "viewer"
+)
+
+// FrameworkRoles is a list of all framework roles.
+var FrameworkRoles = []FrameworkRole{FrameworkRoleAdmin, FrameworkRoleDeveloper, FrameworkRoleViewer}
+
+// ClusterRole returns a name of a built-in ClusterRole granted to members with this role in the framework's namespace.
+func (r FrameworkRole) ClusterRole() string {
+	switch r {
+	case FrameworkRoleAdmin:
+		return "admin"
+	case FrameworkRoleDeveloper:
+		return "edit"
+	default:
+		return "view"
+	}
+}
+
+// RoleBindingName returns a name of a RoleBinding created for members with this role in the framework's namespace.
+func (r FrameworkRole) RoleBindingName() string {
+	return fmt.Sprintf("ketch-framework-%s", r)
+}
+
+// CanDeploy returns true if members with this role can deploy apps and jobs.
+func (r FrameworkRole) CanDeploy() bool {
+	return r == FrameworkRoleAdmin || r == FrameworkRoleDeveloper
+}
+
+// FrameworkMember is a user or a group with a role in a framework.
+type FrameworkMember struct {
+	Kind FrameworkMemberKind `json:"kind"`
+
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	Role FrameworkRole `json:"role"`
+}
+
+// Matches returns true if the member refers to the given user or one of the user's groups.
+func (m FrameworkMember) Matches(user authenticationv1.UserInfo) bool {
+	if m.Kind == FrameworkMemberUser {
+		return m.Name == user.Username
+	}
+	for _, group := range user.Groups {
+		if m.Name == group {
+			return true
+		}
+	}
+	return false
+}
+
+// ImagePolicy restricts images that apps and jobs of a framework can run.
+type ImagePolicy struct {
+	// AllowedRegistries is a list of prefixes images must start with, e.g. "gcr.io/my-project", if empty, any registry is allowed.
+	AllowedRegistries []string `json:"allowedRegistries,omitempty"`
+
+	// RequireDigest requires images to be referenced by digest instead of by tag.
+	RequireDigest bool `json:"requireDigest,omitempty"`
+
+	// Signatures requires images to be signed with cosign, if set, an image needs a signature
+	// verified by one of the public keys or keyless identities and must be pinned to a digest.
+	// Images of apps are pinned on deploy, images of jobs aren't, so jobs must reference their images by digest.
+	Signatures *SignaturePolicy `json:"signatures,omitempty"`
+}
+
+// SignaturePolicy describes cosign signatures images must have.
+// Signatures are read from the registry of an image, the same way "cosign verify" reads them.
+type SignaturePolicy struct {
+	// PublicKeys are PEM encoded public keys of "cosign sign --key" signatures.
+	PublicKeys []string `json:"publicKeys,omitempty"`
+
+	// Keyless are identities of "cosign sign" keyless signatures.
+	// The transparency log isn't queried, a signing certificate is checked at the time it was issued at,
+	// so a signature made with the key of an expired certificate is accepted as long as the certificate is trusted.
+	Keyless []KeylessIdentity `json:"keyless,omitempty"`
+}
+
+// KeylessIdentity is an identity a keyless signing certificate is issued to, e.g. a CI workflow.
+type KeylessIdentity struct {
+	// Issuer is the OIDC issuer of the identity, e.g. "https://token.actions.githubusercontent.com".
+	Issuer string `json:"issuer"`
+
+	// Subject is the email or URI of the identity, e.g. "https://github.com/org/repo/.github/workflows/ci.yaml@refs/heads/main".
+	Subject string `json:"subject"`
+
+	// RootCertificates are PEM encoded certificates of the authority issuing signing certificates, e.g. fulcio roots.
+	RootCertificates string `json:"rootCertificates"`
+}
+
+// Validate returns an error if the image violates the policy.
+func (p *ImagePolicy) Validate(image string) error {
+	if p == nil {
+		return nil
+	}
+	if len(p.AllowedRegistries) > 0 && !p.allowsRegistry(image) {
+		return fmt.Errorf("%w: %q doesn't belong to any of the allowed registries: %s", ErrImageRegistryNotAllowed, image, strings.Join(p.AllowedRegistries, ", "))
+	}
+	if p.RequireDigest && !hasDigest(image) {
+		return fmt.Errorf("%w: %q must be referenced as <image>@sha256:<digest>", ErrImageDigestRequired, image)
+	}
+	return nil
+}
+
+// ValidateJobImage returns an error if the image of a job's container violates the policy.
+// Jobs run their images as given, so the image must be referenced by digest when signatures are required.
+func (p *ImagePolicy) ValidateJobImage(image string) error {
+	if err := p.Validate(image); err != nil {
+		return err
+	}
+	if p.RequiresSignatures() && !hasDigest(image) {
+		return fmt.Errorf("%w: job image %q must be referenced as <image>@sha256:<digest> to verify its signature", ErrImageDigestRequired, image)
+	}
+	return nil
+}
+
+// RequiresSignatures returns true if the policy requires images to be signed.
+func (p *ImagePolicy) RequiresSignatures() bool {
+	return p != nil && p.Signatures != nil
+}
+
+// Validate returns an error if the policy has invalid public keys, identities or root certificates.
+func (p *SignaturePolicy) Validate() error {
+	if len(p.PublicKeys) == 0 && len(p.Keyless) == 0 {
+		return fmt.Errorf("%w: signatures require public keys or keyless identities", ErrInvalidSignaturePolicy)
+	}
+	for i, key := range p.PublicKeys {
+		block, _ := pem.Decode([]byte(key))
+		if block == nil {
+			return fmt.Errorf("%w: public key %d is not PEM encoded", ErrInvalidSignaturePolicy, i+1)
+		}
+		if _, err := x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+			return fmt.Errorf("%w: failed to parse public key %d: %v", ErrInvalidSignaturePolicy, i+1, err)
+		}
+	}
+	for _, id := range p.Keyless {
+		if id.Issuer == "" || id.Subject == "" {
+			return fmt.Errorf("%w: keyless identities require an issuer and a subject", ErrInvalidSignaturePolicy)
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(id.RootCertificates)) {
+			return fmt.Errorf("%w: no root certificates of %q identity", ErrInvalidSignaturePolicy, id.Subject)
+		}
+	}
+	return nil
+}
+
+func (p *ImagePolicy) allowsRegistry(image string) bool {
+	names := []string{image, fullImageName(image)}
+	for _, registry := range p.AllowedRegistries {
+		prefix := strings.TrimSuffix(registry, "/")
+		for _, name := range names {
+			if name == prefix || strings.HasPrefix(name, prefix+"/") || strings.HasPrefix(name, prefix+":") || strings.HasPrefix(name, prefix+"@") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fullImageName adds the implicit docker hub registry to an image name, "nginx" becomes "docker.io/library/nginx".
+func fullImageName(image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 1 {
+		return "docker.io/library/" + image
+	}
+	if !strings.ContainsAny(parts[0], ".:") && parts[0] != "localhost" {
+		return "docker.io/" + image
+	}
+	return image
+}
+
+func hasDigest(image string) bool {
+	i := strings.LastIndex(image, "@")
+	return i >= 0 && strings.Contains(image[i:], ":")
+}
+
+// ProcessDefaults contains default settings of processes running in a framework.
+type ProcessDefaults struct {
+	// Resources are merged with resources of a process, requests and limits of the process take precedence.
+	Resources *v1.ResourceRequirements `json:"resources,omitempty"`
+
+	// SecurityContext is merged with the security context of a process, fields set by the process take precedence.
+	SecurityContext *v1.SecurityContext `json:"securityContext,omitempty"`
+
+	// PodLabels are added to pods unless an app defines a label with the same key.
+	// The app and version labels and labels prefixed with the ketch group select pods of an app, so they are reserved.
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+
+	// PodAnnotations are added to pods unless an app defines an annotation with the same key.
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+
+	// ServiceAccountName is used by apps that don't specify a service account.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// NodeSelector is a selector which must match a node's labels for pods to be scheduled on that node.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations allow pods to be scheduled onto nodes with matching taints.
+	Tolerations []v1.Toleration `json:"tolerations,omitempty"`
+}
+
+const (
+	// FrameworkResourceQuotaName is the name of a ResourceQuota created in a framework's namespace.
+	FrameworkResourceQuotaName = "ketch-framework-quota"
+
+	// FrameworkLimitRangeName is the name of a LimitRange created in a framework's namespace.
+	FrameworkLimitRangeName = "ketch-framework-limits"
+
+	// FrameworkNetworkPolicyName is the name of a NetworkPolicy created in a framework's namespace.
+	FrameworkNetworkPolicyName = "ketch-framework-network-policy"
+)
+
+// FrameworkResourceQuotaSpec contains the hard limits of a framework's ResourceQuota.
+type FrameworkResourceQuotaSpec struct {
+	// CPU is the total amount of CPU that can be requested by all pods of the framework.
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+
+	// Memory is the total amount of memory that can be requested by all pods of the framework.
+	Memory *resource.Quantity `json:"memory,omitempty"`
+
+	// Pods is the maximum number of pods that can run in the framework's namespace.
+	Pods *int64 `json:"pods,omitempty"`
+}
+
+// FrameworkLimitRangeSpec contains default requests and limits of a framework's LimitRange.
+type FrameworkLimitRangeSpec struct {
+	// DefaultCPURequest is the CPU request of containers that don't specify it.
+	DefaultCPURequest *resource.Quantity `json:"defaultCPURequest,omitempty"`
+
+	// DefaultMemoryRequest is the memory request of containers that don't specify it.
+	DefaultMemoryRequest *resource.Quantity `json:"defaultMemoryRequest,omitempty"`
+
+	// DefaultCPULimit is the CPU limit of containers that don't specify it.
+	// It is also used as the CPU request if DefaultCPURequest is not set.
+	DefaultCPULimit *resource.Quantity `json:"defaultCPULimit,omitempty"`
+
+	// DefaultMemoryLimit is the memory limit of containers that don't specify it.
+	// It is also used as the memory request if DefaultMemoryRequest is not set.
+	DefaultMemoryLimit *resource.Quantity `json:"defaultMemoryLimit,omitempty"`
 }
 
 type FrameworkPhase string
 
 const (
-	FrameworkCreated FrameworkPhase = "Created"
-	FrameworkFailed  FrameworkPhase = "Failed"
+	FrameworkCreated     FrameworkPhase = "Created"
+	FrameworkFailed      FrameworkPhase = "Failed"
+	FrameworkTerminating FrameworkPhase = "Terminating"
 )
 
 // +kubebuilder:validation:Enum=traefik;istio;nginx
@@ -86,6 +390,66 @@ type IngressControllerSpec struct {
 	ServiceEndpoint string                `json:"serviceEndpoint,omitempty"`
 	IngressType     IngressControllerType `json:"type"`
 	ClusterIssuer   string                `json:"clusterIssuer,omitempty"`
+
+	// Namespace is the namespace where the ingress controller runs, traffic from this namespace is always allowed by network policies.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// DefaultIngressControllerNamespaces contains namespaces used by ingress controllers when IngressControllerSpec.Namespace is not set.
+var DefaultIngressControllerNamespaces = map[IngressControllerType]string{
+	TraefikIngressControllerType: "traefik",
+	IstioIngressControllerType:   "istio-system",
+	NginxIngressControllerType:   "ingress-nginx",
+}
+
+// IngressNamespace returns the namespace where the ingress controller runs.
+func (s IngressControllerSpec) IngressNamespace() string {
+	if len(s.Namespace) > 0 {
+		return s.Namespace
+	}
+	return DefaultIngressControllerNamespaces[s.IngressType]
+}
+
+// +kubebuilder:validation:Enum=open;isolated;allow-same-framework
+
+// NetworkPolicyMode defines which pods can reach pods of a framework.
+type NetworkPolicyMode string
+
+func (m NetworkPolicyMode) String() string { return string(m) }
+
+const (
+	// NetworkPolicyModeOpen allows traffic from everywhere.
+	NetworkPolicyModeOpen NetworkPolicyMode = "open"
+	// NetworkPolicyModeIsolated allows traffic only from the ingress controller and allowed frameworks.
+	NetworkPolicyModeIsolated NetworkPolicyMode = "isolated"
+	// NetworkPolicyModeAllowSameFramework additionally allows traffic between pods of the framework.
+	NetworkPolicyModeAllowSameFramework NetworkPolicyMode = "allow-same-framework"
+)
+
+// FrameworkNetworkPolicySpec contains configuration of a framework's NetworkPolicy.
+type FrameworkNetworkPolicySpec struct {
+	Mode NetworkPolicyMode `json:"mode"`
+
+	// AllowedFrameworks is a list of frameworks whose pods can reach pods of this framework.
+	AllowedFrameworks []string `json:"allowedFrameworks,omitempty"`
+}
+
+// IsOpen returns true if the framework doesn't restrict incoming traffic.
+func (s *FrameworkNetworkPolicySpec) IsOpen() bool {
+	return s == nil || len(s.Mode) == 0 || s.Mode == NetworkPolicyModeOpen
+}
+
+// Allows returns true if pods of the given framework are explicitly allowed.
+func (s *FrameworkNetworkPolicySpec) Allows(framework string) bool {
+	if s == nil {
+		return false
+	}
+	for _, name := range s.AllowedFrameworks {
+		if name == framework {
+			return true
+		}
+	}
+	return false
 }
 
 // FrameworkStatus defines the observed state of Framework
@@ -96,6 +460,52 @@ type FrameworkStatus struct {
 	Namespace *v1.ObjectReference `json:"namespace,omitempty"`
 	Apps      []string            `json:"apps,omitempty"`
 	Jobs      []string            `json:"jobs,omitempty"`
+
+	// Health summarizes the state of apps and jobs of the framework and its quota usage.
+	Health *FrameworkHealth `json:"health,omitempty"`
+}
+
+// FrameworkHealth summarizes the state of a framework's workloads.
+type FrameworkHealth struct {
+	// Apps contains the phase and readiness of each app of the framework.
+	Apps []FrameworkAppHealth `json:"apps,omitempty"`
+
+	// ReadyApps is the number of apps whose latest deployment is rolled out.
+	ReadyApps int `json:"readyApps"`
+
+	// FailedJobs is the number of jobs that failed to be scheduled or whose pods failed.
+	FailedJobs int `json:"failedJobs"`
+
+	// ResourceQuota references the framework's ResourceQuota, its status reports the usage of the framework's namespace.
+	ResourceQuota *v1.ObjectReference `json:"resourceQuota,omitempty"`
+}
+
+// FrameworkAppHealth contains the state of an app of a framework.
+type FrameworkAppHealth struct {
+	Name  string   `json:"name"`
+	Phase AppPhase `json:"phase"`
+	Ready bool     `json:"ready"`
+	// Message explains why the app isn't healthy.
+	Message string `json:"message,omitempty"`
+}
+
+// Healthy returns true if all apps are ready and no jobs failed.
+func (h *FrameworkHealth) Healthy() bool {
+	return h == nil || (h.ReadyApps == len(h.Apps) && h.FailedJobs == 0)
+}
+
+// CanDeploy returns true if the user is a member allowed to deploy apps and jobs to the framework.
+// Frameworks without members are open to everyone.
+func (f *Framework) CanDeploy(user authenticationv1.UserInfo) bool {
+	if len(f.Spec.Members) == 0 {
+		return true
+	}
+	for _, member := range f.Spec.Members {
+		if member.Role.CanDeploy() && member.Matches(user) {
+			return true
+		}
+	}
+	return false
 }
 
 func (p *Framework) HasApp(name string) bool {
@@ -115,3 +525,163 @@ func (f *Framework) HasJob(name string) bool {
 	}
 	return false
 }
+
+// OwnsApp returns true if the app belongs to the framework or is still installed in the framework's namespace.
+func (f *Framework) OwnsApp(app App) bool {
+	return app.Spec.Framework == f.Name || f.HasApp(app.Name)
+}
+
+// OwnsJob returns true if the job belongs to the framework or is still installed in the framework's namespace.
+func (f *Framework) OwnsJob(job Job) bool {
+	return job.Spec.Framework == f.Name || f.HasJob(job.Name)
+}
+
+// CanAddJob returns false if the job isn't in the framework yet and the framework has reached its job quota.
+func (f *Framework) CanAddJob(name string) bool {
+	if f.HasJob(name) || f.Spec.JobQuotaLimit == nil || *f.Spec.JobQuotaLimit == -1 {
+		return true
+	}
+	return len(f.Status.Jobs) < *f.Spec.JobQuotaLimit
+}
+
+// Hard returns the hard limits of a ResourceQuota.
+func (q FrameworkResourceQuotaSpec) Hard() v1.ResourceList {
+	hard := v1.ResourceList{}
+	if q.CPU != nil {
+		hard[v1.ResourceCPU] = *q.CPU
+	}
+	if q.Memory != nil {
+		hard[v1.ResourceMemory] = *q.Memory
+	}
+	if q.Pods != nil {
+		hard[v1.ResourcePods] = *resource.NewQuantity(*q.Pods, resource.DecimalSI)
+	}
+	return hard
+}
+
+// IsEmpty returns true if no limit is set.
+func (q FrameworkResourceQuotaSpec) IsEmpty() bool {
+	return q.CPU == nil && q.Memory == nil && q.Pods == nil
+}
+
+// LimitRangeItem returns a container LimitRangeItem with default requests and limits.
+func (l FrameworkLimitRangeSpec) LimitRangeItem() v1.LimitRangeItem {
+	item := v1.LimitRangeItem{Type: v1.LimitTypeContainer}
+	defaults := func(cpu, memory *resource.Quantity) v1.ResourceList {
+		if cpu == nil && memory == nil {
+			return nil
+		}
+		list := v1.ResourceList{}
+		if cpu != nil {
+			list[v1.ResourceCPU] = *cpu
+		}
+		if memory != nil {
+			list[v1.ResourceMemory] = *memory
+		}
+		return list
+	}
+	item.DefaultRequest = defaults(l.DefaultCPURequest, l.DefaultMemoryRequest)
+	item.Default = defaults(l.DefaultCPULimit, l.DefaultMemoryLimit)
+	return item
+}
+
+// IsEmpty returns true if no default is set.
+func (l FrameworkLimitRangeSpec) IsEmpty() bool {
+	return l.DefaultCPURequest == nil && l.DefaultMemoryRequest == nil && l.DefaultCPULimit == nil && l.DefaultMemoryLimit == nil
+}
+
+// ValidateNamespaceMetadata checks that namespace labels and annotations are valid
+// and don't use the reserved "theketch.io/" prefix.
+func (s FrameworkSpec) ValidateNamespaceMetadata() error {
+	for _, key := range sortedKeys(s.NamespaceLabels) {
+		if err := validateNamespaceMetadataKey(key); err != nil {
+			return fmt.Errorf("%w: label %q: %v", ErrInvalidNamespaceMetadata, key, err)
+		}
+		if errs := validation.IsValidLabelValue(s.NamespaceLabels[key]); len(errs) > 0 {
+			return fmt.Errorf("%w: label %q: %s", ErrInvalidNamespaceMetadata, key, strings.Join(errs, ", "))
+		}
+	}
+	for _, key := range sortedKeys(s.NamespaceAnnotations) {
+		if err := validateNamespaceMetadataKey(key); err != nil {
+			return fmt.Errorf("%w: annotation %q: %v", ErrInvalidNamespaceMetadata, key, err)
+		}
+	}
+	return nil
+}
+
+// reservedPodLabels are pod labels ketch uses to select pods of an app.
+var reservedPodLabels = map[string]bool{"app": true, "version": true}
+
+// Validate checks that pod labels and annotations are valid and pod labels don't override labels
+// ketch uses to select pods of an app: "app", "version" and labels with the ketch group prefix.
+func (d ProcessDefaults) Validate() error {
+	for _, key := range sortedKeys(d.PodLabels) {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("%w: pod label %q: %s", ErrInvalidProcessDefaults, key, strings.Join(errs, ", "))
+		}
+		if reservedPodLabels[key] || strings.HasPrefix(key, Group+"/") {
+			return fmt.Errorf("%w: pod label %q is reserved by ketch", ErrInvalidProcessDefaults, key)
+		}
+		if errs := validation.IsValidLabelValue(d.PodLabels[key]); len(errs) > 0 {
+			return fmt.Errorf("%w: pod label %q: %s", ErrInvalidProcessDefaults, key, strings.Join(errs, ", "))
+		}
+	}
+	for _, key := range sortedKeys(d.PodAnnotations) {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("%w: pod annotation %q: %s", ErrInvalidProcessDefaults, key, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+func validateNamespaceMetadataKey(key string) error {
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, ", "))
+	}
+	if prefix := Group + "/"; strings.HasPrefix(key, prefix) {
+		return fmt.Errorf("the %q prefix is reserved", prefix)
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ValidateResources checks the framework's limit range and that a cpu or memory quota comes with a default request of the resource.
+// Kubernetes rejects pods without requests of a resource limited by a quota, and apps don't set requests unless configured to.
+func (s FrameworkSpec) ValidateResources() error {
+	limitRange := FrameworkLimitRangeSpec{}
+	if s.LimitRange != nil {
+		if err := s.LimitRange.Validate(); err != nil {
+			return err
+		}
+		limitRange = *s.LimitRange
+	}
+	if s.ResourceQuota == nil {
+		return nil
+	}
+	if s.ResourceQuota.CPU != nil && limitRange.DefaultCPURequest == nil && limitRange.DefaultCPULimit == nil {
+		return ErrQuotaWithoutDefaultRequest
+	}
+	if s.ResourceQuota.Memory != nil && limitRange.DefaultMemoryRequest == nil && limitRange.DefaultMemoryLimit == nil {
+		return ErrQuotaWithoutDefaultRequest
+	}
+	return nil
+}
+
+// Validate returns an error if a default request is greater than the corresponding default limit.
+func (l FrameworkLimitRangeSpec) Validate() error {
+	if l.DefaultCPURequest != nil && l.DefaultCPULimit != nil && l.DefaultCPURequest.Cmp(*l.DefaultCPULimit) > 0 {
+		return ErrDefaultRequestExceedsLimit
+	}
+	if l.DefaultMemoryRequest != nil && l.DefaultMemoryLimit != nil && l.DefaultMemoryRequest.Cmp(*l.DefaultMemoryLimit) > 0 {
+		return ErrDefaultRequestExceedsLimit
+	}
+	return nil
+}
@@ -2,6 +2,12 @@ package v1beta1
 
 import (
 	"testing"
+
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestFramework_HasApp(t *testing.T) {
@@ -37,3 +43,226 @@ func TestFramework_HasApp(t *testing.T) {
 		})
 	}
 }
+
+func TestFramework_OwnsApp(t *testing.T) {
+	framework := &Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Status:     FrameworkStatus{Apps: []string{"moved-app"}},
+	}
+	tests := []struct {
+		name string
+		app  App
+		want bool
+	}{
+		{
+			name: "app belongs to the framework",
+			app:  App{ObjectMeta: metav1.ObjectMeta{Name: "app"}, Spec: AppSpec{Framework: "gke"}},
+			want: true,
+		},
+		{
+			name: "app is moved to another framework but still installed",
+			app:  App{ObjectMeta: metav1.ObjectMeta{Name: "moved-app"}, Spec: AppSpec{Framework: "aws"}},
+			want: true,
+		},
+		{
+			name: "app belongs to another framework",
+			app:  App{ObjectMeta: metav1.ObjectMeta{Name: "app"}, Spec: AppSpec{Framework: "aws"}},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := framework.OwnsApp(tt.app); got != tt.want {
+				t.Errorf("OwnsApp() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrameworkResourceQuotaSpec_Hard(t *testing.T) {
+	cpu := resource.MustParse("4")
+	memory := resource.MustParse("8Gi")
+	pods := int64(20)
+	quota := FrameworkResourceQuotaSpec{CPU: &cpu, Memory: &memory, Pods: &pods}
+	want := v1.ResourceList{
+		v1.ResourceCPU:    cpu,
+		v1.ResourceMemory: memory,
+		v1.ResourcePods:   *resource.NewQuantity(20, resource.DecimalSI),
+	}
+	require.Equal(t, want, quota.Hard())
+	require.False(t, quota.IsEmpty())
+	require.True(t, FrameworkResourceQuotaSpec{}.IsEmpty())
+}
+
+func TestFrameworkLimitRangeSpec_LimitRangeItem(t *testing.T) {
+	cpuRequest := resource.MustParse("100m")
+	memoryLimit := resource.MustParse("256Mi")
+	limitRange := FrameworkLimitRangeSpec{DefaultCPURequest: &cpuRequest, DefaultMemoryLimit: &memoryLimit}
+	want := v1.LimitRangeItem{
+		Type:           v1.LimitTypeContainer,
+		Default:        v1.ResourceList{v1.ResourceMemory: memoryLimit},
+		DefaultRequest: v1.ResourceList{v1.ResourceCPU: cpuRequest},
+	}
+	require.Equal(t, want, limitRange.LimitRangeItem())
+	require.Nil(t, limitRange.Validate())
+}
+
+func TestFramework_CanDeploy(t *testing.T) {
+	framework := &Framework{
+		Spec: FrameworkSpec{
+			Members: []FrameworkMember{
+				{Kind: FrameworkMemberGroup, Name: "team-a", Role: FrameworkRoleAdmin},
+				{Kind: FrameworkMemberUser, Name: "bob", Role: FrameworkRoleViewer},
+			},
+		},
+	}
+	tests := []struct {
+		name string
+		user authenticationv1.UserInfo
+		want bool
+	}{
+		{name: "group member", user: authenticationv1.UserInfo{Username: "alice", Groups: []string{"team-a"}}, want: true},
+		{name: "viewer", user: authenticationv1.UserInfo{Username: "bob"}, want: false},
+		{name: "not a member", user: authenticationv1.UserInfo{Username: "eve", Groups: []string{"team-b"}}, want: false},
+		{name: "cluster admin", user: authenticationv1.UserInfo{Username: "admin", Groups: []string{"system:masters"}}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, framework.CanDeploy(tt.user))
+		})
+	}
+	require.True(t, (&Framework{}).CanDeploy(authenticationv1.UserInfo{Username: "eve"}))
+}
+
+func TestImagePolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *ImagePolicy
+		image   string
+		wantErr error
+	}{
+		{
+			name:  "no policy",
+			image: "nginx:latest",
+		},
+		{
+			name:   "allowed registry",
+			policy: &ImagePolicy{AllowedRegistries: []string{"docker.io/theketch", "gcr.io/shipa/"}},
+			image:  "gcr.io/shipa/app:v1",
+		},
+		{
+			name:   "docker hub image",
+			policy: &ImagePolicy{AllowedRegistries: []string{"docker.io/library"}},
+			image:  "nginx:latest",
+		},
+		{
+			name:    "registry is not allowed",
+			policy:  &ImagePolicy{AllowedRegistries: []string{"gcr.io/shipa"}},
+			image:   "gcr.io/shipa-fake/app:v1",
+			wantErr: ErrImageRegistryNotAllowed,
+		},
+		{
+			name:   "image with digest",
+			policy: &ImagePolicy{RequireDigest: true},
+			image:  "gcr.io/shipa/app@sha256:2d43ab7f4a5bd6a8f1a2a5c3d3e8f1b4b4a2a2d7a6b9c0d1e2f3a4b5c6d7e8f9",
+		},
+		{
+			name:    "image with tag",
+			policy:  &ImagePolicy{RequireDigest: true},
+			image:   "gcr.io/shipa/app:v1",
+			wantErr: ErrImageDigestRequired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.image)
+			if tt.wantErr == nil {
+				require.Nil(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestFrameworkSpec_ValidateNamespaceMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    FrameworkSpec
+		wantErr bool
+	}{
+		{
+			name: "valid labels and annotations",
+			spec: FrameworkSpec{
+				NamespaceLabels:      map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+				NamespaceAnnotations: map[string]string{"linkerd.io/inject": "enabled"},
+			},
+		},
+		{
+			name:    "invalid label key",
+			spec:    FrameworkSpec{NamespaceLabels: map[string]string{"cost center": "42"}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid label value",
+			spec:    FrameworkSpec{NamespaceLabels: map[string]string{"owner": "team a"}},
+			wantErr: true,
+		},
+		{
+			name:    "reserved annotation prefix",
+			spec:    FrameworkSpec{NamespaceAnnotations: map[string]string{"theketch.io/managed-labels": "owner"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.spec.ValidateNamespaceMetadata()
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidNamespaceMetadata)
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestProcessDefaults_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults ProcessDefaults
+		wantErr  bool
+	}{
+		{
+			name: "valid labels and annotations",
+			defaults: ProcessDefaults{
+				PodLabels:      map[string]string{"team": "platform"},
+				PodAnnotations: map[string]string{"prometheus.io/scrape": "true"},
+			},
+		},
+		{
+			name:     "selector label",
+			defaults: ProcessDefaults{PodLabels: map[string]string{"app": "shared"}},
+			wantErr:  true,
+		},
+		{
+			name:     "ketch label",
+			defaults: ProcessDefaults{PodLabels: map[string]string{"theketch.io/app-name": "shared"}},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid label value",
+			defaults: ProcessDefaults{PodLabels: map[string]string{"team": "team a"}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.defaults.Validate()
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidProcessDefaults)
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
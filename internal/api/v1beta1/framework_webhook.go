@@ -60,6 +60,22 @@ var _ webhook.Validator = &Framework{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *Framework) ValidateCreate() error {
 	frameworklog.Info("validate create", "name", r.Name)
+	if err := r.Spec.ValidateNamespaceMetadata(); err != nil {
+		return err
+	}
+	if err := r.Spec.ValidateResources(); err != nil {
+		return err
+	}
+	if r.Spec.ProcessDefaults != nil {
+		if err := r.Spec.ProcessDefaults.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.Spec.ImagePolicy.RequiresSignatures() {
+		if err := r.Spec.ImagePolicy.Signatures.Validate(); err != nil {
+			return err
+		}
+	}
 	client := frameworkmgr.GetClient()
 	ctx := context.TODO()
 	frameworks := FrameworkList{}
@@ -83,6 +99,23 @@ func (r *Framework) ValidateUpdate(old runtime.Object) error {
 		return fmt.Errorf("can't validate framework update")
 	}
 
+	if err := r.Spec.ValidateNamespaceMetadata(); err != nil {
+		return err
+	}
+	if err := r.Spec.ValidateResources(); err != nil {
+		return err
+	}
+	if r.Spec.ProcessDefaults != nil {
+		if err := r.Spec.ProcessDefaults.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.Spec.ImagePolicy.RequiresSignatures() {
+		if err := r.Spec.ImagePolicy.Signatures.Validate(); err != nil {
+			return err
+		}
+	}
+
 	c := frameworkmgr.GetClient()
 	if oldFramework.Spec.NamespaceName != r.Spec.NamespaceName {
 		if len(r.Status.Apps) > 0 {
@@ -104,6 +137,11 @@ func (r *Framework) ValidateUpdate(old runtime.Object) error {
 			return ErrDecreaseQuota
 		}
 	}
+	if r.Spec.JobQuotaLimit != nil && *r.Spec.JobQuotaLimit != -1 && *r.Spec.JobQuotaLimit < len(r.Status.Jobs) {
+		if oldFramework.Spec.JobQuotaLimit == nil || *oldFramework.Spec.JobQuotaLimit != *r.Spec.JobQuotaLimit {
+			return ErrDecreaseJobQuota
+		}
+	}
 	return nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -106,6 +107,34 @@ func TestFramework_ValidateCreate(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "default request exceeds default limit",
+			client: &mocks.MockClient{},
+			framework: Framework{
+				Spec: FrameworkSpec{
+					NamespaceName: "theketch-namespace",
+					LimitRange: &FrameworkLimitRangeSpec{
+						DefaultMemoryRequest: resource.NewQuantity(512*1024*1024, resource.BinarySI),
+						DefaultMemoryLimit:   resource.NewQuantity(256*1024*1024, resource.BinarySI),
+					},
+				},
+			},
+			wantErr: ErrDefaultRequestExceedsLimit,
+		},
+		{
+			name:   "cpu quota without default cpu request",
+			client: &mocks.MockClient{},
+			framework: Framework{
+				Spec: FrameworkSpec{
+					NamespaceName: "theketch-namespace",
+					ResourceQuota: &FrameworkResourceQuotaSpec{CPU: resource.NewQuantity(4, resource.DecimalSI)},
+					LimitRange: &FrameworkLimitRangeSpec{
+						DefaultMemoryRequest: resource.NewQuantity(256*1024*1024, resource.BinarySI),
+					},
+				},
+			},
+			wantErr: ErrQuotaWithoutDefaultRequest,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -212,6 +241,32 @@ func TestFramework_ValidateUpdate(t *testing.T) {
 			},
 			wantErr: ErrDecreaseQuota,
 		},
+		{
+			name: "failed to decrease job quota",
+			framework: Framework{
+				ObjectMeta: metav1.ObjectMeta{Name: "framework-1"},
+				Spec:       FrameworkSpec{NamespaceName: "ketch-namespace", JobQuotaLimit: conversions.IntPtr(1)},
+				Status: FrameworkStatus{
+					Jobs: []string{"job-1", "job-2"},
+				},
+			},
+			client: &mocks.MockClient{
+				OnList: func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+					frameworks := list.(*FrameworkList)
+					frameworks.Items = []Framework{
+						{ObjectMeta: metav1.ObjectMeta{Name: "framework-1"}, Spec: FrameworkSpec{NamespaceName: "ketch-namespace"}},
+					}
+					return nil
+				},
+			},
+			old: &Framework{
+				Spec: FrameworkSpec{NamespaceName: "ketch-namespace", JobQuotaLimit: conversions.IntPtr(5)},
+				Status: FrameworkStatus{
+					Jobs: []string{"job-1", "job-2"},
+				},
+			},
+			wantErr: ErrDecreaseJobQuota,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -71,7 +71,8 @@ type Policy struct {
 
 // Container represents a single container run in a Job
 type Container struct {
-	Name    string   `json:"name"`
+	Name string `json:"name"`
+	// Image is run as given, it must be referenced by digest if the framework requires signed images.
 	Image   string   `json:"image"`
 	Command []string `json:"command"`
 }
@@ -20,7 +20,9 @@ import (
 	"context"
 	"fmt"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -31,13 +33,23 @@ var joblog = logf.Log.WithName("job-resource")
 
 var jobmgr manager = nil
 
-func (r *Job) SetupWebhookWithManager(mgr ctrl.Manager) error {
+// SetupWebhookWithManager registers webhooks validating jobs.
+// Requests of managerServiceAccount, the username of the service account ketch runs as, are allowed by any framework.
+// verifySignatures verifies images of frameworks requiring signatures.
+func (r *Job) SetupWebhookWithManager(mgr ctrl.Manager, managerServiceAccount string, verifySignatures SignatureVerifier) error {
 	jobmgr = mgr
+	validator, err := newMembershipValidator(mgr, func() runtime.Object { return &Job{} }, managerServiceAccount, verifySignatures)
+	if err != nil {
+		return err
+	}
+	mgr.GetWebhookServer().Register("/validate-theketch-io-v1beta1-job-membership", &webhook.Admission{Handler: validator})
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
 }
 
+// +kubebuilder:webhook:verbs=create;update,path=/validate-theketch-io-v1beta1-job-membership,mutating=false,failurePolicy=fail,groups=theketch.io,resources=jobs,versions=v1beta1,name=vjobmembership.kb.io,sideEffects=none,admissionReviewVersions=v1beta1
+
 // +kubebuilder:webhook:verbs=create;update;delete,path=/validate-theketch-io-v1beta1-job,mutating=false,failurePolicy=fail,groups=theketch.io,resources=jobs,versions=v1beta1,name=vjob.kb.io,sideEffects=none,admissionReviewVersions=v1beta1
 
 var _ webhook.Validator = &Job{}
@@ -55,14 +67,23 @@ func (r *Job) ValidateCreate() error {
 			return ErrJobExists
 		}
 	}
+	framework := Framework{}
+	if err := client.Get(context.Background(), types.NamespacedName{Name: r.Spec.Framework}, &framework); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !framework.CanAddJob(r.Name) {
+		return ErrJobQuotaExceeded
+	}
 	return nil
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *Job) ValidateUpdate(old runtime.Object) error {
 	joblog.Info("validate update", "name", r.Name)
-	oldJob, ok := old.(*Job)
-	if !ok {
+	if _, ok := old.(*Job); !ok {
 		return fmt.Errorf("can't validate job update")
 	}
 	client := jobmgr.GetClient()
@@ -70,8 +91,14 @@ func (r *Job) ValidateUpdate(old runtime.Object) error {
 	if err := client.List(context.Background(), &jobs); err != nil {
 		return err
 	}
-	for _, job := range jobs.Items {
-		if job.Spec.Name == oldJob.Spec.Name {
+	return r.ValidateUniqueName(jobs.Items)
+}
+
+// ValidateUniqueName returns ErrJobExists if another job of the list has the same name in its spec.
+// The job itself is skipped, so the job can be updated.
+func (r *Job) ValidateUniqueName(jobs []Job) error {
+	for _, job := range jobs {
+		if job.Name != r.Name && job.Spec.Name == r.Spec.Name {
 			return ErrJobExists
 		}
 	}
@@ -10,6 +10,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/theketchio/ketch/internal/api/v1beta1/mocks"
+	"github.com/theketchio/ketch/internal/utils/conversions"
 )
 
 func TestJob_ValidateDelete(t *testing.T) {
@@ -69,6 +70,25 @@ func TestJob_ValidateCreate(t *testing.T) {
 			},
 			wantErr: ErrJobExists,
 		},
+		{
+			name: "framework reached the job quota",
+			client: &mocks.MockClient{
+				OnGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+					framework := obj.(*Framework)
+					framework.Spec.JobQuotaLimit = conversions.IntPtr(1)
+					framework.Status.Jobs = []string{"test-job"}
+					return nil
+				},
+			},
+			job: Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "another-test-job"},
+				Spec: JobSpec{
+					Name:      "another-test-job",
+					Framework: "framework",
+				},
+			},
+			wantErr: ErrJobQuotaExceeded,
+		},
 		{
 			name: "success",
 			client: &mocks.MockClient{
@@ -133,16 +153,37 @@ func TestJob_ValidateUpdate(t *testing.T) {
 				OnList: func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
 					jobs := list.(*JobList)
 					jobs.Items = []Job{
-						{ObjectMeta: metav1.ObjectMeta{Name: "job-1"}, Spec: JobSpec{Name: "test-job"}},
+						{ObjectMeta: metav1.ObjectMeta{Name: "job-1"}, Spec: JobSpec{Name: "another-job"}},
+						{ObjectMeta: metav1.ObjectMeta{Name: "job-2"}, Spec: JobSpec{Name: "test-job"}},
 					}
 					return nil
 				},
 			},
 			old: &Job{
-				Spec: JobSpec{Name: "test-job"},
+				Spec: JobSpec{Name: "another-job"},
 			},
 			wantErr: ErrJobExists,
 		},
+		{
+			name: "job updates itself",
+			job: Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job-1"},
+				Spec:       JobSpec{Name: "test-job", Parallelism: 2},
+			},
+			client: &mocks.MockClient{
+				OnList: func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+					jobs := list.(*JobList)
+					jobs.Items = []Job{
+						{ObjectMeta: metav1.ObjectMeta{Name: "job-1"}, Spec: JobSpec{Name: "test-job"}},
+					}
+					return nil
+				},
+			},
+			old: &Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "job-1"},
+				Spec:       JobSpec{Name: "test-job"},
+			},
+		},
 		{
 			name: "everything is ok",
 			job: Job{
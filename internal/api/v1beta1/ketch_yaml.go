@@ -40,7 +40,7 @@ type KetchYamlHealthcheck struct {
 	// Method defines the method used to make the http request. The default is GET.
 	Method string `json:"method,omitempty"`
 
-	// Scheme defines which scheme to use. The defaults is http.
+	// Scheme defines which scheme to use. The accepted values are http, https and grpc. The defaults is http. With grpc, the grpc.health.v1.Health service is checked by grpc_health_probe which must be present in the image, Path is the name of the service to check and "/" checks the overall server health.
 	Scheme string `json:"scheme,omitempty"`
 
 	// Headers defines optional additional header names that can be used for the request. Header names must be capitalized.
@@ -77,6 +77,18 @@ type KetchYamlKubernetesConfig struct {
 // KetchYamlKubernetesConfig contains specific configurations of a process.
 type KetchYamlProcessConfig struct {
 	Ports []KetchYamlProcessPortConfig `json:"ports,omitempty"`
+
+	// Internal exposes the process to other applications with a ClusterIP Service that has a stable cluster-local DNS name. The Service doesn't get public ingress.
+	Internal *KetchYamlProcessInternalConfig `json:"internal,omitempty"`
+}
+
+// KetchYamlProcessInternalConfig contains configuration of a process exposed inside the cluster.
+type KetchYamlProcessInternalConfig struct {
+	// Port is the port used in the published URL of the process. It must be one of the process ports. If omitted, the first port of the process is used.
+	Port int `json:"port,omitempty"`
+
+	// PublishURL makes ketch inject an <APP>_<PROCESS>_URL environment variable into other applications of the same framework.
+	PublishURL bool `json:"publish_url,omitempty"`
 }
 
 // KetchYamlKubernetesConfig contains configuration of an exposed port.
@@ -92,4 +104,18 @@ type KetchYamlProcessPortConfig struct {
 
 	// TargetPort is the port that the process is listening on. If omitted, the port value is used.
 	TargetPort int `json:"target_port,omitempty"`
+
+	// AppProtocol is the application protocol of the port. The accepted values are http, h2c (HTTP/2 without TLS) and grpc. If omitted, http is used.
+	// Nginx has no backend protocol for plain HTTP/2, so h2c can't be used by a routable port of an app in a framework with the nginx ingress controller.
+	// +kubebuilder:validation:Enum=http;h2c;grpc
+	AppProtocol string `json:"app_protocol,omitempty"`
 }
+
+const (
+	// AppProtocolHTTP is the default application protocol of a port.
+	AppProtocolHTTP = "http"
+	// AppProtocolH2C is HTTP/2 over cleartext.
+	AppProtocolH2C = "h2c"
+	// AppProtocolGRPC is gRPC, it is served over HTTP/2 as well.
+	AppProtocolGRPC = "grpc"
+)
@@ -0,0 +1,239 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	authorizationv1client "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:rbac:groups="authorization.k8s.io",resources=subjectaccessreviews,verbs=create
+
+// signatureVerificationTimeout bounds registry requests verifying signatures of a workload's images,
+// it's shorter than timeoutSeconds of the webhook, so a slow registry gets the request denied instead of timed out.
+const signatureVerificationTimeout = 25 * time.Second
+
+// SignatureVerifier returns an error if one of the images of an app or a job has no signature verified
+// by the framework's image policy. Failed verifications wrap ErrImageSignatureNotVerified or ErrImageDigestRequired.
+type SignatureVerifier func(ctx context.Context, obj runtime.Object, framework Framework, images []string) error
+
+// membershipValidator rejects requests to create or update apps and jobs
+// coming from users who aren't allowed to deploy to the target framework.
+// It also rejects apps and jobs with images that violate the framework's image policy.
+type membershipValidator struct {
+	client    client.Client
+	decoder   *admission.Decoder
+	newObject func() runtime.Object
+	// managerServiceAccount is the username of the service account ketch runs as, ketch can deploy to any framework.
+	managerServiceAccount string
+	// accessReviews checks if a user who isn't a member of a framework can update the framework,
+	// if nil, only members can deploy to a framework with members.
+	accessReviews authorizationv1client.SubjectAccessReviewInterface
+	// verifySignatures verifies signatures of the object's images if the framework requires signatures,
+	// if nil, images of such frameworks are rejected.
+	verifySignatures SignatureVerifier
+}
+
+var _ admission.Handler = &membershipValidator{}
+
+// newMembershipValidator returns a membershipValidator of objects created by newObject.
+func newMembershipValidator(mgr ctrl.Manager, newObject func() runtime.Object, managerServiceAccount string, verifySignatures SignatureVerifier) (*membershipValidator, error) {
+	decoder, err := admission.NewDecoder(mgr.GetScheme())
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+	return &membershipValidator{
+		client:                mgr.GetClient(),
+		decoder:               decoder,
+		newObject:             newObject,
+		managerServiceAccount: managerServiceAccount,
+		accessReviews:         clientset.AuthorizationV1().SubjectAccessReviews(),
+		verifySignatures:      verifySignatures,
+	}, nil
+}
+
+func (v *membershipValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation == admissionv1.Delete {
+		return admission.Allowed("")
+	}
+	obj := v.newObject()
+	if err := v.decoder.DecodeRaw(req.Object, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	var oldObj runtime.Object
+	if req.Operation == admissionv1.Update && len(req.OldObject.Raw) > 0 {
+		oldObj = v.newObject()
+		if err := v.decoder.DecodeRaw(req.OldObject, oldObj); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		// moving an object out of a framework changes the framework's workloads as well,
+		// so a user must be allowed to deploy to both frameworks.
+		if oldFrameworkName := targetFramework(oldObj); oldFrameworkName != targetFramework(obj) {
+			oldFramework := Framework{}
+			err := v.client.Get(ctx, types.NamespacedName{Name: oldFrameworkName}, &oldFramework)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return admission.Errored(http.StatusInternalServerError, err)
+			}
+			if err == nil {
+				allowed, err := v.canDeploy(ctx, req.UserInfo, oldFramework)
+				if err != nil {
+					return admission.Errored(http.StatusInternalServerError, err)
+				}
+				if !allowed {
+					return admission.Denied(fmt.Sprintf("%s: %q can't deploy to %q framework", ErrNotFrameworkMember, req.UserInfo.Username, oldFrameworkName))
+				}
+			}
+		}
+	}
+	frameworkName := targetFramework(obj)
+	framework := Framework{}
+	if err := v.client.Get(ctx, types.NamespacedName{Name: frameworkName}, &framework); err != nil {
+		if apierrors.IsNotFound(err) {
+			// there is nothing to protect, the controller reports a missing framework.
+			return admission.Allowed("")
+		}
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	allowed, err := v.canDeploy(ctx, req.UserInfo, framework)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if !allowed {
+		return admission.Denied(fmt.Sprintf("%s: %q can't deploy to %q framework", ErrNotFrameworkMember, req.UserInfo.Username, frameworkName))
+	}
+	validateImage := framework.Spec.ImagePolicy.Validate
+	if _, ok := obj.(*Job); ok {
+		validateImage = framework.Spec.ImagePolicy.ValidateJobImage
+	}
+	allowedImages := map[string]bool{}
+	// images that already run in the framework are not checked, so the policy doesn't block scaling or stopping apps.
+	if oldObj != nil && targetFramework(oldObj) == frameworkName {
+		for _, image := range workloadImages(oldObj) {
+			allowedImages[image] = true
+		}
+	}
+	for _, image := range workloadImages(obj) {
+		if allowedImages[image] {
+			continue
+		}
+		if err := validateImage(image); err != nil {
+			return admission.Denied(fmt.Sprintf("%q framework: %v", frameworkName, err))
+		}
+	}
+	if framework.Spec.ImagePolicy.RequiresSignatures() {
+		var images []string
+		for _, image := range workloadImages(obj) {
+			if !allowedImages[image] {
+				images = append(images, image)
+			}
+		}
+		if len(images) > 0 {
+			if v.verifySignatures == nil {
+				return admission.Denied(fmt.Sprintf("%q framework: %s: signatures can't be verified", frameworkName, ErrImageSignatureNotVerified))
+			}
+			verifyCtx, cancel := context.WithTimeout(ctx, signatureVerificationTimeout)
+			defer cancel()
+			if err := v.verifySignatures(verifyCtx, obj, framework, images); err != nil {
+				if errors.Is(err, ErrImageSignatureNotVerified) || errors.Is(err, ErrImageDigestRequired) {
+					return admission.Denied(fmt.Sprintf("%q framework: %v", frameworkName, err))
+				}
+				return admission.Errored(http.StatusInternalServerError, err)
+			}
+		}
+	}
+	return admission.Allowed("")
+}
+
+// canDeploy returns true if the user can deploy to the framework.
+// Besides members, ketch itself and users allowed to update the framework, who can make themselves members anyway, can deploy.
+func (v *membershipValidator) canDeploy(ctx context.Context, user authenticationv1.UserInfo, framework Framework) (bool, error) {
+	if user.Username == v.managerServiceAccount || framework.CanDeploy(user) {
+		return true, nil
+	}
+	if v.accessReviews == nil {
+		return false, nil
+	}
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	review, err := v.accessReviews.Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:     "update",
+				Group:    Group,
+				Resource: "frameworks",
+				Name:     framework.Name,
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to review access of %q to %q framework: %w", user.Username, framework.Name, err)
+	}
+	return review.Status.Allowed, nil
+}
+
+func targetFramework(obj runtime.Object) string {
+	switch o := obj.(type) {
+	case *App:
+		return o.Spec.Framework
+	case *Job:
+		return o.Spec.Framework
+	}
+	return ""
+}
+
+func workloadImages(obj runtime.Object) []string {
+	var images []string
+	switch o := obj.(type) {
+	case *App:
+		// apps run images pinned to digests, so the pinned image is checked
+		for _, deployment := range o.Spec.Deployments {
+			images = append(images, deployment.PinnedImage())
+		}
+	case *Job:
+		// jobs aren't pinned, their images must be referenced by digest to be verified
+		for _, container := range o.Spec.Containers {
+			images = append(images, container.Image)
+		}
+	}
+	return images
+}
@@ -0,0 +1,316 @@
+package v1beta1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	ctrlFake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestMembershipValidator_Handle(t *testing.T) {
+	restricted := &Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+		Spec: FrameworkSpec{
+			NamespaceName: "ketch-restricted",
+			Members: []FrameworkMember{
+				{Kind: FrameworkMemberUser, Name: "alice", Role: FrameworkRoleDeveloper},
+				{Kind: FrameworkMemberUser, Name: "bob", Role: FrameworkRoleViewer},
+			},
+		},
+	}
+	open := &Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "open"},
+		Spec:       FrameworkSpec{NamespaceName: "ketch-open"},
+	}
+	production := &Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "production"},
+		Spec: FrameworkSpec{
+			NamespaceName: "ketch-production",
+			ImagePolicy:   &ImagePolicy{AllowedRegistries: []string{"gcr.io/shipa"}},
+		},
+	}
+	signedImage := "gcr.io/shipa/app@sha256:" + strings.Repeat("a", 64)
+	signedTag := "gcr.io/shipa/app:signed"
+	unsignedImage := "gcr.io/shipa/app@sha256:" + strings.Repeat("b", 64)
+	// signatures are verified by ketch's manager, the fake verifier accepts only signedImage.
+	verifySignatures := func(ctx context.Context, obj runtime.Object, framework Framework, images []string) error {
+		if _, ok := ctx.Deadline(); !ok {
+			return fmt.Errorf("signatures verified without a timeout")
+		}
+		for _, image := range images {
+			if image != signedImage {
+				return fmt.Errorf("%w: %q", ErrImageSignatureNotVerified, image)
+			}
+		}
+		return nil
+	}
+	signed := &Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "signed"},
+		Spec: FrameworkSpec{
+			NamespaceName: "ketch-signed",
+			ImagePolicy:   &ImagePolicy{Signatures: &SignaturePolicy{PublicKeys: []string{"public key"}}},
+		},
+	}
+	scheme := runtime.NewScheme()
+	require.Nil(t, AddToScheme()(scheme))
+	decoder, err := admission.NewDecoder(scheme)
+	require.Nil(t, err)
+	// users of platform-team group can update frameworks
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		allowed := attributes.Verb == "update" && attributes.Resource == "frameworks" && attributes.Name == "restricted"
+		for _, group := range review.Spec.Groups {
+			if group == "platform-team" && allowed {
+				review.Status.Allowed = true
+			}
+		}
+		return true, review, nil
+	})
+	validator := &membershipValidator{
+		client:                ctrlFake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(restricted, open, production, signed).Build(),
+		decoder:               decoder,
+		newObject:             func() runtime.Object { return &App{} },
+		managerServiceAccount: "system:serviceaccount:ketch:ketch-controller",
+		accessReviews:         clientset.AuthorizationV1().SubjectAccessReviews(),
+		verifySignatures:      verifySignatures,
+	}
+	rawApp := func(framework string, images ...string) runtime.RawExtension {
+		app := App{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "theketch.io/v1beta1", Kind: "App"},
+			ObjectMeta: metav1.ObjectMeta{Name: "app"},
+			Spec:       AppSpec{Framework: framework},
+		}
+		for i, image := range images {
+			app.Spec.Deployments = append(app.Spec.Deployments, AppDeploymentSpec{Image: image, Version: DeploymentVersion(i + 1)})
+		}
+		bs, err := json.Marshal(app)
+		require.Nil(t, err)
+		return runtime.RawExtension{Raw: bs}
+	}
+	jobValidator := *validator
+	jobValidator.newObject = func() runtime.Object { return &Job{} }
+	rawJob := func(framework string, images ...string) runtime.RawExtension {
+		job := Job{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "theketch.io/v1beta1", Kind: "Job"},
+			ObjectMeta: metav1.ObjectMeta{Name: "job"},
+			Spec:       JobSpec{Name: "job", Framework: framework},
+		}
+		for i, image := range images {
+			job.Spec.Containers = append(job.Spec.Containers, Container{Name: fmt.Sprintf("container-%d", i), Image: image})
+		}
+		bs, err := json.Marshal(job)
+		require.Nil(t, err)
+		return runtime.RawExtension{Raw: bs}
+	}
+
+	tests := []struct {
+		name        string
+		request     admissionv1.AdmissionRequest
+		job         bool
+		wantAllowed bool
+	}{
+		{
+			name: "member creates an app",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    rawApp("restricted"),
+				UserInfo:  authenticationv1.UserInfo{Username: "alice"},
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "viewer creates an app",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    rawApp("restricted"),
+				UserInfo:  authenticationv1.UserInfo{Username: "bob"},
+			},
+		},
+		{
+			name: "framework admin updates an app",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    rawApp("restricted"),
+				UserInfo:  authenticationv1.UserInfo{Username: "admin", Groups: []string{"platform-team"}},
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "ketch updates an app",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    rawApp("restricted"),
+				UserInfo:  authenticationv1.UserInfo{Username: "system:serviceaccount:ketch:ketch-controller"},
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "another service account of ketch's namespace updates an app",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    rawApp("restricted"),
+				UserInfo:  authenticationv1.UserInfo{Username: "system:serviceaccount:ketch:default"},
+			},
+		},
+		{
+			name: "non-member moves app out of restricted framework",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    rawApp("open"),
+				OldObject: rawApp("restricted"),
+				UserInfo:  authenticationv1.UserInfo{Username: "eve"},
+			},
+		},
+		{
+			name: "member moves app out of restricted framework",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    rawApp("open"),
+				OldObject: rawApp("restricted"),
+				UserInfo:  authenticationv1.UserInfo{Username: "alice"},
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "framework without members",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    rawApp("open"),
+				UserInfo:  authenticationv1.UserInfo{Username: "eve"},
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "image from an allowed registry",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    rawApp("production", "gcr.io/shipa/app:v1"),
+				UserInfo:  authenticationv1.UserInfo{Username: "eve"},
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "image from a registry that is not allowed",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    rawApp("production", "docker.io/shipa/app:v1"),
+				UserInfo:  authenticationv1.UserInfo{Username: "eve"},
+			},
+		},
+		{
+			name: "update keeps an image that already runs in the framework",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    rawApp("production", "nginx", "gcr.io/shipa/app:v2"),
+				OldObject: rawApp("production", "nginx"),
+				UserInfo:  authenticationv1.UserInfo{Username: "eve"},
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "app is moved to a framework that doesn't allow its image",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    rawApp("production", "nginx"),
+				OldObject: rawApp("open", "nginx"),
+				UserInfo:  authenticationv1.UserInfo{Username: "eve"},
+			},
+		},
+		{
+			name: "signed image",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    rawApp("signed", signedImage),
+				UserInfo:  authenticationv1.UserInfo{Username: "eve"},
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "image without signatures",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    rawApp("signed", unsignedImage),
+				UserInfo:  authenticationv1.UserInfo{Username: "eve"},
+			},
+		},
+		{
+			name: "signed image that isn't pinned to a digest",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    rawApp("signed", signedTag),
+				UserInfo:  authenticationv1.UserInfo{Username: "eve"},
+			},
+		},
+		{
+			name: "update keeps an unsigned image that already runs in the framework",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Update,
+				Object:    rawApp("signed", unsignedImage, signedImage),
+				OldObject: rawApp("signed", unsignedImage),
+				UserInfo:  authenticationv1.UserInfo{Username: "eve"},
+			},
+			wantAllowed: true,
+		},
+		{
+			name: "job with a signed image referenced by digest",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    rawJob("signed", signedImage),
+				UserInfo:  authenticationv1.UserInfo{Username: "eve"},
+			},
+			job:         true,
+			wantAllowed: true,
+		},
+		{
+			name: "job with a signed image referenced by tag",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    rawJob("signed", signedTag),
+				UserInfo:  authenticationv1.UserInfo{Username: "eve"},
+			},
+			job: true,
+		},
+		{
+			name: "job with an unsigned image",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    rawJob("signed", signedImage, unsignedImage),
+				UserInfo:  authenticationv1.UserInfo{Username: "eve"},
+			},
+			job: true,
+		},
+		{
+			name: "missing framework",
+			request: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    rawApp("missing"),
+				UserInfo:  authenticationv1.UserInfo{Username: "eve"},
+			},
+			wantAllowed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := validator
+			if tt.job {
+				handler = &jobValidator
+			}
+			response := handler.Handle(context.Background(), admission.Request{AdmissionRequest: tt.request})
+			require.Equal(t, tt.wantAllowed, response.Allowed)
+		})
+	}
+}
@@ -3,17 +3,23 @@ package mocks
 import (
 	"context"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type MockClient struct {
+	OnGet  func(ctx context.Context, key client.ObjectKey, obj client.Object) error
 	OnList func(ctx context.Context, list runtime.Object, opts ...client.ListOption) error
 }
 
 func (m MockClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
-	panic("implement me")
+	if m.OnGet != nil {
+		return m.OnGet(ctx, key, obj)
+	}
+	return apierrors.NewNotFound(schema.GroupResource{}, key.Name)
 }
 
 func (m MockClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
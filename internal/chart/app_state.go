@@ -0,0 +1,93 @@
+package chart
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+// AppStateOptions returns options that render the app with the state of the cluster it depends on:
+// URLs of internal processes published by other apps of the framework and a checksum of the bound secrets.
+// Anything rendering an app the way the controller does must use them.
+// Names of bound secrets missing in the framework's namespace are returned as well, the app is rendered without them.
+func AppStateOptions(ctx context.Context, c client.Reader, app *ketchv1.App, framework ketchv1.Framework) ([]Option, []string, error) {
+	envs, err := InternalServiceEnvs(ctx, c, app, framework)
+	if err != nil {
+		return nil, nil, err
+	}
+	checksum, missingSecrets, err := ServiceBindingsChecksum(ctx, c, app, framework.Spec.NamespaceName)
+	if err != nil {
+		return nil, nil, err
+	}
+	return []Option{WithInternalServiceEnvs(envs), WithServiceBindingsChecksum(checksum)}, missingSecrets, nil
+}
+
+// ServiceBindingsChecksum returns a checksum of secrets bound to the app and names of bound secrets that don't exist.
+// A missing secret is part of the checksum, so pods are restarted once it's created.
+func ServiceBindingsChecksum(ctx context.Context, c client.Reader, app *ketchv1.App, namespace string) (string, []string, error) {
+	if len(app.Spec.ServiceBindings) == 0 {
+		return "", nil, nil
+	}
+	var missingSecrets []string
+	hash := sha256.New()
+	for _, binding := range app.Spec.ServiceBindings {
+		fmt.Fprintf(hash, "%s/%s/%s\n", binding.Name, binding.SecretName, binding.Mode)
+		secret := v1.Secret{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: binding.SecretName}, &secret); err != nil {
+			if k8sErrors.IsNotFound(err) {
+				missingSecrets = append(missingSecrets, binding.SecretName)
+				hash.Write([]byte("missing\n"))
+				continue
+			}
+			return "", nil, fmt.Errorf("failed to get secret %q of service binding %q: %w", binding.SecretName, binding.Name, err)
+		}
+		keys := make([]string, 0, len(secret.Data))
+		for key := range secret.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(hash, "%s=", key)
+			hash.Write(secret.Data[key])
+			hash.Write([]byte("\n"))
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), missingSecrets, nil
+}
+
+// InternalServiceEnvs returns environment variables with URLs of internal processes
+// published by other apps of the framework.
+func InternalServiceEnvs(ctx context.Context, c client.Reader, app *ketchv1.App, framework ketchv1.Framework) ([]ketchv1.Env, error) {
+	var envs []ketchv1.Env
+	for _, name := range framework.Status.Apps {
+		if name == app.Name {
+			continue
+		}
+		other := ketchv1.App{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name}, &other); err != nil {
+			if k8sErrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get app %q: %w", name, err)
+		}
+		for _, service := range other.Status.InternalServices {
+			if !service.Published {
+				continue
+			}
+			envs = append(envs, ketchv1.Env{
+				Name:  ketchv1.InternalServiceEnvName(other.Name, service.Process),
+				Value: service.URL(),
+			})
+		}
+	}
+	return envs, nil
+}
@@ -0,0 +1,87 @@
+package chart
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+func TestServiceBindingsChecksum(t *testing.T) {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "ketch-gke"},
+		Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("secret")},
+	}
+	app := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "app"},
+		Spec: ketchv1.AppSpec{
+			Framework:       "gke",
+			ServiceBindings: []ketchv1.ServiceBinding{{Name: "db", SecretName: "postgres", Mode: ketchv1.ServiceBindingModeMount}},
+		},
+	}
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme()(scheme))
+	cli := clientfake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(secret).Build()
+
+	checksum, missing, err := ServiceBindingsChecksum(context.Background(), cli, app, "ketch-gke")
+	require.Nil(t, err)
+	require.Len(t, checksum, 64)
+	require.Nil(t, missing)
+
+	secret.Data["password"] = []byte("new-secret")
+	require.Nil(t, cli.Update(context.Background(), secret))
+	newChecksum, _, err := ServiceBindingsChecksum(context.Background(), cli, app, "ketch-gke")
+	require.Nil(t, err)
+	require.NotEqual(t, checksum, newChecksum)
+
+	missingChecksum, missing, err := ServiceBindingsChecksum(context.Background(), cli, app, "ketch-aws")
+	require.Nil(t, err)
+	require.Equal(t, []string{"postgres"}, missing)
+	require.NotEqual(t, newChecksum, missingChecksum)
+
+	checksum, missing, err = ServiceBindingsChecksum(context.Background(), cli, &ketchv1.App{}, "ketch-gke")
+	require.Nil(t, err)
+	require.Equal(t, "", checksum)
+	require.Nil(t, missing)
+}
+
+func TestInternalServiceEnvs(t *testing.T) {
+	framework := ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+		Status:     ketchv1.FrameworkStatus{Apps: []string{"frontend", "backend", "removed"}},
+	}
+	backend := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend"},
+		Spec:       ketchv1.AppSpec{Framework: "gke"},
+		Status: ketchv1.AppStatus{InternalServices: []ketchv1.InternalService{
+			{Process: "worker", Host: "backend-worker.ketch-gke.svc.cluster.local", Port: 9090, Published: true},
+			{Process: "admin", Host: "backend-admin.ketch-gke.svc.cluster.local", Port: 9091},
+		}},
+	}
+	frontend := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "frontend"},
+		Spec:       ketchv1.AppSpec{Framework: "gke"},
+	}
+	scheme := runtime.NewScheme()
+	require.Nil(t, ketchv1.AddToScheme()(scheme))
+	cli := clientfake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(backend, frontend).Build()
+
+	envs, err := InternalServiceEnvs(context.Background(), cli, frontend, framework)
+	require.Nil(t, err)
+	require.Equal(t, []ketchv1.Env{
+		{Name: ketchv1.InternalServiceEnvName("backend", "worker"), Value: "http://backend-worker.ketch-gke.svc.cluster.local:9090"},
+	}, envs)
+
+	envs, err = InternalServiceEnvs(context.Background(), cli, backend, framework)
+	require.Nil(t, err)
+	require.Nil(t, envs)
+}
@@ -57,6 +57,20 @@ type app struct {
 	// ServiceAccountName specifies a service account name to be used for this application.
 	// SA should exist.
 	ServiceAccountName string `json:"serviceAccountName"`
+	// InternalServices is a list of ClusterIP Services with stable names exposing internal processes.
+	InternalServices []internalService `json:"internalServices,omitempty"`
+	// InternalServiceEnv contains URLs of internal processes published by other apps of the framework.
+	InternalServiceEnv []ketchv1.Env `json:"internalServiceEnv,omitempty"`
+}
+
+// internalService contains values for populating the internal_service.yaml
+type internalService struct {
+	Name    string           `json:"name"`
+	Process string           `json:"process"`
+	Ports   []v1.ServicePort `json:"ports"`
+	// Port is the port of the published URL.
+	Port      int32 `json:"port"`
+	Published bool  `json:"published"`
 }
 
 type deployment struct {
@@ -74,6 +88,10 @@ type Options struct {
 	// ExposedPorts are ports exposed by an image of each deployment.
 	ExposedPorts map[ketchv1.DeploymentVersion][]ketchv1.ExposedPort
 	Templates    templates.Templates
+	// InternalServiceEnvs are URLs of internal processes published by other apps.
+	InternalServiceEnvs []ketchv1.Env
+	// ServiceBindingsChecksum is a checksum of secrets bound to the app.
+	ServiceBindingsChecksum string
 }
 
 func WithExposedPorts(ports map[ketchv1.DeploymentVersion][]ketchv1.ExposedPort) Option {
@@ -95,6 +113,29 @@ func WithTemplates(tpls templates.Templates) Option {
 	}
 }
 
+// WithServiceBindingsChecksum sets a checksum of secrets bound to the app,
+// processes are restarted when it changes.
+func WithServiceBindingsChecksum(checksum string) Option {
+	return func(opts *Options) {
+		opts.ServiceBindingsChecksum = checksum
+	}
+}
+
+// WithInternalServiceEnvs injects URLs of internal processes published by other apps.
+func WithInternalServiceEnvs(envs []ketchv1.Env) Option {
+	return func(opts *Options) {
+		opts.InternalServiceEnvs = envs
+	}
+}
+
+// serviceAccountName returns the app's service account or the framework's default one.
+func serviceAccountName(application *ketchv1.App, framework *ketchv1.Framework) string {
+	if len(application.Spec.ServiceAccountName) > 0 || framework.Spec.ProcessDefaults == nil {
+		return application.Spec.ServiceAccountName
+	}
+	return framework.Spec.ProcessDefaults.ServiceAccountName
+}
+
 func imagePullSecrets(deploymentImagePullSecrets []v1.LocalObjectReference, spec ketchv1.DockerRegistrySpec) []v1.LocalObjectReference {
 	if len(deploymentImagePullSecrets) > 0 {
 		// imagePullSecrets defined for this particular deployment is higher priority.
@@ -130,14 +171,15 @@ func New(application *ketchv1.App, framework *ketchv1.Framework, opts ...Option)
 			Group:               ketchv1.Group,
 			MetadataLabels:      application.Spec.Labels,
 			MetadataAnnotations: application.Spec.Annotations,
-			ServiceAccountName:  application.Spec.ServiceAccountName,
+			ServiceAccountName:  serviceAccountName(application, framework),
+			InternalServiceEnv:  options.InternalServiceEnvs,
 		},
 		IngressController: &framework.Spec.IngressController,
 	}
 
 	for _, deploymentSpec := range application.Spec.Deployments {
 		deployment := deployment{
-			Image:   deploymentSpec.Image,
+			Image:   deploymentSpec.PinnedImage(),
 			Version: deploymentSpec.Version,
 			Labels:  deploymentSpec.Labels,
 			RoutingSettings: ketchv1.RoutingSettings{
@@ -159,6 +201,7 @@ func New(application *ketchv1.App, framework *ketchv1.Framework, opts ...Option)
 				withUnits(processSpec.Units),
 				withEnvs(processSpec.Env),
 				withPortsAndProbes(c),
+				withInternalService(c.InternalConfig(name)),
 				withLifecycle(c.Lifecycle()),
 				withSecurityContext(processSpec.SecurityContext),
 				withResourceRequirements(processSpec.Resources),
@@ -166,10 +209,15 @@ func New(application *ketchv1.App, framework *ketchv1.Framework, opts ...Option)
 				withVolumeMounts(processSpec.VolumeMounts),
 				withLabels(application.Spec.Labels, deployment.Version),
 				withAnnotations(application.Spec.Annotations, deployment.Version),
+				withServiceBindings(application.Spec.ServiceBindings, options.ServiceBindingsChecksum),
+				withProcessDefaults(framework.Spec.ProcessDefaults),
 			)
 			if err != nil {
 				return nil, err
 			}
+			if isRoutable && process.PublicServicePortProtocol == ketchv1.AppProtocolH2C && framework.Spec.IngressController.IngressType == ketchv1.NginxIngressControllerType {
+				return nil, ErrNginxH2C
+			}
 
 			// the most recent version will always be the last entry in the array. In the event of
 			// a rollback the most recent version is still in the array, but its weight will be changed to 0
@@ -185,6 +233,7 @@ func New(application *ketchv1.App, framework *ketchv1.Framework, opts ...Option)
 		values.App.Deployments = append(values.App.Deployments, deployment)
 	}
 	values.App.IsAccessible = isAppAccessible(values.App)
+	values.App.InternalServices = internalServices(values.App)
 
 	return &ApplicationChart{
 		values:    *values,
@@ -192,6 +241,48 @@ func New(application *ketchv1.App, framework *ketchv1.Framework, opts ...Option)
 	}, nil
 }
 
+// internalServices returns services for internal processes.
+// A service selects pods of all deployments of its process and uses ports of the most recent deployment.
+func internalServices(application *app) []internalService {
+	var services []internalService
+	indexes := map[string]int{}
+	for _, deployment := range application.Deployments {
+		for _, process := range deployment.Processes {
+			if !process.Internal {
+				continue
+			}
+			service := internalService{
+				Name:      fmt.Sprintf("%s-%s", application.Name, process.Name),
+				Process:   process.Name,
+				Ports:     process.ServicePorts,
+				Port:      process.InternalServicePort,
+				Published: process.PublishURL,
+			}
+			if i, ok := indexes[process.Name]; ok {
+				services[i] = service
+				continue
+			}
+			indexes[process.Name] = len(services)
+			services = append(services, service)
+		}
+	}
+	return services
+}
+
+// InternalServices returns cluster-local services of internal processes deployed to the given namespace.
+func (chrt ApplicationChart) InternalServices(namespace string) []ketchv1.InternalService {
+	var services []ketchv1.InternalService
+	for _, service := range chrt.values.App.InternalServices {
+		services = append(services, ketchv1.InternalService{
+			Process:   service.Process,
+			Host:      fmt.Sprintf("%s.%s.svc.cluster.local", service.Name, namespace),
+			Port:      service.Port,
+			Published: service.Published,
+		})
+	}
+	return services
+}
+
 func (chrt ApplicationChart) getValuesMap() (map[string]interface{}, error) {
 	bs, err := yaml.Marshal(chrt.values)
 	if err != nil {
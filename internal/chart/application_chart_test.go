@@ -359,3 +359,196 @@ func TestNewApplicationChart(t *testing.T) {
 		})
 	}
 }
+
+func TestApplicationChart_InternalServices(t *testing.T) {
+	framework := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "framework"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+	}
+	ketchYaml := func(port int) *ketchv1.KetchYamlData {
+		return &ketchv1.KetchYamlData{
+			Kubernetes: &ketchv1.KetchYamlKubernetesConfig{
+				Processes: map[string]ketchv1.KetchYamlProcessConfig{
+					"worker": {
+						Ports:    []ketchv1.KetchYamlProcessPortConfig{{Protocol: "TCP", Port: port}},
+						Internal: &ketchv1.KetchYamlProcessInternalConfig{PublishURL: true},
+					},
+				},
+			},
+		}
+	}
+	application := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend"},
+		Spec: ketchv1.AppSpec{
+			Framework: "framework",
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Image:     "shipasoftware/go-app:v1",
+					Version:   1,
+					Processes: []ketchv1.ProcessSpec{{Name: "web"}, {Name: "worker"}},
+					KetchYaml: ketchYaml(9090),
+				},
+				{
+					Image:     "shipasoftware/go-app:v2",
+					Version:   2,
+					Processes: []ketchv1.ProcessSpec{{Name: "web"}, {Name: "worker"}},
+					KetchYaml: ketchYaml(9091),
+				},
+			},
+		},
+	}
+	got, err := New(application, framework,
+		WithTemplates(templates.NginxDefaultTemplates),
+		WithExposedPorts(map[ketchv1.DeploymentVersion][]ketchv1.ExposedPort{1: nil, 2: nil}),
+		WithInternalServiceEnvs([]ketchv1.Env{{Name: "FRONTEND_RPC_URL", Value: "http://frontend-rpc.ketch-gke.svc.cluster.local:8080"}}))
+	require.Nil(t, err)
+
+	want := []ketchv1.InternalService{
+		{Process: "worker", Host: "backend-worker.ketch-gke.svc.cluster.local", Port: 9091, Published: true},
+	}
+	require.Equal(t, want, got.InternalServices("ketch-gke"))
+
+	client := HelmClient{cfg: &action.Configuration{KubeClient: &fake.PrintingKubeClient{}, Releases: storage.Init(driver.NewMemory())}, namespace: "ketch-gke", c: clientfake.NewClientBuilder().Build()}
+	release, err := client.UpdateChart(*got, ChartConfig{Version: "0.0.1", AppName: application.Name}, func(install *action.Install) {
+		install.DryRun = true
+		install.ClientOnly = true
+	})
+	require.Nil(t, err)
+	require.Contains(t, release.Manifest, "name: backend-worker\n")
+	require.Contains(t, release.Manifest, "name: FRONTEND_RPC_URL")
+}
+
+func TestApplicationChart_HTTP2Upstreams(t *testing.T) {
+	framework := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "framework"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+	}
+	tests := []struct {
+		name        string
+		templates   templates.Templates
+		appProtocol string
+		want        string
+	}{
+		{name: "nginx grpc", templates: templates.NginxDefaultTemplates, appProtocol: ketchv1.AppProtocolGRPC, want: `nginx.ingress.kubernetes.io/backend-protocol: "GRPC"`},
+		{name: "istio grpc", templates: templates.IstioDefaultTemplates, appProtocol: ketchv1.AppProtocolGRPC, want: "h2UpgradePolicy: UPGRADE"},
+		{name: "istio h2c", templates: templates.IstioDefaultTemplates, appProtocol: ketchv1.AppProtocolH2C, want: "h2UpgradePolicy: UPGRADE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			application := &ketchv1.App{
+				ObjectMeta: metav1.ObjectMeta{Name: "backend"},
+				Spec: ketchv1.AppSpec{
+					Framework: "framework",
+					Ingress:   ketchv1.IngressSpec{Cnames: ketchv1.CnameList{{Name: "backend.example.com"}}},
+					Deployments: []ketchv1.AppDeploymentSpec{
+						{
+							Image:     "shipasoftware/go-app:v1",
+							Version:   1,
+							Processes: []ketchv1.ProcessSpec{{Name: "web"}},
+							KetchYaml: &ketchv1.KetchYamlData{
+								Kubernetes: &ketchv1.KetchYamlKubernetesConfig{
+									Processes: map[string]ketchv1.KetchYamlProcessConfig{
+										"web": {Ports: []ketchv1.KetchYamlProcessPortConfig{{Protocol: "TCP", Port: 9000, AppProtocol: tt.appProtocol}}},
+									},
+								},
+							},
+							RoutingSettings: ketchv1.RoutingSettings{Weight: 100},
+						},
+					},
+				},
+			}
+			got, err := New(application, framework,
+				WithTemplates(tt.templates),
+				WithExposedPorts(map[ketchv1.DeploymentVersion][]ketchv1.ExposedPort{1: nil}))
+			require.Nil(t, err)
+
+			client := HelmClient{cfg: &action.Configuration{KubeClient: &fake.PrintingKubeClient{}, Releases: storage.Init(driver.NewMemory())}, namespace: "ketch-gke", c: clientfake.NewClientBuilder().Build()}
+			release, err := client.UpdateChart(*got, ChartConfig{Version: "0.0.1", AppName: application.Name}, func(install *action.Install) {
+				install.DryRun = true
+				install.ClientOnly = true
+			})
+			require.Nil(t, err)
+			require.Contains(t, release.Manifest, tt.want)
+		})
+	}
+}
+
+func TestNew_NginxH2C(t *testing.T) {
+	framework := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "framework"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName:     "ketch-gke",
+			IngressController: ketchv1.IngressControllerSpec{IngressType: ketchv1.NginxIngressControllerType},
+		},
+	}
+	application := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend"},
+		Spec: ketchv1.AppSpec{
+			Framework: "framework",
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Image:     "shipasoftware/go-app:v1",
+					Version:   1,
+					Processes: []ketchv1.ProcessSpec{{Name: "web"}},
+					KetchYaml: &ketchv1.KetchYamlData{
+						Kubernetes: &ketchv1.KetchYamlKubernetesConfig{
+							Processes: map[string]ketchv1.KetchYamlProcessConfig{
+								"web": {Ports: []ketchv1.KetchYamlProcessPortConfig{{Protocol: "TCP", Port: 9000, AppProtocol: ketchv1.AppProtocolH2C}}},
+							},
+						},
+					},
+					RoutingSettings: ketchv1.RoutingSettings{Weight: 100},
+				},
+			},
+		},
+	}
+	_, err := New(application, framework,
+		WithTemplates(templates.NginxDefaultTemplates),
+		WithExposedPorts(map[ketchv1.DeploymentVersion][]ketchv1.ExposedPort{1: nil}))
+	require.Equal(t, ErrNginxH2C, err)
+}
+
+func TestNew_ProcessDefaults(t *testing.T) {
+	framework := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "framework"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-gke",
+			ProcessDefaults: &ketchv1.ProcessDefaults{
+				ServiceAccountName: "framework-sa",
+				NodeSelector:       map[string]string{"pool": "apps"},
+				Tolerations:        []v1.Toleration{{Key: "dedicated", Operator: v1.TolerationOpExists}},
+			},
+		},
+	}
+	application := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend"},
+		Spec: ketchv1.AppSpec{
+			Framework: "framework",
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{
+					Image:     "shipasoftware/go-app:v1",
+					Version:   1,
+					Processes: []ketchv1.ProcessSpec{{Name: "web", Cmd: []string{"web"}}},
+				},
+			},
+		},
+	}
+	got, err := New(application, framework, WithTemplates(templates.NginxDefaultTemplates), WithExposedPorts(map[ketchv1.DeploymentVersion][]ketchv1.ExposedPort{1: nil}))
+	require.Nil(t, err)
+	require.Equal(t, "framework-sa", got.values.App.ServiceAccountName)
+
+	client := HelmClient{cfg: &action.Configuration{KubeClient: &fake.PrintingKubeClient{}, Releases: storage.Init(driver.NewMemory())}, namespace: "ketch-gke", c: clientfake.NewClientBuilder().Build()}
+	release, err := client.UpdateChart(*got, ChartConfig{Version: "0.0.1", AppName: application.Name}, func(install *action.Install) {
+		install.DryRun = true
+		install.ClientOnly = true
+	})
+	require.Nil(t, err)
+	require.Contains(t, release.Manifest, "serviceAccountName: framework-sa\n")
+	require.Contains(t, release.Manifest, "      nodeSelector:\n        pool: apps\n")
+	require.Contains(t, release.Manifest, "      tolerations:\n        - key: dedicated\n          operator: Exists\n")
+
+	application.Spec.ServiceAccountName = "app-sa"
+	got, err = New(application, framework, WithTemplates(templates.NginxDefaultTemplates), WithExposedPorts(map[ketchv1.DeploymentVersion][]ketchv1.ExposedPort{1: nil}))
+	require.Nil(t, err)
+	require.Equal(t, "app-sa", got.values.App.ServiceAccountName)
+}
@@ -1,10 +1,13 @@
 package chart
 
 import (
+	"path"
 	"path/filepath"
+	"strings"
 
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
 	"sigs.k8s.io/yaml"
 )
 
@@ -46,3 +49,41 @@ func getValuesMap(i interface{}) (map[string]interface{}, error) {
 	}
 	return chartutil.ReadValues(bs)
 }
+
+// Render renders templates of the chart without installing it and returns non-empty manifests keyed by template filename.
+func Render(tv TemplateValuer, config ChartConfig, namespace string) (map[string]string, error) {
+	files, err := bufferedFiles(config, tv.GetTemplates(), tv.GetValues())
+	if err != nil {
+		return nil, err
+	}
+	chrt, err := loader.LoadFiles(files)
+	if err != nil {
+		return nil, err
+	}
+	vals, err := getValuesMap(tv.GetValues())
+	if err != nil {
+		return nil, err
+	}
+	options := chartutil.ReleaseOptions{
+		Name:      tv.GetName(),
+		Namespace: namespace,
+		Revision:  1,
+		IsInstall: true,
+	}
+	values, err := chartutil.ToRenderValues(chrt, vals, options, chartutil.DefaultCapabilities)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := engine.Render(chrt, values)
+	if err != nil {
+		return nil, err
+	}
+	manifests := make(map[string]string, len(rendered))
+	for name, content := range rendered {
+		if len(strings.TrimSpace(content)) == 0 {
+			continue
+		}
+		manifests[path.Base(name)] = content
+	}
+	return manifests, nil
+}
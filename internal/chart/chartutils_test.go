@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/templates"
 	"github.com/theketchio/ketch/internal/utils/conversions"
 )
 
@@ -171,3 +172,29 @@ func TestGetValuesMap(t *testing.T) {
 		})
 	}
 }
+
+func TestRender(t *testing.T) {
+	job := &ketchv1.Job{
+		Spec: ketchv1.JobSpec{
+			Version:   "v1",
+			Name:      "hello",
+			Framework: "myframework",
+			Containers: []ketchv1.Container{
+				{Name: "hello", Image: "ubuntu", Command: []string{"pwd"}},
+			},
+		},
+	}
+	chrt := NewJobChart(job, WithTemplates(templates.Templates{Yamls: map[string]string{
+		"job.yaml":   "name: {{ $.Values.job.name }}\nnamespace: {{ $.Release.Namespace }}",
+		"empty.yaml": "{{- if false }}name: {{ $.Values.job.name }}{{- end }}",
+	}}))
+	manifests, err := Render(chrt, ChartConfig{Version: "v0.0.1", AppName: "hello", AppVersion: "v1"}, "ketch-myframework")
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{"job.yaml": "name: hello\nnamespace: ketch-myframework"}, manifests)
+
+	chrt = NewJobChart(job, WithTemplates(templates.Templates{Yamls: map[string]string{
+		"job.yaml": "name: {{ $.Values.job.name ",
+	}}))
+	_, err = Render(chrt, ChartConfig{Version: "v0.0.1", AppName: "hello", AppVersion: "v1"}, "ketch-myframework")
+	require.NotNil(t, err)
+}
@@ -59,6 +59,9 @@ func (c Configurator) Probes(port int32) (Probes, error) {
 	if hc.TimeoutSeconds == 0 {
 		hc.TimeoutSeconds = defaultHealthcheckTimeoutSeconds
 	}
+	if strings.ToLower(hc.Scheme) == grpcHealthcheckScheme {
+		return grpcProbes(*hc, port), nil
+	}
 	if !hc.UseInRouter {
 		url := fmt.Sprintf("%s://localhost:%d/%s", hc.Scheme, port, strings.TrimPrefix(hc.Path, "/"))
 		result.Readiness = &apiv1.Probe{
@@ -103,6 +106,33 @@ func (c Configurator) Probes(port int32) (Probes, error) {
 	return result, nil
 }
 
+// grpcProbes returns probes that call the grpc.health.v1.Health service of the process.
+// Kubernetes doesn't support gRPC probes natively, so grpc_health_probe is used to check the health.
+func grpcProbes(hc ketchv1.KetchYamlHealthcheck, port int32) Probes {
+	if hc.AllowedFailures == 0 {
+		hc.AllowedFailures = defaultHealthcheckAllowedFailures
+	}
+	command := []string{"grpc_health_probe", fmt.Sprintf("-addr=localhost:%d", port)}
+	if service := strings.Trim(hc.Path, "/"); service != "" {
+		command = append(command, fmt.Sprintf("-service=%s", service))
+	}
+	probe := &apiv1.Probe{
+		FailureThreshold: int32(hc.AllowedFailures),
+		PeriodSeconds:    int32(hc.IntervalSeconds),
+		TimeoutSeconds:   int32(hc.TimeoutSeconds),
+		Handler: apiv1.Handler{
+			Exec: &apiv1.ExecAction{
+				Command: command,
+			},
+		},
+	}
+	result := Probes{Readiness: probe}
+	if hc.ForceRestart {
+		result.Liveness = probe
+	}
+	return result
+}
+
 func (c Configurator) Lifecycle() *apiv1.Lifecycle {
 	if c.data.Hooks == nil {
 		return nil
@@ -143,6 +173,14 @@ func (c Configurator) ProcessPortConfigs(process string) []ketchv1.KetchYamlProc
 	return portConfigs
 }
 
+// InternalConfig returns configuration of a process exposed inside the cluster or nil if the process isn't internal.
+func (c Configurator) InternalConfig(process string) *ketchv1.KetchYamlProcessInternalConfig {
+	if c.data.Kubernetes == nil {
+		return nil
+	}
+	return c.data.Kubernetes.Processes[process].Internal
+}
+
 func (c Configurator) ContainerPortsForProcess(process string) []apiv1.ContainerPort {
 	ports := c.ProcessPortConfigs(process)
 	containerPorts := make([]apiv1.ContainerPort, 0, len(ports))
@@ -190,7 +228,7 @@ func (c Configurator) ServicePortsForProcess(process string) []apiv1.ServicePort
 		if len(portConfig.Name) > 0 {
 			name = portConfig.Name
 		} else {
-			name = fmt.Sprintf("%s-%d", defaultHttpPortName, i+1)
+			name = fmt.Sprintf("%s-%d", defaultPortName(portConfig.AppProtocol), i+1)
 		}
 
 		sp := apiv1.ServicePort{
@@ -199,7 +237,23 @@ func (c Configurator) ServicePortsForProcess(process string) []apiv1.ServicePort
 			Protocol:   apiv1.Protocol(portConfig.Protocol),
 			TargetPort: targetPort,
 		}
+		if len(portConfig.AppProtocol) > 0 {
+			appProtocol := strings.ToLower(portConfig.AppProtocol)
+			sp.AppProtocol = &appProtocol
+		}
 		servicePorts = append(servicePorts, sp)
 	}
 	return servicePorts
 }
+
+// defaultPortName returns a name prefix for a port with the given application protocol.
+// Istio uses the prefix to select the protocol of a port.
+func defaultPortName(appProtocol string) string {
+	switch strings.ToLower(appProtocol) {
+	case ketchv1.AppProtocolGRPC:
+		return defaultGrpcPortName
+	case ketchv1.AppProtocolH2C:
+		return defaultHttp2PortName
+	}
+	return defaultHttpPortName
+}
@@ -0,0 +1,140 @@
+package chart
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+func TestConfigurator_Probes(t *testing.T) {
+	tests := []struct {
+		name        string
+		healthcheck *ketchv1.KetchYamlHealthcheck
+		want        Probes
+		wantErr     bool
+	}{
+		{
+			name: "no healthcheck",
+		},
+		{
+			name: "http with use_in_router",
+			healthcheck: &ketchv1.KetchYamlHealthcheck{
+				Path:         "/health",
+				UseInRouter:  true,
+				ForceRestart: true,
+			},
+			want: Probes{
+				Readiness: &apiv1.Probe{
+					FailureThreshold: 3,
+					PeriodSeconds:    10,
+					TimeoutSeconds:   60,
+					Handler: apiv1.Handler{
+						HTTPGet: &apiv1.HTTPGetAction{Path: "/health", Port: intstr.FromInt(8080), Scheme: "HTTP"},
+					},
+				},
+				Liveness: &apiv1.Probe{
+					FailureThreshold: 3,
+					PeriodSeconds:    10,
+					TimeoutSeconds:   60,
+					Handler: apiv1.Handler{
+						HTTPGet: &apiv1.HTTPGetAction{Path: "/health", Port: intstr.FromInt(8080), Scheme: "HTTP"},
+					},
+				},
+			},
+		},
+		{
+			name: "http with use_in_router and POST method",
+			healthcheck: &ketchv1.KetchYamlHealthcheck{
+				Path:        "/health",
+				Method:      "POST",
+				UseInRouter: true,
+			},
+			wantErr: true,
+		},
+		{
+			name: "grpc overall health",
+			healthcheck: &ketchv1.KetchYamlHealthcheck{
+				Path:   "/",
+				Scheme: "grpc",
+			},
+			want: Probes{
+				Readiness: &apiv1.Probe{
+					FailureThreshold: 3,
+					PeriodSeconds:    10,
+					TimeoutSeconds:   60,
+					Handler: apiv1.Handler{
+						Exec: &apiv1.ExecAction{Command: []string{"grpc_health_probe", "-addr=localhost:8080"}},
+					},
+				},
+			},
+		},
+		{
+			name: "grpc service health with liveness",
+			healthcheck: &ketchv1.KetchYamlHealthcheck{
+				Path:            "helloworld.Greeter",
+				Scheme:          "GRPC",
+				ForceRestart:    true,
+				AllowedFailures: 5,
+				IntervalSeconds: 20,
+				TimeoutSeconds:  2,
+			},
+			want: Probes{
+				Readiness: &apiv1.Probe{
+					FailureThreshold: 5,
+					PeriodSeconds:    20,
+					TimeoutSeconds:   2,
+					Handler: apiv1.Handler{
+						Exec: &apiv1.ExecAction{Command: []string{"grpc_health_probe", "-addr=localhost:8080", "-service=helloworld.Greeter"}},
+					},
+				},
+				Liveness: &apiv1.Probe{
+					FailureThreshold: 5,
+					PeriodSeconds:    20,
+					TimeoutSeconds:   2,
+					Handler: apiv1.Handler{
+						Exec: &apiv1.ExecAction{Command: []string{"grpc_health_probe", "-addr=localhost:8080", "-service=helloworld.Greeter"}},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConfigurator(&ketchv1.KetchYamlData{Healthcheck: tt.healthcheck}, Procfile{}, nil, DefaultApplicationPort)
+			got, err := c.Probes(8080)
+			if tt.wantErr {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestConfigurator_ServicePortsForProcess(t *testing.T) {
+	data := &ketchv1.KetchYamlData{
+		Kubernetes: &ketchv1.KetchYamlKubernetesConfig{
+			Processes: map[string]ketchv1.KetchYamlProcessConfig{
+				"web": {
+					Ports: []ketchv1.KetchYamlProcessPortConfig{
+						{Protocol: "TCP", Port: 9000, AppProtocol: ketchv1.AppProtocolGRPC},
+						{Protocol: "TCP", Port: 9001, TargetPort: 8001, AppProtocol: ketchv1.AppProtocolH2C},
+						{Name: "metrics", Protocol: "TCP", Port: 9002},
+					},
+				},
+			},
+		},
+	}
+	c := NewConfigurator(data, Procfile{}, nil, DefaultApplicationPort)
+	want := []apiv1.ServicePort{
+		{Name: "grpc-default-1", Protocol: "TCP", Port: 9000, TargetPort: intstr.FromInt(9000), AppProtocol: stringRef("grpc")},
+		{Name: "http2-default-2", Protocol: "TCP", Port: 9001, TargetPort: intstr.FromInt(8001), AppProtocol: stringRef("h2c")},
+		{Name: "metrics", Protocol: "TCP", Port: 9002, TargetPort: intstr.FromInt(9002)},
+	}
+	require.Equal(t, want, c.ServicePortsForProcess("web"))
+}
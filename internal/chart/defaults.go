@@ -3,9 +3,16 @@ package chart
 const (
 	defaultHealthcheckScheme          = "http"
 	defaultHttpPortName               = "http-default"
+	defaultHttp2PortName              = "http2-default"
+	defaultGrpcPortName               = "grpc-default"
+	grpcHealthcheckScheme             = "grpc"
 	defaultHealthcheckTimeoutSeconds  = 60
 	defaultHealthcheckIntervalSeconds = 10
 	defaultHealthcheckAllowedFailures = 3
 	DefaultApplicationPort            = 8888
 	DefaultRoutableProcessName        = "web"
+	// ServiceBindingRoot is a directory where credentials of bound services are mounted.
+	ServiceBindingRoot         = "/bindings"
+	serviceBindingRootEnv      = "SERVICE_BINDING_ROOT"
+	serviceBindingVolumePrefix = "binding"
 )
@@ -80,6 +80,7 @@ type InstallOption func(install *action.Install)
 // UpdateChart checks if the app chart is already installed and performs "helm install" or "helm update" operation.
 func (c HelmClient) UpdateChart(tv TemplateValuer, config ChartConfig, opts ...InstallOption) (*release.Release, error) {
 	appName := tv.GetName()
+	_, job := tv.(*JobChart)
 	files, err := bufferedFiles(config, tv.GetTemplates(), tv.GetValues())
 	if err != nil {
 		return nil, err
@@ -100,8 +101,10 @@ func (c HelmClient) UpdateChart(tv TemplateValuer, config ChartConfig, opts ...I
 		clientInstall.ReleaseName = appName
 		clientInstall.Namespace = c.namespace
 		clientInstall.PostRenderer = &postRender{
-			namespace: c.namespace,
-			cli:       c.c,
+			namespace:   c.namespace,
+			cli:         c.c,
+			releaseName: appName,
+			job:         job,
 		}
 		for _, opt := range opts {
 			opt(clientInstall)
@@ -119,8 +122,10 @@ func (c HelmClient) UpdateChart(tv TemplateValuer, config ChartConfig, opts ...I
 	// Let's set it to minimal to disable "helm rollback".
 	updateClient.MaxHistory = 1
 	updateClient.PostRenderer = &postRender{
-		namespace: c.namespace,
-		cli:       c.c,
+		namespace:   c.namespace,
+		cli:         c.c,
+		releaseName: appName,
+		job:         job,
 	}
 	shouldUpdate, err := c.isHelmChartStatusActionable(c.statusFunc, appName, helmStatusActionMapUpdate)
 	if err != nil || !shouldUpdate {
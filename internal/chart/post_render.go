@@ -3,57 +3,198 @@ package chart
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"sort"
 	"strings"
 
 	"helm.sh/helm/v3/pkg/postrender"
 	v1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/kustomize/api/krusty"
 	kTypes "sigs.k8s.io/kustomize/api/types"
 	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+const (
+	// PostRenderSuffix is a suffix of names of configmaps with kustomize post-render patches.
+	PostRenderSuffix = "-postrender"
+
+	// PostRenderManifestsFile is the name of the file with rendered manifests a kustomization refers to.
+	PostRenderManifestsFile = "app.yaml"
+
+	kustomizationDirectory = "postrender"
 )
 
+// PostRenderAppLabel returns a label set on a post-render configmap to the name of the only app it patches.
+func PostRenderAppLabel() string {
+	return ketchv1.Group + "/postrender-app"
+}
+
+// PostRenderPatchLabel returns a label set on a post-render configmap to the name of its patch.
+func PostRenderPatchLabel() string {
+	return ketchv1.Group + "/postrender-patch"
+}
+
+// PostRenderSelectorAnnotation returns an annotation set on a post-render configmap to a label selector of apps it patches.
+func PostRenderSelectorAnnotation() string {
+	return ketchv1.Group + "/postrender-selector"
+}
+
+// PostRenderPatchedAtAnnotation returns an annotation set on an app to the time its post-render patches changed,
+// updating the app makes ketch render it again with the patches.
+func PostRenderPatchedAtAnnotation() string {
+	return ketchv1.Group + "/postrender-patched-at"
+}
+
 var _ postrender.PostRenderer = &postRender{}
 
 type postRender struct {
 	cli       client.Client
 	namespace string
+	// releaseName is the name of the app or job whose manifests are rendered.
+	releaseName string
+	// job is true if the release is a job, jobs share names with apps, so scoped patches of an app aren't applied to them.
+	job bool
 }
 
 func (p *postRender) Run(renderedManifests *bytes.Buffer) (modifiedManifests *bytes.Buffer, err error) {
-
-	var configMapList v1.ConfigMapList
-	opts := &client.ListOptions{Namespace: p.namespace}
-	if err := p.cli.List(context.Background(), &configMapList, opts); err != nil {
+	ctx := context.Background()
+	getReleaseApp := func() (*ketchv1.App, error) {
+		if p.job {
+			return nil, nil
+		}
+		return getApp(ctx, p.cli, p.releaseName)
+	}
+	manifests, err := applyPostRenderPatches(ctx, p.cli, p.namespace, getReleaseApp, renderedManifests.Bytes())
+	if err != nil {
 		return nil, err
 	}
+	return bytes.NewBuffer(manifests), nil
+}
 
-	fs := filesys.MakeFsInMemory()
-	if err := fs.Mkdir(p.namespace); err != nil {
+// PostRender applies post-render patches stored in the namespace to rendered manifests of the app, app is nil for a job.
+// Configmaps created without "ketch app patch add" are merged into one kustomization and patch all apps and jobs as before,
+// they may split the kustomization and its patches across configmaps.
+// Patches added with "ketch app patch add" are applied to apps only, one by one ordered by names of their configmaps.
+func PostRender(ctx context.Context, cli client.Client, namespace string, app *ketchv1.App, manifests []byte) ([]byte, error) {
+	return applyPostRenderPatches(ctx, cli, namespace, func() (*ketchv1.App, error) { return app, nil }, manifests)
+}
+
+// applyPostRenderPatches is PostRender getting the app only if the namespace has scoped patches.
+func applyPostRenderPatches(ctx context.Context, cli client.Client, namespace string, releaseApp func() (*ketchv1.App, error), manifests []byte) ([]byte, error) {
+	var configMapList v1.ConfigMapList
+	opts := &client.ListOptions{Namespace: namespace}
+	if err := cli.List(ctx, &configMapList, opts); err != nil {
 		return nil, err
 	}
+	sort.Slice(configMapList.Items, func(i, j int) bool {
+		return configMapList.Items[i].Name < configMapList.Items[j].Name
+	})
 
-	var postrenderFound bool
+	legacyFiles := map[string]string{}
+	var patches []v1.ConfigMap
 	for _, cm := range configMapList.Items {
-		if !strings.HasSuffix(cm.Name, "-postrender") {
+		if !strings.HasSuffix(cm.Name, PostRenderSuffix) {
 			continue
 		}
-		postrenderFound = true
-
-		for k, v := range cm.Data {
-			fileName := p.namespace + "/" + k
-			if err := fs.WriteFile(fileName, []byte(v)); err != nil {
-				return nil, err
+		if !isScopedPostRender(cm) {
+			for name, content := range cm.Data {
+				legacyFiles[name] = content
 			}
+			continue
 		}
+		patches = append(patches, cm)
 	}
+	if len(legacyFiles) > 0 {
+		var err error
+		if manifests, err = Kustomize(manifests, legacyFiles); err != nil {
+			return nil, fmt.Errorf("failed to apply post-render patches: %w", err)
+		}
+	}
+	if len(patches) == 0 {
+		return manifests, nil
+	}
+	app, err := releaseApp()
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return manifests, nil
+	}
+	for _, cm := range patches {
+		applies, err := PostRenderPatchApplies(cm, app.Name, app.Labels)
+		if err != nil {
+			return nil, err
+		}
+		if !applies {
+			continue
+		}
+		if manifests, err = Kustomize(manifests, cm.Data); err != nil {
+			return nil, fmt.Errorf("failed to apply %q post-render patch: %w", cm.Name, err)
+		}
+	}
+	return manifests, nil
+}
 
-	// return original manifests, otherwise begin postrender
-	if !postrenderFound {
-		return renderedManifests, nil
+// isScopedPostRender returns true if the post-render configmap is added by "ketch app patch add" or scoped to apps.
+func isScopedPostRender(cm v1.ConfigMap) bool {
+	if _, ok := cm.Labels[PostRenderPatchLabel()]; ok {
+		return true
+	}
+	if _, ok := cm.Labels[PostRenderAppLabel()]; ok {
+		return true
 	}
+	_, ok := cm.Annotations[PostRenderSelectorAnnotation()]
+	return ok
+}
 
-	if err := fs.WriteFile(p.namespace+"/app.yaml", renderedManifests.Bytes()); err != nil {
+// getApp returns the app, nil is returned if there is no such app.
+func getApp(ctx context.Context, cli client.Client, name string) (*ketchv1.App, error) {
+	var app ketchv1.App
+	if err := cli.Get(ctx, types.NamespacedName{Name: name}, &app); err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &app, nil
+}
+
+// PostRenderPatchApplies returns true if the post-render configmap patches the app.
+// A configmap with PostRenderAppLabel patches only the referenced app,
+// a configmap with PostRenderSelectorAnnotation patches apps with matching labels,
+// any other configmap patches all apps in its namespace.
+func PostRenderPatchApplies(cm v1.ConfigMap, appName string, appLabels map[string]string) (bool, error) {
+	if name, ok := cm.Labels[PostRenderAppLabel()]; ok {
+		return name == appName, nil
+	}
+	if value, ok := cm.Annotations[PostRenderSelectorAnnotation()]; ok {
+		selector, err := labels.Parse(value)
+		if err != nil {
+			return false, fmt.Errorf("invalid label selector of %q post-render patch: %w", cm.Name, err)
+		}
+		return appLabels != nil && selector.Matches(labels.Set(appLabels)), nil
+	}
+	return true, nil
+}
+
+// Kustomize builds the kustomization in files, the kustomization refers to manifests as PostRenderManifestsFile.
+func Kustomize(manifests []byte, files map[string]string) ([]byte, error) {
+	fs := filesys.MakeFsInMemory()
+	if err := fs.Mkdir(kustomizationDirectory); err != nil {
+		return nil, err
+	}
+	for name, content := range files {
+		if err := fs.WriteFile(kustomizationDirectory+"/"+name, []byte(content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := fs.WriteFile(kustomizationDirectory+"/"+PostRenderManifestsFile, manifests); err != nil {
 		return nil, err
 	}
 
@@ -66,13 +207,9 @@ func (p *postRender) Run(renderedManifests *bytes.Buffer) (modifiedManifests *by
 		},
 	})
 
-	result, err := kustomizer.Run(fs, p.namespace)
-	if err != nil {
-		return nil, err
-	}
-	y, err := result.AsYaml()
+	result, err := kustomizer.Run(fs, kustomizationDirectory)
 	if err != nil {
 		return nil, err
 	}
-	return bytes.NewBuffer(y), nil
+	return result.AsYaml()
 }
@@ -2,6 +2,7 @@ package chart
 
 import (
 	"bytes"
+	"context"
 	_ "embed"
 	"fmt"
 	"testing"
@@ -9,7 +10,12 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 )
 
 //go:embed testdata/render_yamls/prerendered-manifests.yaml
@@ -54,15 +60,81 @@ func TestPostRenderRun(t *testing.T) {
 			},
 			expected: nodeaffinityPostrender,
 		},
+		{
+			name: "postrender of the app",
+			configmap: corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "post-nodeaffinity-postrender",
+					Namespace: "fake",
+					Labels:    map[string]string{PostRenderAppLabel(): "post"},
+				},
+				Data: map[string]string{
+					"kustomization.yaml": kustomizationYaml,
+					"patch.yaml":         nodeaffinityPatchYaml,
+				},
+			},
+			expected: nodeaffinityPostrender,
+		},
+		{
+			name: "postrender of another app",
+			configmap: corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "dashboard-nodeaffinity-postrender",
+					Namespace: "fake",
+					Labels:    map[string]string{PostRenderAppLabel(): "dashboard"},
+				},
+				Data: map[string]string{
+					"kustomization.yaml": kustomizationYaml,
+					"patch.yaml":         nodeaffinityPatchYaml,
+				},
+			},
+			expected: string(prerender),
+		},
+		{
+			name: "postrender of apps with matching labels",
+			configmap: corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "nodeaffinity-postrender",
+					Namespace:   "fake",
+					Annotations: map[string]string{PostRenderSelectorAnnotation(): "team=a"},
+				},
+				Data: map[string]string{
+					"kustomization.yaml": kustomizationYaml,
+					"patch.yaml":         nodeaffinityPatchYaml,
+				},
+			},
+			expected: nodeaffinityPostrender,
+		},
+		{
+			name: "postrender of apps with other labels",
+			configmap: corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "nodeaffinity-postrender",
+					Namespace:   "fake",
+					Annotations: map[string]string{PostRenderSelectorAnnotation(): "team=b"},
+				},
+				Data: map[string]string{
+					"kustomization.yaml": kustomizationYaml,
+					"patch.yaml":         nodeaffinityPatchYaml,
+				},
+			},
+			expected: string(prerender),
+		},
 	}
 	for _, tt := range tc {
 		t.Run(tt.name, func(t *testing.T) {
-			client := fake.NewClientBuilder()
-			client.WithObjects(&tt.configmap)
+			scheme := runtime.NewScheme()
+			require.Nil(t, clientgoscheme.AddToScheme(scheme))
+			require.Nil(t, ketchv1.AddToScheme()(scheme))
+			client := fake.NewClientBuilder().WithScheme(scheme)
+			client.WithObjects(&tt.configmap, &ketchv1.App{
+				ObjectMeta: metav1.ObjectMeta{Name: "post", Labels: map[string]string{"team": "a"}},
+			})
 
 			pr := postRender{
-				namespace: "fake",
-				cli:       client.Build(),
+				namespace:   "fake",
+				cli:         client.Build(),
+				releaseName: "post",
 			}
 			result, err := pr.Run(bytes.NewBuffer(prerender))
 			fmt.Println(result.String())
@@ -71,3 +143,107 @@ func TestPostRenderRun(t *testing.T) {
 		})
 	}
 }
+
+func TestPostRender(t *testing.T) {
+	frameworkPatch := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nodeaffinity-postrender",
+			Namespace: "fake",
+			Labels:    map[string]string{PostRenderPatchLabel(): "nodeaffinity"},
+		},
+		Data: map[string]string{
+			"kustomization.yaml": kustomizationYaml,
+			"patch.yaml":         nodeaffinityPatchYaml,
+		},
+	}
+	app := &ketchv1.App{ObjectMeta: metav1.ObjectMeta{Name: "post"}}
+	tests := []struct {
+		name       string
+		app        *ketchv1.App
+		configmaps []corev1.ConfigMap
+		expected   string
+	}{
+		{
+			name: "legacy kustomization split across configmaps",
+			app:  app,
+			configmaps: []corev1.ConfigMap{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "kustomization-postrender", Namespace: "fake"},
+					Data:       map[string]string{"kustomization.yaml": kustomizationYaml},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "patch-postrender", Namespace: "fake"},
+					Data:       map[string]string{"patch.yaml": nodeaffinityPatchYaml},
+				},
+			},
+			expected: nodeaffinityPostrender,
+		},
+		{
+			name: "legacy kustomization patches jobs",
+			configmaps: []corev1.ConfigMap{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "nodeaffinity-postrender", Namespace: "fake"},
+					Data: map[string]string{
+						"kustomization.yaml": kustomizationYaml,
+						"patch.yaml":         nodeaffinityPatchYaml,
+					},
+				},
+			},
+			expected: nodeaffinityPostrender,
+		},
+		{
+			name:       "framework patch of an app",
+			app:        app,
+			configmaps: []corev1.ConfigMap{frameworkPatch},
+			expected:   nodeaffinityPostrender,
+		},
+		{
+			name:       "framework patch doesn't patch jobs",
+			configmaps: []corev1.ConfigMap{frameworkPatch},
+			expected:   string(prerender),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			require.Nil(t, clientgoscheme.AddToScheme(scheme))
+			require.Nil(t, ketchv1.AddToScheme()(scheme))
+			var objects []client.Object
+			for i := range tt.configmaps {
+				objects = append(objects, &tt.configmaps[i])
+			}
+			cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+			result, err := PostRender(context.Background(), cli, "fake", tt.app, prerender)
+			require.Nil(t, err)
+			require.Equal(t, tt.expected, string(result))
+		})
+	}
+}
+
+func TestPostRender_RunJobNamedAsApp(t *testing.T) {
+	frameworkPatch := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nodeaffinity-postrender",
+			Namespace: "fake",
+			Labels:    map[string]string{PostRenderPatchLabel(): "nodeaffinity"},
+		},
+		Data: map[string]string{
+			"kustomization.yaml": kustomizationYaml,
+			"patch.yaml":         nodeaffinityPatchYaml,
+		},
+	}
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme()(scheme))
+	cli := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&ketchv1.App{ObjectMeta: metav1.ObjectMeta{Name: "post"}}, frameworkPatch).Build()
+
+	result, err := (&postRender{cli: cli, namespace: "fake", releaseName: "post"}).Run(bytes.NewBuffer(prerender))
+	require.Nil(t, err)
+	require.Equal(t, nodeaffinityPostrender, result.String())
+
+	// a job named as the app doesn't get the app's patches
+	result, err = (&postRender{cli: cli, namespace: "fake", releaseName: "post", job: true}).Run(bytes.NewBuffer(prerender))
+	require.Nil(t, err)
+	require.Equal(t, string(prerender), result.String())
+}
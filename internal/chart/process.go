@@ -3,6 +3,7 @@ package chart
 import (
 	"errors"
 	"fmt"
+	"path"
 	"strings"
 
 	v1 "k8s.io/api/core/v1"
@@ -11,7 +12,10 @@ import (
 )
 
 var (
-	ErrPortsNotFound = errors.New("routable process should have at least one container port and one service port")
+	ErrPortsNotFound         = errors.New("routable process should have at least one container port and one service port")
+	ErrInternalPortsNotFound = errors.New("internal process should have at least one service port")
+	ErrInternalPortInvalid   = errors.New("internal port should be one of the process service ports")
+	ErrNginxH2C              = errors.New("nginx ingress controller doesn't support h2c upstreams, use grpc or http app protocol for the routable port")
 )
 
 type process struct {
@@ -22,11 +26,23 @@ type process struct {
 	ContainerPorts    []v1.ContainerPort `json:"containerPorts"`
 	ServicePorts      []v1.ServicePort   `json:"servicePorts"`
 	PublicServicePort int32              `json:"publicServicePort,omitempty"`
-	Env               []ketchv1.Env      `json:"env"`
+	// PublicServicePortProtocol is the application protocol of PublicServicePort, e.g. grpc or h2c.
+	PublicServicePortProtocol string        `json:"publicServicePortProtocol,omitempty"`
+	Env                       []ketchv1.Env `json:"env"`
+	// EnvFrom is a list of sources to populate environment variables of the process.
+	EnvFrom []v1.EnvFromSource `json:"envFrom,omitempty"`
+	// Internal is true if the process is exposed inside the cluster with a stable service name.
+	Internal bool `json:"internal,omitempty"`
+	// InternalServicePort is the port of the process URL published to other apps.
+	InternalServicePort int32 `json:"internalServicePort,omitempty"`
+	// PublishURL is true if the process URL is injected into other apps of the framework.
+	PublishURL bool `json:"publishURL,omitempty"`
 
 	SecurityContext      *v1.SecurityContext      `json:"securityContext,omitempty"`
 	ResourceRequirements *v1.ResourceRequirements `json:"resourceRequirements,omitempty"`
 	NodeSelectorTerms    []v1.NodeSelectorTerm    `json:"nodeSelectorTerms,omitempty"`
+	NodeSelector         map[string]string        `json:"nodeSelector,omitempty"`
+	Tolerations          []v1.Toleration          `json:"tolerations,omitempty"`
 	Volumes              []v1.Volume              `json:"volumes,omitempty"`
 	VolumeMounts         []v1.VolumeMount         `json:"volumeMounts,omitempty"`
 	ReadinessProbe       *v1.Probe                `json:"readinessProbe,omitempty"`
@@ -91,12 +107,88 @@ func withPortsAndProbes(c portConfigurator) processOption {
 			return err
 		}
 		p.PublicServicePort = p.ServicePorts[0].Port
+		if p.ServicePorts[0].AppProtocol != nil {
+			p.PublicServicePortProtocol = *p.ServicePorts[0].AppProtocol
+		}
 		p.LivenessProbe = probes.Liveness
 		p.ReadinessProbe = probes.Readiness
 		return nil
 	}
 }
 
+// withInternalService configures the process to be exposed inside the cluster.
+// It must be applied after withPortsAndProbes.
+func withInternalService(cfg *ketchv1.KetchYamlProcessInternalConfig) processOption {
+	return func(p *process) error {
+		if cfg == nil {
+			return nil
+		}
+		if len(p.ServicePorts) == 0 {
+			return ErrInternalPortsNotFound
+		}
+		p.Internal = true
+		p.PublishURL = cfg.PublishURL
+		p.InternalServicePort = p.ServicePorts[0].Port
+		if cfg.Port == 0 {
+			return nil
+		}
+		for _, port := range p.ServicePorts {
+			if port.Port == int32(cfg.Port) {
+				p.InternalServicePort = port.Port
+				return nil
+			}
+		}
+		return ErrInternalPortInvalid
+	}
+}
+
+// withServiceBindings provides credentials of the bound services to the process.
+// Secrets of "mount" bindings are mounted to /bindings/<binding name> as the Service Binding spec suggests,
+// keys of "env" bindings are injected as environment variables.
+// The checksum of the bound secrets is added to the pod's annotations to restart the process when the secrets change.
+func withServiceBindings(bindings []ketchv1.ServiceBinding, checksum string) processOption {
+	return func(p *process) error {
+		if len(bindings) == 0 {
+			return nil
+		}
+		var mounted bool
+		// copy volumes to keep the App's process spec untouched.
+		p.Volumes = append([]v1.Volume{}, p.Volumes...)
+		p.VolumeMounts = append([]v1.VolumeMount{}, p.VolumeMounts...)
+		for _, binding := range bindings {
+			if binding.Mode == ketchv1.ServiceBindingModeEnv {
+				p.EnvFrom = append(p.EnvFrom, v1.EnvFromSource{
+					SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: binding.SecretName}},
+				})
+				continue
+			}
+			mounted = true
+			volumeName := fmt.Sprintf("%s-%s", serviceBindingVolumePrefix, binding.Name)
+			p.Volumes = append(p.Volumes, v1.Volume{
+				Name: volumeName,
+				VolumeSource: v1.VolumeSource{
+					Secret: &v1.SecretVolumeSource{SecretName: binding.SecretName},
+				},
+			})
+			p.VolumeMounts = append(p.VolumeMounts, v1.VolumeMount{
+				Name:      volumeName,
+				MountPath: path.Join(ServiceBindingRoot, binding.Name),
+				ReadOnly:  true,
+			})
+		}
+		if mounted {
+			p.Env = append(p.Env, ketchv1.Env{Name: serviceBindingRootEnv, Value: ServiceBindingRoot})
+		}
+		if len(checksum) > 0 {
+			if p.PodMetadata.Annotations == nil {
+				p.PodMetadata.Annotations = make(map[string]string)
+			}
+			p.PodMetadata.Annotations[ketchv1.ServiceBindingsChecksumAnnotation(ketchv1.Group)] = checksum
+		}
+		return nil
+	}
+}
+
 func withSecurityContext(securityContext *v1.SecurityContext) processOption {
 	return func(p *process) error {
 		p.SecurityContext = securityContext
@@ -132,6 +224,114 @@ func withVolumeMounts(vm []v1.VolumeMount) processOption {
 	}
 }
 
+// withProcessDefaults merges the framework's process defaults under settings explicitly defined for the process,
+// so it must be applied after all other options.
+func withProcessDefaults(defaults *ketchv1.ProcessDefaults) processOption {
+	return func(p *process) error {
+		if defaults == nil {
+			return nil
+		}
+		p.ResourceRequirements = mergeResourceRequirements(defaults.Resources, p.ResourceRequirements)
+		p.SecurityContext = mergeSecurityContext(defaults.SecurityContext, p.SecurityContext)
+		p.PodMetadata.Labels = mergeMaps(defaults.PodLabels, p.PodMetadata.Labels)
+		p.PodMetadata.Annotations = mergeMaps(defaults.PodAnnotations, p.PodMetadata.Annotations)
+		p.NodeSelector = mergeMaps(defaults.NodeSelector, p.NodeSelector)
+		if len(p.Tolerations) == 0 {
+			p.Tolerations = defaults.Tolerations
+		}
+		return nil
+	}
+}
+
+// mergeMaps returns a map containing items of both maps, items of explicit take precedence.
+func mergeMaps(defaults, explicit map[string]string) map[string]string {
+	if len(defaults) == 0 {
+		return explicit
+	}
+	merged := make(map[string]string, len(defaults)+len(explicit))
+	for k, v := range defaults {
+		merged[k] = v
+	}
+	for k, v := range explicit {
+		merged[k] = v
+	}
+	return merged
+}
+
+func mergeResourceList(defaults, explicit v1.ResourceList) v1.ResourceList {
+	if len(defaults) == 0 {
+		return explicit
+	}
+	merged := make(v1.ResourceList, len(defaults)+len(explicit))
+	for name, quantity := range defaults {
+		merged[name] = quantity
+	}
+	for name, quantity := range explicit {
+		merged[name] = quantity
+	}
+	return merged
+}
+
+// mergeResourceRequirements returns requirements of a process completed with default requests and limits.
+func mergeResourceRequirements(defaults, explicit *v1.ResourceRequirements) *v1.ResourceRequirements {
+	if defaults == nil {
+		return explicit
+	}
+	if explicit == nil {
+		return defaults.DeepCopy()
+	}
+	return &v1.ResourceRequirements{
+		Limits:   mergeResourceList(defaults.Limits, explicit.Limits),
+		Requests: mergeResourceList(defaults.Requests, explicit.Requests),
+	}
+}
+
+// mergeSecurityContext returns a security context of a process completed with fields of the default one.
+func mergeSecurityContext(defaults, explicit *v1.SecurityContext) *v1.SecurityContext {
+	if defaults == nil {
+		return explicit
+	}
+	if explicit == nil {
+		return defaults.DeepCopy()
+	}
+	merged := explicit.DeepCopy()
+	d := defaults.DeepCopy()
+	if merged.Capabilities == nil {
+		merged.Capabilities = d.Capabilities
+	}
+	if merged.Privileged == nil {
+		merged.Privileged = d.Privileged
+	}
+	if merged.SELinuxOptions == nil {
+		merged.SELinuxOptions = d.SELinuxOptions
+	}
+	if merged.WindowsOptions == nil {
+		merged.WindowsOptions = d.WindowsOptions
+	}
+	if merged.RunAsUser == nil {
+		merged.RunAsUser = d.RunAsUser
+	}
+	if merged.RunAsGroup == nil {
+		merged.RunAsGroup = d.RunAsGroup
+	}
+	if merged.RunAsNonRoot == nil {
+		merged.RunAsNonRoot = d.RunAsNonRoot
+	}
+	if merged.ReadOnlyRootFilesystem == nil {
+		merged.ReadOnlyRootFilesystem = d.ReadOnlyRootFilesystem
+	}
+	if merged.AllowPrivilegeEscalation == nil {
+		merged.AllowPrivilegeEscalation = d.AllowPrivilegeEscalation
+	}
+	if merged.ProcMount == nil {
+		merged.ProcMount = d.ProcMount
+	}
+	if merged.SeccompProfile == nil {
+		merged.SeccompProfile = d.SeccompProfile
+	}
+	return merged
+}
+
 // withLabels returns a function that populates Kind labels.
 func withLabels(labels []ketchv1.MetadataItem, deploymentVersion ketchv1.DeploymentVersion) processOption {
 	return func(p *process) error {
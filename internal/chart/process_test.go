@@ -39,6 +39,10 @@ func boolRef(b bool) *bool {
 	return &b
 }
 
+func stringRef(s string) *string {
+	return &s
+}
+
 func TestNewProcess(t *testing.T) {
 	memorySize := resource.NewQuantity(5*1024*1024*1024, resource.BinarySI)
 	cores := resource.NewMilliQuantity(5300, resource.DecimalSI)
@@ -127,6 +131,152 @@ func TestNewProcess(t *testing.T) {
 				VolumeMounts:         volumeMounts,
 			},
 		},
+		{
+			name:        "grpc public port",
+			processName: "web",
+			isRoutable:  true,
+			options: []processOption{
+				withPortsAndProbes(
+					mockConfigurator{
+						servicePorts: map[string][]v1.ServicePort{
+							"web": {
+								{Name: "grpc-default-1", Protocol: "TCP", Port: 9000, TargetPort: intstr.IntOrString{IntVal: 9000}, AppProtocol: stringRef("grpc")},
+							},
+						},
+						containerPorts: map[string][]v1.ContainerPort{
+							"web": {
+								{ContainerPort: 9000},
+							},
+						},
+					},
+				),
+			},
+			want: &process{
+				Name:     "web",
+				Units:    ketchv1.DefaultNumberOfUnits,
+				Routable: true,
+				ContainerPorts: []v1.ContainerPort{
+					{ContainerPort: 9000},
+				},
+				ServicePorts: []v1.ServicePort{
+					{Name: "grpc-default-1", Protocol: "TCP", Port: 9000, TargetPort: intstr.IntOrString{IntVal: 9000}, AppProtocol: stringRef("grpc")},
+				},
+				PublicServicePort:         9000,
+				PublicServicePortProtocol: "grpc",
+				Env: []ketchv1.Env{
+					{Name: "port", Value: "9000"},
+					{Name: "PORT", Value: "9000"},
+					{Name: "PORT_web", Value: "9000"},
+				},
+			},
+		},
+		{
+			name:        "internal process",
+			processName: "worker",
+			isRoutable:  false,
+			options: []processOption{
+				withPortsAndProbes(
+					mockConfigurator{
+						servicePorts: map[string][]v1.ServicePort{
+							"worker": {
+								{Protocol: "TCP", Port: 9090, TargetPort: intstr.IntOrString{IntVal: 9090}},
+								{Protocol: "TCP", Port: 9091, TargetPort: intstr.IntOrString{IntVal: 9091}},
+							},
+						},
+						containerPorts: map[string][]v1.ContainerPort{
+							"worker": {
+								{ContainerPort: 9090},
+								{ContainerPort: 9091},
+							},
+						},
+					},
+				),
+				withInternalService(&ketchv1.KetchYamlProcessInternalConfig{Port: 9091, PublishURL: true}),
+			},
+			want: &process{
+				Name:              "worker",
+				Units:             ketchv1.DefaultNumberOfUnits,
+				PublicServicePort: 9090,
+				ContainerPorts: []v1.ContainerPort{
+					{ContainerPort: 9090},
+					{ContainerPort: 9091},
+				},
+				ServicePorts: []v1.ServicePort{
+					{Protocol: "TCP", Port: 9090, TargetPort: intstr.IntOrString{IntVal: 9090}},
+					{Protocol: "TCP", Port: 9091, TargetPort: intstr.IntOrString{IntVal: 9091}},
+				},
+				Internal:            true,
+				InternalServicePort: 9091,
+				PublishURL:          true,
+				Env: []ketchv1.Env{
+					{Name: "PORT_worker", Value: "9090,9091"},
+				},
+			},
+		},
+		{
+			name:        "internal process without ports",
+			processName: "worker",
+			isRoutable:  false,
+			options: []processOption{
+				withInternalService(&ketchv1.KetchYamlProcessInternalConfig{}),
+			},
+			wantErr: ErrInternalPortsNotFound,
+		},
+		{
+			name:        "internal process with unknown port",
+			processName: "worker",
+			isRoutable:  false,
+			options: []processOption{
+				withPortsAndProbes(
+					mockConfigurator{
+						servicePorts: map[string][]v1.ServicePort{
+							"worker": {
+								{Protocol: "TCP", Port: 9090, TargetPort: intstr.IntOrString{IntVal: 9090}},
+							},
+						},
+						containerPorts: map[string][]v1.ContainerPort{
+							"worker": {
+								{ContainerPort: 9090},
+							},
+						},
+					},
+				),
+				withInternalService(&ketchv1.KetchYamlProcessInternalConfig{Port: 8080}),
+			},
+			wantErr: ErrInternalPortInvalid,
+		},
+		{
+			name:        "service bindings",
+			processName: "worker",
+			isRoutable:  false,
+			options: []processOption{
+				withVolumes(volumes),
+				withServiceBindings([]ketchv1.ServiceBinding{
+					{Name: "db", SecretName: "postgres", Mode: ketchv1.ServiceBindingModeMount},
+					{Name: "cache", SecretName: "redis", Mode: ketchv1.ServiceBindingModeEnv},
+				}, "checksum"),
+			},
+			want: &process{
+				Name:  "worker",
+				Units: ketchv1.DefaultNumberOfUnits,
+				Env: []ketchv1.Env{
+					{Name: "SERVICE_BINDING_ROOT", Value: "/bindings"},
+				},
+				EnvFrom: []v1.EnvFromSource{
+					{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "redis"}}},
+				},
+				Volumes: append(volumes, v1.Volume{
+					Name:         "binding-db",
+					VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "postgres"}},
+				}),
+				VolumeMounts: []v1.VolumeMount{
+					{Name: "binding-db", MountPath: "/bindings/db", ReadOnly: true},
+				},
+				PodMetadata: extraMetadata{
+					Annotations: map[string]string{"theketch.io/service-bindings-checksum": "checksum"},
+				},
+			},
+		},
 		{
 			name:        "no service port",
 			processName: "web",
@@ -465,3 +615,72 @@ func Test_withLabels(t *testing.T) {
 		})
 	}
 }
+
+func Test_withProcessDefaults(t *testing.T) {
+	defaults := &ketchv1.ProcessDefaults{
+		Resources: &v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m"), v1.ResourceMemory: resource.MustParse("128Mi")},
+			Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("256Mi")},
+		},
+		SecurityContext: &v1.SecurityContext{RunAsNonRoot: boolRef(true), ReadOnlyRootFilesystem: boolRef(true)},
+		PodLabels:       map[string]string{"team": "platform", "tier": "default"},
+		PodAnnotations:  map[string]string{"sidecar.istio.io/inject": "false"},
+		NodeSelector:    map[string]string{"pool": "apps"},
+		Tolerations:     []v1.Toleration{{Key: "dedicated", Operator: v1.TolerationOpEqual, Value: "apps", Effect: v1.TaintEffectNoSchedule}},
+	}
+	tests := []struct {
+		name    string
+		process process
+		want    process
+	}{
+		{
+			name:    "process without explicit settings",
+			process: process{Name: "web"},
+			want: process{
+				Name:                 "web",
+				ResourceRequirements: defaults.Resources,
+				SecurityContext:      defaults.SecurityContext,
+				PodMetadata: extraMetadata{
+					Labels:      map[string]string{"team": "platform", "tier": "default"},
+					Annotations: map[string]string{"sidecar.istio.io/inject": "false"},
+				},
+				NodeSelector: map[string]string{"pool": "apps"},
+				Tolerations:  defaults.Tolerations,
+			},
+		},
+		{
+			name: "explicit settings take precedence",
+			process: process{
+				Name: "web",
+				ResourceRequirements: &v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")},
+				},
+				SecurityContext: &v1.SecurityContext{ReadOnlyRootFilesystem: boolRef(false)},
+				PodMetadata: extraMetadata{
+					Labels: map[string]string{"tier": "frontend"},
+				},
+			},
+			want: process{
+				Name: "web",
+				ResourceRequirements: &v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m"), v1.ResourceMemory: resource.MustParse("128Mi")},
+					Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("256Mi")},
+				},
+				SecurityContext: &v1.SecurityContext{RunAsNonRoot: boolRef(true), ReadOnlyRootFilesystem: boolRef(false)},
+				PodMetadata: extraMetadata{
+					Labels:      map[string]string{"team": "platform", "tier": "frontend"},
+					Annotations: map[string]string{"sidecar.istio.io/inject": "false"},
+				},
+				NodeSelector: map[string]string{"pool": "apps"},
+				Tolerations:  defaults.Tolerations,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.process
+			require.Nil(t, withProcessDefaults(defaults)(&p))
+			require.Equal(t, tt.want, p)
+		})
+	}
+}
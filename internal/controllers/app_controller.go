@@ -20,12 +20,14 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
 	"helm.sh/helm/v3/pkg/release"
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/autoscaling/v2beta1"
@@ -39,9 +41,16 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/tools/reference"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 	"github.com/theketchio/ketch/internal/chart"
@@ -54,9 +63,11 @@ type AppReconciler struct {
 	Log            logr.Logger
 	Scheme         *runtime.Scheme
 	TemplateReader templates.Reader
-	HelmFactoryFn  helmFactoryFn
-	Now            timeNowFn
-	Recorder       record.EventRecorder
+	// TemplatesNamespace is the namespace where the Operator stores chart templates.
+	TemplatesNamespace string
+	HelmFactoryFn      helmFactoryFn
+	Now                timeNowFn
+	Recorder           record.EventRecorder
 	// Group stands for k8s group of Ketch App CRD.
 	Group  string
 	Config *rest.Config
@@ -150,9 +161,17 @@ func (r *AppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		app.SetCondition(ketchv1.Scheduled, v1.ConditionFalse, scheduleResult.err.Error(), metav1.NewTime(time.Now()))
 	} else {
 		app.Status.Framework = scheduleResult.framework
+		app.Status.InternalServices = scheduleResult.internalServices
+		app.Status.TemplatesRevision = scheduleResult.templatesRevision
 		outcome := ketchv1.AppReconcileOutcome{AppName: app.Name, DeploymentCount: app.Spec.DeploymentsCount}
 		r.Recorder.Event(&app, v1.EventTypeNormal, ketchv1.AppReconcileOutcomeReason, outcome.String())
 		app.SetCondition(ketchv1.Scheduled, v1.ConditionTrue, "", metav1.NewTime(time.Now()))
+		if len(scheduleResult.missingSecrets) > 0 {
+			message := fmt.Sprintf("bound secrets %s are not found", strings.Join(scheduleResult.missingSecrets, ", "))
+			app.SetCondition(ketchv1.ServiceBindingsReady, v1.ConditionFalse, message, metav1.NewTime(time.Now()))
+		} else if len(app.Spec.ServiceBindings) > 0 || app.Status.Condition(ketchv1.ServiceBindingsReady) != nil {
+			app.SetCondition(ketchv1.ServiceBindingsReady, v1.ConditionTrue, "", metav1.NewTime(time.Now()))
+		}
 	}
 
 	if err := r.Status().Update(context.Background(), &app); err != nil {
@@ -176,6 +195,11 @@ func (r *AppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		// set default timeout
 		result = ctrl.Result{RequeueAfter: reconcileTimeout}
 	}
+
+	if scheduleResult.moving {
+		// the app is still installed in the framework it was moved from
+		result = ctrl.Result{RequeueAfter: appMoveRequeueInterval}
+	}
 	return result, err
 }
 
@@ -202,9 +226,13 @@ func hpaTargetMap(app *ketchv1.App, hpaList v2beta1.HorizontalPodAutoscalerList)
 }
 
 type appReconcileResult struct {
-	framework  *v1.ObjectReference
-	useTimeout bool
-	err        error
+	framework         *v1.ObjectReference
+	internalServices  []ketchv1.InternalService
+	templatesRevision string
+	missingSecrets    []string
+	useTimeout        bool
+	moving            bool
+	err               error
 }
 
 // isConflictError returns true if AppReconciler was trying to update an App CR and got a conflict error.
@@ -241,11 +269,9 @@ func (r *AppReconciler) reconcile(ctx context.Context, app *ketchv1.App, logger
 			err: fmt.Errorf(`framework "%s" is not linked to a kubernetes namespace`, framework.Name),
 		}
 	}
-	tpls, err := r.TemplateReader.Get(templates.IngressConfigMapName(framework.Spec.IngressController.IngressType.String()))
+	tpls, err := r.frameworkTemplates(framework)
 	if err != nil {
-		return appReconcileResult{
-			err: fmt.Errorf(`failed to read configmap with the app's chart templates: %w`, err),
-		}
+		return appReconcileResult{err: err}
 	}
 	if !framework.HasApp(app.Name) && framework.Spec.AppQuotaLimit != nil && len(framework.Status.Apps) >= *framework.Spec.AppQuotaLimit && *framework.Spec.AppQuotaLimit != -1 {
 		return appReconcileResult{
@@ -253,12 +279,16 @@ func (r *AppReconciler) reconcile(ctx context.Context, app *ketchv1.App, logger
 		}
 	}
 
-	appChrt, err := chart.New(app, &framework,
-		chart.WithExposedPorts(app.ExposedPorts()),
-		chart.WithTemplates(*tpls))
+	stateOptions, missingSecrets, err := chart.AppStateOptions(ctx, r.Client, app, framework)
 	if err != nil {
 		return appReconcileResult{err: err}
 	}
+	options := append([]chart.Option{chart.WithExposedPorts(app.ExposedPorts()), chart.WithTemplates(*tpls)}, stateOptions...)
+	appChrt, err := chart.New(app, &framework, options...)
+	if err != nil {
+		return appReconcileResult{err: err}
+	}
+	internalServices := appChrt.InternalServices(framework.Spec.NamespaceName)
 
 	patchedFramework := framework
 	if !patchedFramework.HasApp(app.Name) {
@@ -333,6 +363,13 @@ func (r *AppReconciler) reconcile(ctx context.Context, app *ketchv1.App, logger
 		}
 	}
 
+	moving, err := r.removeFromPreviousFrameworks(ctx, app, framework)
+	if err != nil {
+		return appReconcileResult{
+			err: fmt.Errorf("failed to remove app from previous framework: %w", err),
+		}
+	}
+
 	if len(app.Spec.Deployments) > 0 && !app.Spec.Canary.Active {
 		// use latest deployment and watch events for each process
 		latestDeployment := app.Spec.Deployments[len(app.Spec.Deployments)-1]
@@ -357,14 +394,128 @@ func (r *AppReconciler) reconcile(ctx context.Context, app *ketchv1.App, logger
 		// in order to ensure events actually get sent. It seems the lazyRecorder we use
 		// can stop with unhandled messages if the reconciler rapidly requeues.
 		return appReconcileResult{
-			framework:  ref,
-			useTimeout: true,
+			framework:         ref,
+			internalServices:  internalServices,
+			templatesRevision: tpls.Checksum(),
+			missingSecrets:    missingSecrets,
+			useTimeout:        true,
+			moving:            moving,
 		}
 	}
 
 	return appReconcileResult{
-		framework: ref,
+		framework:         ref,
+		internalServices:  internalServices,
+		templatesRevision: tpls.Checksum(),
+		missingSecrets:    missingSecrets,
+		moving:            moving,
+	}
+}
+
+// removeFromPreviousFrameworks uninstalls the app from frameworks it was moved from once it is ready in its current framework.
+// It returns true if the app is still waiting to be removed from a previous framework.
+func (r *AppReconciler) removeFromPreviousFrameworks(ctx context.Context, app *ketchv1.App, framework ketchv1.Framework) (bool, error) {
+	frameworks := ketchv1.FrameworkList{}
+	if err := r.List(ctx, &frameworks); err != nil {
+		return false, err
+	}
+	var previous []ketchv1.Framework
+	for _, item := range frameworks.Items {
+		if item.Name != framework.Name && item.HasApp(app.Name) {
+			previous = append(previous, item)
+		}
+	}
+	if len(previous) == 0 {
+		return false, nil
+	}
+	ready, err := appReady(ctx, r.Client, app, framework.Spec.NamespaceName)
+	if err != nil {
+		return false, err
+	}
+	if !ready {
+		return true, nil
+	}
+	for _, item := range previous {
+		// frameworks sharing a namespace share the app's helm release, which has just been upgraded by the current framework.
+		uninstall := item.Spec.NamespaceName != framework.Spec.NamespaceName
+		if err := r.removeAppFromFramework(ctx, app, item, uninstall); err != nil {
+			return false, err
+		}
+		r.Recorder.Eventf(app, v1.EventTypeNormal, ketchv1.AppReconcileUpdate, "app has been moved from %q framework to %q", item.Name, framework.Name)
+	}
+	return false, nil
+}
+
+// frameworkTemplates returns the default chart templates of the framework's ingress controller
+// with the framework's own templates layered over them.
+func (r *AppReconciler) frameworkTemplates(framework ketchv1.Framework) (*templates.Templates, error) {
+	tpls, err := r.TemplateReader.Get(templates.IngressConfigMapName(framework.Spec.IngressController.IngressType.String()))
+	if err != nil {
+		return nil, fmt.Errorf(`failed to read configmap with the app's chart templates: %w`, err)
+	}
+	if len(framework.Spec.TemplatesConfigMapName) == 0 {
+		return tpls, nil
+	}
+	overrides, err := r.TemplateReader.Get(framework.Spec.TemplatesConfigMapName)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to read configmap with the framework's chart templates: %w`, err)
+	}
+	return tpls.WithOverrides(*overrides), nil
+}
+
+// appReady returns true if all deployments of the latest app version are rolled out in the namespace.
+func appReady(ctx context.Context, c client.Reader, app *ketchv1.App, namespace string) (bool, error) {
+	if len(app.Spec.Deployments) == 0 {
+		return true, nil
+	}
+	latestDeployment := app.Spec.Deployments[len(app.Spec.Deployments)-1]
+	for _, process := range latestDeployment.Processes {
+		var dep appsv1.Deployment
+		err := c.Get(ctx, client.ObjectKey{
+			Namespace: namespace,
+			Name:      fmt.Sprintf("%s-%s-%d", app.GetName(), process.Name, latestDeployment.Version),
+		}, &dep)
+		if k8sErrors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		replicas := int32(1)
+		if dep.Spec.Replicas != nil {
+			replicas = *dep.Spec.Replicas
+		}
+		if dep.Status.ObservedGeneration < dep.Generation || dep.Status.UpdatedReplicas < replicas || dep.Status.ReadyReplicas < replicas {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// removeAppFromFramework removes the app from the framework's status and, if uninstall is true,
+// uninstalls the app's helm chart from the framework's namespace.
+func (r *AppReconciler) removeAppFromFramework(ctx context.Context, app *ketchv1.App, framework ketchv1.Framework, uninstall bool) error {
+	if uninstall && uninstallHelmChart(r.Group, app.Annotations) {
+		helmClient, err := r.HelmFactoryFn(framework.Spec.NamespaceName)
+		if err != nil {
+			return err
+		}
+		if err = helmClient.DeleteChart(app.Name); err != nil {
+			return err
+		}
+	}
+
+	patchedFramework := framework
+
+	patchedFramework.Status.Apps = make([]string, 0, len(patchedFramework.Status.Apps))
+	for _, name := range framework.Status.Apps {
+		if name == app.Name {
+			continue
+		}
+		patchedFramework.Status.Apps = append(patchedFramework.Status.Apps, name)
 	}
+	mergePatch := client.MergeFrom(&framework)
+	return r.Status().Patch(ctx, &patchedFramework, mergePatch)
 }
 
 // watchDeployEvents watches a namespace for events and, after a deployment has started updating, records events
@@ -680,31 +831,9 @@ func (r *AppReconciler) deleteChart(ctx context.Context, app *ketchv1.App) error
 		if !framework.HasApp(app.Name) {
 			continue
 		}
-
-		if uninstallHelmChart(r.Group, app.Annotations) {
-			helmClient, err := r.HelmFactoryFn(framework.Spec.NamespaceName)
-			if err != nil {
-				return err
-			}
-			if err = helmClient.DeleteChart(app.Name); err != nil {
-				return err
-			}
-		}
-
-		patchedFramework := framework
-
-		patchedFramework.Status.Apps = make([]string, 0, len(patchedFramework.Status.Apps))
-		for _, name := range framework.Status.Apps {
-			if name == app.Name {
-				continue
-			}
-			patchedFramework.Status.Apps = append(patchedFramework.Status.Apps, name)
-		}
-		mergePatch := client.MergeFrom(&framework)
-		if err := r.Status().Patch(ctx, &patchedFramework, mergePatch); err != nil {
+		if err := r.removeAppFromFramework(ctx, app, framework, true); err != nil {
 			return err
 		}
-		break
 	}
 
 	controllerutil.RemoveFinalizer(app, ketchv1.KetchFinalizer)
@@ -716,7 +845,259 @@ func (r *AppReconciler) deleteChart(ctx context.Context, app *ketchv1.App) error
 }
 
 func (r *AppReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// apps affected by changes of templates and frameworks share one rate limiter,
+	// so a single change doesn't re-render all apps at once.
+	rerenderLimiter := &workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(appRerenderQPS), appRerenderBurst)}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &ketchv1.App{}, boundSecretsIndex, boundSecretNames); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&ketchv1.App{}).
+		Watches(&source.Kind{Type: &ketchv1.App{}},
+			handler.EnqueueRequestsFromMapFunc(r.appsOfSameFramework),
+			builder.WithPredicates(internalServicesChanged())).
+		// secrets are watched by metadata only, so the cache doesn't hold their data.
+		Watches(&source.Kind{Type: &v1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(r.appsBoundToSecret),
+			builder.OnlyMetadata,
+			builder.WithPredicates(r.boundSecretChanged())).
+		Watches(&source.Kind{Type: &v1.ConfigMap{}},
+			&enqueueRateLimited{toRequests: r.appsUsingTemplates, rateLimiter: rerenderLimiter},
+			builder.WithPredicates(templatesChanged(r.TemplatesNamespace))).
+		Watches(&source.Kind{Type: &ketchv1.Framework{}},
+			&enqueueRateLimited{toRequests: r.appsOfFramework, rateLimiter: rerenderLimiter},
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
 		Complete(r)
 }
+
+// enqueueRateLimited enqueues reconcile requests returned by toRequests after a delay given by rateLimiter.
+type enqueueRateLimited struct {
+	toRequests  handler.MapFunc
+	rateLimiter workqueue.RateLimiter
+}
+
+var _ handler.EventHandler = &enqueueRateLimited{}
+
+func (e *enqueueRateLimited) Create(evt event.CreateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(evt.Object, q)
+}
+
+func (e *enqueueRateLimited) Update(evt event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(evt.ObjectNew, q)
+}
+
+func (e *enqueueRateLimited) Delete(evt event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(evt.Object, q)
+}
+
+func (e *enqueueRateLimited) Generic(evt event.GenericEvent, q workqueue.RateLimitingInterface) {
+	e.enqueue(evt.Object, q)
+}
+
+func (e *enqueueRateLimited) enqueue(obj client.Object, q workqueue.RateLimitingInterface) {
+	for _, req := range e.toRequests(obj) {
+		q.AddAfter(req, e.rateLimiter.When(req))
+	}
+}
+
+// appsUsingTemplates returns reconcile requests for apps whose frameworks use the configmap with chart templates,
+// so the apps are rendered with the up-to-date templates.
+func (r *AppReconciler) appsUsingTemplates(obj client.Object) []reconcile.Request {
+	ctx := context.Background()
+	frameworks := ketchv1.FrameworkList{}
+	if err := r.List(ctx, &frameworks); err != nil {
+		return nil
+	}
+	affected := map[string]bool{}
+	for _, framework := range frameworks.Items {
+		ingressConfigMapName := templates.IngressConfigMapName(framework.Spec.IngressController.IngressType.String())
+		if obj.GetName() == ingressConfigMapName || obj.GetName() == framework.Spec.TemplatesConfigMapName {
+			affected[framework.Name] = true
+		}
+	}
+	if len(affected) == 0 {
+		return nil
+	}
+	apps := ketchv1.AppList{}
+	if err := r.List(ctx, &apps); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, app := range apps.Items {
+		if affected[app.Spec.Framework] {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: app.Name}})
+		}
+	}
+	return requests
+}
+
+// appsOfFramework returns reconcile requests for apps of the framework,
+// so the apps are rendered with the up-to-date framework's settings.
+func (r *AppReconciler) appsOfFramework(obj client.Object) []reconcile.Request {
+	apps := ketchv1.AppList{}
+	if err := r.List(context.Background(), &apps); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, app := range apps.Items {
+		if app.Spec.Framework == obj.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: app.Name}})
+		}
+	}
+	return requests
+}
+
+// appsBoundToSecret returns reconcile requests for apps that have the secret bound,
+// so their processes are restarted with the new credentials.
+func (r *AppReconciler) appsBoundToSecret(obj client.Object) []reconcile.Request {
+	ctx := context.Background()
+	apps := ketchv1.AppList{}
+	if err := r.List(ctx, &apps, client.MatchingFields{boundSecretsIndex: obj.GetName()}); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	namespaces := map[string]string{}
+	for _, app := range apps.Items {
+		for _, binding := range app.Spec.ServiceBindings {
+			if binding.SecretName != obj.GetName() {
+				continue
+			}
+			namespace, ok := namespaces[app.Spec.Framework]
+			if !ok {
+				framework := ketchv1.Framework{}
+				if err := r.Get(ctx, types.NamespacedName{Name: app.Spec.Framework}, &framework); err != nil {
+					continue
+				}
+				namespace = framework.Spec.NamespaceName
+				namespaces[app.Spec.Framework] = namespace
+			}
+			if namespace == obj.GetNamespace() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: app.Name}})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+// boundSecretChanged filters events of secrets bound to apps.
+// Data of secrets isn't cached, so updates are passed if the resource version of the secret changed.
+func (r *AppReconciler) boundSecretChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return r.isBoundSecret(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld.GetResourceVersion() == e.ObjectNew.GetResourceVersion() {
+				return false
+			}
+			return r.isBoundSecret(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return r.isBoundSecret(e.Object)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// isBoundSecret returns true if the secret is in a framework's namespace and an app of the framework has the secret bound.
+func (r *AppReconciler) isBoundSecret(obj client.Object) bool {
+	return len(r.appsBoundToSecret(obj)) > 0
+}
+
+// boundSecretNames returns names of secrets bound to the app, it indexes apps by boundSecretsIndex.
+func boundSecretNames(obj client.Object) []string {
+	app, ok := obj.(*ketchv1.App)
+	if !ok {
+		return nil
+	}
+	names := make([]string, 0, len(app.Spec.ServiceBindings))
+	for _, binding := range app.Spec.ServiceBindings {
+		names = append(names, binding.SecretName)
+	}
+	return names
+}
+
+// appsOfSameFramework returns reconcile requests for other apps of the app's framework,
+// so they get the up-to-date URLs of the app's internal processes.
+func (r *AppReconciler) appsOfSameFramework(obj client.Object) []reconcile.Request {
+	app, ok := obj.(*ketchv1.App)
+	if !ok {
+		return nil
+	}
+	framework := ketchv1.Framework{}
+	if err := r.Get(context.Background(), types.NamespacedName{Name: app.Spec.Framework}, &framework); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, name := range framework.Status.Apps {
+		if name == app.Name {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+	}
+	return requests
+}
+
+// templatesChanged filters events of configmaps in the templates namespace that change chart templates.
+func templatesChanged(namespace string) predicate.Predicate {
+	inKetchNamespace := func(obj client.Object) bool {
+		return obj.GetNamespace() == namespace
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return inKetchNamespace(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldConfigMap, okOld := e.ObjectOld.(*v1.ConfigMap)
+			newConfigMap, okNew := e.ObjectNew.(*v1.ConfigMap)
+			if !okOld || !okNew || !inKetchNamespace(newConfigMap) {
+				return false
+			}
+			return !reflect.DeepEqual(oldConfigMap.Data, newConfigMap.Data)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return inKetchNamespace(e.Object)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// internalServicesChanged filters app events that change published internal services.
+func internalServicesChanged() predicate.Predicate {
+	hasPublished := func(obj client.Object) bool {
+		app, ok := obj.(*ketchv1.App)
+		if !ok {
+			return false
+		}
+		for _, service := range app.Status.InternalServices {
+			if service.Published {
+				return true
+			}
+		}
+		return false
+	}
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldApp, okOld := e.ObjectOld.(*ketchv1.App)
+			newApp, okNew := e.ObjectNew.(*ketchv1.App)
+			if !okOld || !okNew {
+				return false
+			}
+			return !reflect.DeepEqual(oldApp.Status.InternalServices, newApp.Status.InternalServices)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return hasPublished(e.Object)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
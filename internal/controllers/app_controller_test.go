@@ -25,6 +25,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlFake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 	"github.com/theketchio/ketch/internal/chart"
@@ -727,3 +729,279 @@ func Test_appReconcileResult_isConflictError(t *testing.T) {
 		})
 	}
 }
+
+func TestAppReconciler_appsBoundToSecret(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+	}
+	aws := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "aws"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-aws"},
+	}
+	newApp := func(name, framework string, bindings ...ketchv1.ServiceBinding) *ketchv1.App {
+		return &ketchv1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       ketchv1.AppSpec{Framework: framework, ServiceBindings: bindings},
+		}
+	}
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme()(scheme))
+	cli := ctrlFake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(gke, aws,
+		newApp("app-1", "gke", ketchv1.ServiceBinding{Name: "db", SecretName: "postgres"}),
+		newApp("app-2", "aws", ketchv1.ServiceBinding{Name: "db", SecretName: "postgres"}),
+		newApp("app-3", "gke", ketchv1.ServiceBinding{Name: "cache", SecretName: "redis"}),
+		newApp("app-4", "gke"),
+	).Build()
+	r := AppReconciler{Client: cli}
+
+	secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "ketch-gke"}}
+	requests := r.appsBoundToSecret(secret)
+	require.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "app-1"}}}, requests)
+
+	require.True(t, r.isBoundSecret(secret))
+	require.False(t, r.isBoundSecret(&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: "default"}}))
+	require.False(t, r.isBoundSecret(&v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "sh.helm.release.v1.app-1.v1", Namespace: "ketch-gke"}}))
+
+	require.Equal(t, []string{"postgres"}, boundSecretNames(newApp("app-1", "gke", ketchv1.ServiceBinding{Name: "db", SecretName: "postgres"})))
+
+	newMetadata := func(namespace, resourceVersion string) *metav1.PartialObjectMetadata {
+		return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: "postgres", Namespace: namespace, ResourceVersion: resourceVersion}}
+	}
+	p := r.boundSecretChanged()
+	require.True(t, p.Update(event.UpdateEvent{ObjectOld: newMetadata("ketch-gke", "1"), ObjectNew: newMetadata("ketch-gke", "2")}))
+	require.False(t, p.Update(event.UpdateEvent{ObjectOld: newMetadata("ketch-gke", "2"), ObjectNew: newMetadata("ketch-gke", "2")}))
+	require.False(t, p.Update(event.UpdateEvent{ObjectOld: newMetadata("default", "1"), ObjectNew: newMetadata("default", "2")}))
+}
+
+func TestAppReconciler_removeFromPreviousFrameworks(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+		Status:     ketchv1.FrameworkStatus{Apps: []string{"app", "another-app"}},
+	}
+	aws := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "aws"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-aws"},
+		Status:     ketchv1.FrameworkStatus{Apps: []string{"app"}},
+	}
+	app := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "app"},
+		Spec: ketchv1.AppSpec{
+			Framework: "aws",
+			Deployments: []ketchv1.AppDeploymentSpec{
+				{Version: 2, Processes: []ketchv1.ProcessSpec{{Name: "web", Units: conversions.IntPtr(2)}}},
+			},
+		},
+	}
+	replicas := int32(2)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "app-web-2", Namespace: "ketch-aws"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{UpdatedReplicas: 2, ReadyReplicas: 1},
+	}
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme()(scheme))
+	cli := ctrlFake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(gke, aws, app, deployment).Build()
+	helmMock := &helm{}
+	r := AppReconciler{
+		Client:   cli,
+		Recorder: record.NewFakeRecorder(10),
+		HelmFactoryFn: func(namespace string) (Helm, error) {
+			require.Equal(t, "ketch-gke", namespace)
+			return helmMock, nil
+		},
+	}
+
+	moving, err := r.removeFromPreviousFrameworks(context.Background(), app, *aws)
+	require.Nil(t, err)
+	require.True(t, moving)
+	require.Nil(t, helmMock.deleteChartCalled)
+
+	deployment.Status.ReadyReplicas = 2
+	require.Nil(t, cli.Update(context.Background(), deployment))
+
+	moving, err = r.removeFromPreviousFrameworks(context.Background(), app, *aws)
+	require.Nil(t, err)
+	require.False(t, moving)
+	require.Equal(t, []string{"app"}, helmMock.deleteChartCalled)
+
+	framework := ketchv1.Framework{}
+	require.Nil(t, cli.Get(context.Background(), types.NamespacedName{Name: "gke"}, &framework))
+	require.Equal(t, []string{"another-app"}, framework.Status.Apps)
+	require.Nil(t, cli.Get(context.Background(), types.NamespacedName{Name: "aws"}, &framework))
+	require.Equal(t, []string{"app"}, framework.Status.Apps)
+}
+
+func TestAppReconciler_removeFromPreviousFrameworksSharingNamespace(t *testing.T) {
+	staging := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-shared"},
+		Status:     ketchv1.FrameworkStatus{Apps: []string{"app"}},
+	}
+	production := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "production"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-shared"},
+		Status:     ketchv1.FrameworkStatus{Apps: []string{"app"}},
+	}
+	app := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "app"},
+		Spec:       ketchv1.AppSpec{Framework: "production"},
+	}
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme()(scheme))
+	cli := ctrlFake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(staging, production, app).Build()
+	helmMock := &helm{}
+	r := AppReconciler{
+		Client:   cli,
+		Recorder: record.NewFakeRecorder(10),
+		HelmFactoryFn: func(namespace string) (Helm, error) {
+			return helmMock, nil
+		},
+	}
+
+	moving, err := r.removeFromPreviousFrameworks(context.Background(), app, *production)
+	require.Nil(t, err)
+	require.False(t, moving)
+	// the release in the shared namespace is the one the app runs now
+	require.Nil(t, helmMock.deleteChartCalled)
+
+	framework := ketchv1.Framework{}
+	require.Nil(t, cli.Get(context.Background(), types.NamespacedName{Name: "staging"}, &framework))
+	require.Empty(t, framework.Status.Apps)
+	require.Nil(t, cli.Get(context.Background(), types.NamespacedName{Name: "production"}, &framework))
+	require.Equal(t, []string{"app"}, framework.Status.Apps)
+}
+
+func TestAppReconciler_frameworkTemplates(t *testing.T) {
+	defaults := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: templates.IngressConfigMapName("traefik"), Namespace: templates.KetchNamespace},
+		Data:       map[string]string{"deployment.yaml": "deployment", "ingress.yaml": "ingress"},
+	}
+	overrides := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a-templates", Namespace: templates.KetchNamespace},
+		Data:       map[string]string{"deployment.yaml": "team-a deployment"},
+	}
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	cli := ctrlFake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(defaults, overrides).Build()
+	r := AppReconciler{TemplateReader: templates.NewStorage(cli, templates.KetchNamespace)}
+
+	framework := ketchv1.Framework{
+		Spec: ketchv1.FrameworkSpec{
+			IngressController: ketchv1.IngressControllerSpec{IngressType: ketchv1.TraefikIngressControllerType},
+		},
+	}
+	tpls, err := r.frameworkTemplates(framework)
+	require.Nil(t, err)
+	require.Equal(t, defaults.Data, tpls.Yamls)
+
+	framework.Spec.TemplatesConfigMapName = "team-a-templates"
+	tpls, err = r.frameworkTemplates(framework)
+	require.Nil(t, err)
+	require.Equal(t, map[string]string{"deployment.yaml": "team-a deployment", "ingress.yaml": "ingress"}, tpls.Yamls)
+
+	framework.Spec.TemplatesConfigMapName = "team-b-templates"
+	_, err = r.frameworkTemplates(framework)
+	require.NotNil(t, err)
+}
+
+func TestAppReconciler_appsUsingTemplates(t *testing.T) {
+	gke := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec: ketchv1.FrameworkSpec{
+			IngressController: ketchv1.IngressControllerSpec{IngressType: ketchv1.TraefikIngressControllerType},
+		},
+	}
+	aws := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "aws"},
+		Spec: ketchv1.FrameworkSpec{
+			IngressController:      ketchv1.IngressControllerSpec{IngressType: ketchv1.IstioIngressControllerType},
+			TemplatesConfigMapName: "aws-templates",
+		},
+	}
+	newApp := func(name, framework string) *ketchv1.App {
+		return &ketchv1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       ketchv1.AppSpec{Framework: framework},
+		}
+	}
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme()(scheme))
+	cli := ctrlFake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(gke, aws,
+		newApp("app-1", "gke"),
+		newApp("app-2", "aws"),
+	).Build()
+	r := AppReconciler{Client: cli}
+
+	tests := []struct {
+		configMapName string
+		want          []reconcile.Request
+	}{
+		{
+			configMapName: templates.IngressConfigMapName("traefik"),
+			want:          []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "app-1"}}},
+		},
+		{
+			configMapName: templates.IngressConfigMapName("istio"),
+			want:          []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "app-2"}}},
+		},
+		{
+			configMapName: "aws-templates",
+			want:          []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "app-2"}}},
+		},
+		{
+			configMapName: templates.IngressConfigMapName("nginx"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.configMapName, func(t *testing.T) {
+			configMap := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: tt.configMapName, Namespace: templates.KetchNamespace}}
+			require.Equal(t, tt.want, r.appsUsingTemplates(configMap))
+		})
+	}
+}
+
+func TestAppReconciler_appsOfFramework(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.Nil(t, ketchv1.AddToScheme()(scheme))
+	cli := ctrlFake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(
+		&ketchv1.App{ObjectMeta: metav1.ObjectMeta{Name: "app-1"}, Spec: ketchv1.AppSpec{Framework: "gke"}},
+		&ketchv1.App{ObjectMeta: metav1.ObjectMeta{Name: "app-2"}, Spec: ketchv1.AppSpec{Framework: "aws"}},
+	).Build()
+	r := AppReconciler{Client: cli}
+
+	requests := r.appsOfFramework(&ketchv1.Framework{ObjectMeta: metav1.ObjectMeta{Name: "gke"}})
+	require.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "app-1"}}}, requests)
+}
+
+func Test_templatesChanged(t *testing.T) {
+	newConfigMap := func(namespace string, data map[string]string) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "ingress-traefik-templates", Namespace: namespace},
+			Data:       data,
+		}
+	}
+	p := templatesChanged(templates.KetchNamespace)
+	require.True(t, p.Create(event.CreateEvent{Object: newConfigMap(templates.KetchNamespace, nil)}))
+	require.False(t, p.Create(event.CreateEvent{Object: newConfigMap("default", nil)}))
+	require.True(t, templatesChanged("ketch").Create(event.CreateEvent{Object: newConfigMap("ketch", nil)}))
+	require.False(t, templatesChanged("ketch").Create(event.CreateEvent{Object: newConfigMap(templates.KetchNamespace, nil)}))
+	require.True(t, p.Update(event.UpdateEvent{
+		ObjectOld: newConfigMap(templates.KetchNamespace, map[string]string{"deployment.yaml": "old"}),
+		ObjectNew: newConfigMap(templates.KetchNamespace, map[string]string{"deployment.yaml": "new"}),
+	}))
+	require.False(t, p.Update(event.UpdateEvent{
+		ObjectOld: newConfigMap(templates.KetchNamespace, map[string]string{"deployment.yaml": "same"}),
+		ObjectNew: newConfigMap(templates.KetchNamespace, map[string]string{"deployment.yaml": "same"}),
+	}))
+	require.False(t, p.Update(event.UpdateEvent{
+		ObjectOld: newConfigMap("default", map[string]string{"deployment.yaml": "old"}),
+		ObjectNew: newConfigMap("default", map[string]string{"deployment.yaml": "new"}),
+	}))
+	require.True(t, p.Delete(event.DeleteEvent{Object: newConfigMap(templates.KetchNamespace, nil)}))
+}
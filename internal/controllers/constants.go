@@ -1,9 +1,32 @@
 package controllers
 
-import "time"
+import (
+	"time"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
 
 const (
-	KetchNamespace = "ketch-system"
 	// reconcileTimeout is the default timeout to trigger Operator reconcile
 	reconcileTimeout = 10 * time.Minute
+	// appMoveRequeueInterval is how often the Operator checks if a moved app is ready in its new framework
+	appMoveRequeueInterval = 10 * time.Second
+	// appRerenderQPS is how many apps per second the Operator re-renders after their templates or framework change
+	appRerenderQPS = 5
+	// appRerenderBurst is how many apps the Operator re-renders at once after their templates or framework change
+	appRerenderBurst = 20
+	// boundSecretsIndex is a field index of apps by names of their bound secrets
+	boundSecretsIndex = "spec.serviceBindings.secretName"
+	// frameworkIndex is a field index of apps and jobs by name of their framework
+	frameworkIndex = "spec.framework"
 )
+
+// managedNamespaceLabelsAnnotation returns an annotation listing namespace labels set from a framework's spec
+func managedNamespaceLabelsAnnotation() string {
+	return ketchv1.Group + "/managed-labels"
+}
+
+// managedNamespaceAnnotationsAnnotation returns an annotation listing namespace annotations set from a framework's spec
+func managedNamespaceAnnotationsAnnotation() string {
+	return ketchv1.Group + "/managed-annotations"
+}
@@ -19,17 +19,31 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/reference"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 )
@@ -43,6 +57,13 @@ type FrameworkReconciler struct {
 
 // +kubebuilder:rbac:groups=theketch.io,resources=frameworks,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=theketch.io,resources=frameworks/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=limitranges,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=bind,resourceNames=admin;edit;view
+// +kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch
 
 func (r *FrameworkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	_ = r.Log.WithValues("framework", req.NamespacedName)
@@ -52,11 +73,30 @@ func (r *FrameworkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	if !framework.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, &framework)
+	}
+
+	if !controllerutil.ContainsFinalizer(&framework, ketchv1.KetchFinalizer) {
+		controllerutil.AddFinalizer(&framework, ketchv1.KetchFinalizer)
+		if err := r.Update(ctx, &framework); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	status := r.reconcile(ctx, &framework)
+	health, err := r.frameworkHealth(ctx, &framework)
+	if err != nil {
+		r.Log.Error(err, "failed to get framework health", "framework", framework.Name)
+		health = framework.Status.Health
+	}
+	status.Health = health
 	framework.Status = status
 
-	err := r.Status().Update(ctx, &framework)
-	return ctrl.Result{}, err
+	if err := r.Status().Update(ctx, &framework); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
 }
 
 func (r *FrameworkReconciler) reconcile(ctx context.Context, framework *ketchv1.Framework) ketchv1.FrameworkStatus {
@@ -113,9 +153,7 @@ func (r *FrameworkReconciler) reconcile(ctx context.Context, framework *ketchv1.
 		}
 	}
 
-	if namespace.Labels == nil {
-		namespace.Labels = map[string]string{}
-	}
+	setNamespaceMetadata(&namespace, framework.Spec)
 
 	// we rely on istio automatic sidecar injection
 	// https://istio.io/latest/docs/setup/additional-setup/sidecar-injection/#automatic-sidecar-injection
@@ -123,7 +161,9 @@ func (r *FrameworkReconciler) reconcile(ctx context.Context, framework *ketchv1.
 	if framework.Spec.IngressController.IngressType == ketchv1.IstioIngressControllerType {
 		istioInjectionValue = "enabled"
 	}
-	namespace.Labels["istio-injection"] = istioInjectionValue
+	if _, ok := framework.Spec.NamespaceLabels["istio-injection"]; !ok {
+		namespace.Labels["istio-injection"] = istioInjectionValue
+	}
 
 	err = r.Update(ctx, &namespace)
 	if err != nil {
@@ -161,6 +201,42 @@ func (r *FrameworkReconciler) reconcile(ctx context.Context, framework *ketchv1.
 			}
 		}
 	}
+	if err := r.reconcileResourceQuota(ctx, framework); err != nil {
+		return ketchv1.FrameworkStatus{
+			Phase:     ketchv1.FrameworkFailed,
+			Message:   fmt.Sprintf("failed to reconcile resource quota: %v", err),
+			Apps:      framework.Status.Apps,
+			Jobs:      framework.Status.Jobs,
+			Namespace: framework.Status.Namespace,
+		}
+	}
+	if err := r.reconcileLimitRange(ctx, framework); err != nil {
+		return ketchv1.FrameworkStatus{
+			Phase:     ketchv1.FrameworkFailed,
+			Message:   fmt.Sprintf("failed to reconcile limit range: %v", err),
+			Apps:      framework.Status.Apps,
+			Jobs:      framework.Status.Jobs,
+			Namespace: framework.Status.Namespace,
+		}
+	}
+	if err := r.reconcileNetworkPolicy(ctx, framework, frameworks.Items); err != nil {
+		return ketchv1.FrameworkStatus{
+			Phase:     ketchv1.FrameworkFailed,
+			Message:   fmt.Sprintf("failed to reconcile network policy: %v", err),
+			Apps:      framework.Status.Apps,
+			Jobs:      framework.Status.Jobs,
+			Namespace: framework.Status.Namespace,
+		}
+	}
+	if err := r.reconcileRoleBindings(ctx, framework); err != nil {
+		return ketchv1.FrameworkStatus{
+			Phase:     ketchv1.FrameworkFailed,
+			Message:   fmt.Sprintf("failed to reconcile role bindings: %v", err),
+			Apps:      framework.Status.Apps,
+			Jobs:      framework.Status.Jobs,
+			Namespace: framework.Status.Namespace,
+		}
+	}
 	return ketchv1.FrameworkStatus{
 		Namespace: ref,
 		Phase:     ketchv1.FrameworkCreated,
@@ -169,8 +245,437 @@ func (r *FrameworkReconciler) reconcile(ctx context.Context, framework *ketchv1.
 	}
 }
 
+// frameworkHealth summarizes the phase and readiness of the framework's apps, the number of failed jobs
+// and references the framework's resource quota.
+func (r *FrameworkReconciler) frameworkHealth(ctx context.Context, framework *ketchv1.Framework) (*ketchv1.FrameworkHealth, error) {
+	apps := ketchv1.AppList{}
+	if err := r.List(ctx, &apps, client.MatchingFields{frameworkIndex: framework.Name}); err != nil {
+		return nil, err
+	}
+	jobs := ketchv1.JobList{}
+	if err := r.List(ctx, &jobs, client.MatchingFields{frameworkIndex: framework.Name}); err != nil {
+		return nil, err
+	}
+	health := ketchv1.FrameworkHealth{}
+	for i := range apps.Items {
+		app := &apps.Items[i]
+		if app.Spec.Framework != framework.Name {
+			continue
+		}
+		appHealth := ketchv1.FrameworkAppHealth{Name: app.Name, Phase: app.Phase()}
+		if appHealth.Phase == ketchv1.AppError {
+			for _, c := range app.Status.Conditions {
+				if c.Status == v1.ConditionFalse {
+					appHealth.Message = c.Message
+					break
+				}
+			}
+		} else {
+			ready, err := appReady(ctx, r.Client, app, framework.Spec.NamespaceName)
+			if err != nil {
+				return nil, err
+			}
+			appHealth.Ready = ready
+			if !ready {
+				appHealth.Message = "waiting for deployments to be ready"
+			}
+		}
+		if appHealth.Ready {
+			health.ReadyApps++
+		}
+		health.Apps = append(health.Apps, appHealth)
+	}
+	sort.Slice(health.Apps, func(i, j int) bool { return health.Apps[i].Name < health.Apps[j].Name })
+
+	for _, job := range jobs.Items {
+		if job.Spec.Framework != framework.Name {
+			continue
+		}
+		if c := job.Status.Condition(ketchv1.Scheduled); c != nil && c.Status == v1.ConditionFalse {
+			health.FailedJobs++
+			continue
+		}
+		var batchJob batchv1.Job
+		err := r.Get(ctx, types.NamespacedName{Namespace: framework.Spec.NamespaceName, Name: job.Name}, &batchJob)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range batchJob.Status.Conditions {
+			if c.Type == batchv1.JobFailed && c.Status == v1.ConditionTrue {
+				health.FailedJobs++
+				break
+			}
+		}
+	}
+
+	var quota v1.ResourceQuota
+	err := r.Get(ctx, types.NamespacedName{Namespace: framework.Spec.NamespaceName, Name: ketchv1.FrameworkResourceQuotaName}, &quota)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		ref, err := reference.GetReference(r.Scheme, &quota)
+		if err != nil {
+			return nil, err
+		}
+		health.ResourceQuota = ref
+	}
+	return &health, nil
+}
+
+// finalize removes the ketch finalizer once the framework doesn't own any apps or jobs.
+// Until then, the framework is kept so that its workloads can be uninstalled or moved to another framework.
+func (r *FrameworkReconciler) finalize(ctx context.Context, framework *ketchv1.Framework) error {
+	if !controllerutil.ContainsFinalizer(framework, ketchv1.KetchFinalizer) {
+		return nil
+	}
+	apps := ketchv1.AppList{}
+	if err := r.List(ctx, &apps); err != nil {
+		return err
+	}
+	jobs := ketchv1.JobList{}
+	if err := r.List(ctx, &jobs); err != nil {
+		return err
+	}
+	var appCount, jobCount int
+	for _, app := range apps.Items {
+		if framework.OwnsApp(app) {
+			appCount++
+		}
+	}
+	for _, job := range jobs.Items {
+		if framework.OwnsJob(job) {
+			jobCount++
+		}
+	}
+	if appCount > 0 || jobCount > 0 {
+		framework.Status.Phase = ketchv1.FrameworkTerminating
+		framework.Status.Message = fmt.Sprintf("waiting for %d apps and %d jobs to be removed or moved to another framework", appCount, jobCount)
+		return r.Status().Update(ctx, framework)
+	}
+	controllerutil.RemoveFinalizer(framework, ketchv1.KetchFinalizer)
+	return r.Update(ctx, framework)
+}
+
+// frameworkOfWorkload returns a reconcile request for the framework of a deleted app or job,
+// so that a terminating framework can be removed once it has no workloads.
+func (r *FrameworkReconciler) frameworkOfWorkload(obj client.Object) []reconcile.Request {
+	var name string
+	switch workload := obj.(type) {
+	case *ketchv1.App:
+		name = workload.Spec.Framework
+	case *ketchv1.Job:
+		name = workload.Spec.Framework
+	}
+	if len(name) == 0 {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name}}}
+}
+
+// frameworkOfWorkloadResource returns a reconcile request for the framework of the app or job
+// that a deployment or a batch job belongs to, so that the framework's health is refreshed
+// when the workload's pods become ready or fail.
+func (r *FrameworkReconciler) frameworkOfWorkloadResource(obj client.Object) []reconcile.Request {
+	ctx := context.Background()
+	labels := obj.GetLabels()
+	if name, ok := labels[ketchv1.Group+"/app-name"]; ok {
+		app := ketchv1.App{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, &app); err != nil {
+			return nil
+		}
+		return r.frameworkOfWorkload(&app)
+	}
+	if name, ok := labels[ketchv1.Group+"/job-name"]; ok {
+		job := ketchv1.Job{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, &job); err != nil {
+			return nil
+		}
+		return r.frameworkOfWorkload(&job)
+	}
+	return nil
+}
+
+// workloadFramework is an index function returning the framework of an app or a job.
+func workloadFramework(obj client.Object) []string {
+	switch workload := obj.(type) {
+	case *ketchv1.App:
+		return []string{workload.Spec.Framework}
+	case *ketchv1.Job:
+		return []string{workload.Spec.Framework}
+	}
+	return nil
+}
+
+// workloadResourceStatusChanged is a predicate that filters deployment and batch job events
+// to deletions and status updates.
+func workloadResourceStatusChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			switch oldObj := e.ObjectOld.(type) {
+			case *appsv1.Deployment:
+				newObj, ok := e.ObjectNew.(*appsv1.Deployment)
+				return ok && !reflect.DeepEqual(oldObj.Status, newObj.Status)
+			case *batchv1.Job:
+				newObj, ok := e.ObjectNew.(*batchv1.Job)
+				return ok && !reflect.DeepEqual(oldObj.Status.Conditions, newObj.Status.Conditions)
+			}
+			return false
+		},
+		DeleteFunc:  func(event.DeleteEvent) bool { return true },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
+// workloadChanged is a predicate that filters app and job events to deletions and updates
+// that may change the framework's health: a new generation or new conditions.
+func workloadChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() {
+				return true
+			}
+			return !reflect.DeepEqual(workloadConditions(e.ObjectOld), workloadConditions(e.ObjectNew))
+		},
+		DeleteFunc:  func(event.DeleteEvent) bool { return true },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
+func workloadConditions(obj client.Object) []ketchv1.Condition {
+	switch workload := obj.(type) {
+	case *ketchv1.App:
+		return workload.Status.Conditions
+	case *ketchv1.Job:
+		return workload.Status.Conditions
+	}
+	return nil
+}
+
+// setNamespaceMetadata sets labels and annotations from the framework's spec on the namespace.
+// Keys set by ketch are recorded in the namespace's annotations, so labels and annotations removed from the spec
+// are removed from the namespace while those added by other tools are left untouched.
+func setNamespaceMetadata(namespace *v1.Namespace, spec ketchv1.FrameworkSpec) {
+	if namespace.Labels == nil {
+		namespace.Labels = map[string]string{}
+	}
+	if namespace.Annotations == nil {
+		namespace.Annotations = map[string]string{}
+	}
+	labelsKey, annotationsKey := managedNamespaceLabelsAnnotation(), managedNamespaceAnnotationsAnnotation()
+	previousLabels := namespace.Annotations[labelsKey]
+	previousAnnotations := namespace.Annotations[annotationsKey]
+	namespace.Annotations[labelsKey] = syncManagedKeys(namespace.Labels, spec.NamespaceLabels, previousLabels)
+	namespace.Annotations[annotationsKey] = syncManagedKeys(namespace.Annotations, spec.NamespaceAnnotations, previousAnnotations)
+	for _, key := range []string{labelsKey, annotationsKey} {
+		if len(namespace.Annotations[key]) == 0 {
+			delete(namespace.Annotations, key)
+		}
+	}
+}
+
+// syncManagedKeys removes keys listed in managed but missing in desired from current, copies desired to current
+// and returns a new comma-separated list of managed keys.
+func syncManagedKeys(current, desired map[string]string, managed string) string {
+	for _, key := range strings.Split(managed, ",") {
+		if _, ok := desired[key]; !ok && len(key) > 0 {
+			delete(current, key)
+		}
+	}
+	keys := make([]string, 0, len(desired))
+	for key, value := range desired {
+		current[key] = value
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// reconcileResourceQuota creates or updates a ResourceQuota in the framework's namespace.
+// The ResourceQuota is removed when the framework doesn't define any quota.
+func (r *FrameworkReconciler) reconcileResourceQuota(ctx context.Context, framework *ketchv1.Framework) error {
+	quota := v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ketchv1.FrameworkResourceQuotaName,
+			Namespace: framework.Spec.NamespaceName,
+		},
+	}
+	if framework.Spec.ResourceQuota == nil || framework.Spec.ResourceQuota.IsEmpty() {
+		return client.IgnoreNotFound(r.Delete(ctx, &quota))
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, &quota, func() error {
+		quota.Spec.Hard = framework.Spec.ResourceQuota.Hard()
+		return controllerutil.SetControllerReference(framework, &quota, r.Scheme)
+	})
+	return err
+}
+
+// reconcileLimitRange creates or updates a LimitRange in the framework's namespace.
+// The LimitRange is removed when the framework doesn't define any defaults.
+func (r *FrameworkReconciler) reconcileLimitRange(ctx context.Context, framework *ketchv1.Framework) error {
+	limitRange := v1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ketchv1.FrameworkLimitRangeName,
+			Namespace: framework.Spec.NamespaceName,
+		},
+	}
+	if framework.Spec.LimitRange == nil || framework.Spec.LimitRange.IsEmpty() {
+		return client.IgnoreNotFound(r.Delete(ctx, &limitRange))
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, &limitRange, func() error {
+		limitRange.Spec.Limits = []v1.LimitRangeItem{framework.Spec.LimitRange.LimitRangeItem()}
+		return controllerutil.SetControllerReference(framework, &limitRange, r.Scheme)
+	})
+	return err
+}
+
+// reconcileNetworkPolicy creates or updates a NetworkPolicy in the framework's namespace.
+// The NetworkPolicy is removed when the framework is open.
+func (r *FrameworkReconciler) reconcileNetworkPolicy(ctx context.Context, framework *ketchv1.Framework, frameworks []ketchv1.Framework) error {
+	policy := networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ketchv1.FrameworkNetworkPolicyName,
+			Namespace: framework.Spec.NamespaceName,
+		},
+	}
+	if framework.Spec.NetworkPolicy.IsOpen() {
+		return client.IgnoreNotFound(r.Delete(ctx, &policy))
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, &policy, func() error {
+		policy.Spec = networkPolicySpec(framework, frameworks)
+		return controllerutil.SetControllerReference(framework, &policy, r.Scheme)
+	})
+	return err
+}
+
+// networkPolicySpec returns a spec that allows incoming traffic only from the ingress controller's namespace,
+// namespaces of allowed frameworks and, in allow-same-framework mode, from the framework's own namespace.
+func networkPolicySpec(framework *ketchv1.Framework, frameworks []ketchv1.Framework) networkingv1.NetworkPolicySpec {
+	namespacePeer := func(namespace string) networkingv1.NetworkPolicyPeer {
+		return networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{v1.LabelMetadataName: namespace},
+			},
+		}
+	}
+	var peers []networkingv1.NetworkPolicyPeer
+	if ingressNamespace := framework.Spec.IngressController.IngressNamespace(); len(ingressNamespace) > 0 {
+		peers = append(peers, namespacePeer(ingressNamespace))
+	}
+	if framework.Spec.NetworkPolicy.Mode == ketchv1.NetworkPolicyModeAllowSameFramework {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{}})
+	}
+	for _, f := range frameworks {
+		if f.Name == framework.Name || !framework.Spec.NetworkPolicy.Allows(f.Name) {
+			continue
+		}
+		peers = append(peers, namespacePeer(f.Spec.NamespaceName))
+	}
+	return networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		Ingress:     []networkingv1.NetworkPolicyIngressRule{{From: peers}},
+	}
+}
+
+// reconcileRoleBindings maintains a RoleBinding per framework role granting members access to the framework's namespace.
+// A RoleBinding is removed when no member has its role.
+func (r *FrameworkReconciler) reconcileRoleBindings(ctx context.Context, framework *ketchv1.Framework) error {
+	for _, role := range ketchv1.FrameworkRoles {
+		roleBinding := rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      role.RoleBindingName(),
+				Namespace: framework.Spec.NamespaceName,
+			},
+		}
+		var subjects []rbacv1.Subject
+		for _, member := range framework.Spec.Members {
+			if member.Role != role {
+				continue
+			}
+			subjects = append(subjects, rbacv1.Subject{
+				APIGroup: rbacv1.GroupName,
+				Kind:     string(member.Kind),
+				Name:     member.Name,
+			})
+		}
+		if len(subjects) == 0 {
+			if err := client.IgnoreNotFound(r.Delete(ctx, &roleBinding)); err != nil {
+				return err
+			}
+			continue
+		}
+		_, err := controllerutil.CreateOrUpdate(ctx, r.Client, &roleBinding, func() error {
+			roleBinding.Subjects = subjects
+			roleBinding.RoleRef = rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     role.ClusterRole(),
+			}
+			return controllerutil.SetControllerReference(framework, &roleBinding, r.Scheme)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// frameworksAllowing returns reconcile requests for frameworks that allow traffic from the given framework.
+func (r *FrameworkReconciler) frameworksAllowing(obj client.Object) []reconcile.Request {
+	frameworks := ketchv1.FrameworkList{}
+	if err := r.List(context.Background(), &frameworks); err != nil {
+		r.Log.Error(err, "failed to get a list of frameworks")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, framework := range frameworks.Items {
+		if framework.Spec.NetworkPolicy.Allows(obj.GetName()) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: framework.Name}})
+		}
+	}
+	return requests
+}
+
+// frameworkNamespaceChanged filters framework events to those that change the framework's namespace.
+func frameworkNamespaceChanged() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldFramework, ok := e.ObjectOld.(*ketchv1.Framework)
+			if !ok {
+				return false
+			}
+			newFramework, ok := e.ObjectNew.(*ketchv1.Framework)
+			if !ok {
+				return false
+			}
+			return oldFramework.Spec.NamespaceName != newFramework.Spec.NamespaceName
+		},
+	}
+}
+
 func (r *FrameworkReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	for _, obj := range []client.Object{&ketchv1.App{}, &ketchv1.Job{}} {
+		if err := mgr.GetFieldIndexer().IndexField(context.Background(), obj, frameworkIndex, workloadFramework); err != nil {
+			return err
+		}
+	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&ketchv1.Framework{}).
+		Owns(&v1.ResourceQuota{}).
+		Owns(&v1.LimitRange{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Owns(&rbacv1.RoleBinding{}).
+		Watches(&source.Kind{Type: &ketchv1.Framework{}}, handler.EnqueueRequestsFromMapFunc(r.frameworksAllowing), builder.WithPredicates(frameworkNamespaceChanged())).
+		Watches(&source.Kind{Type: &ketchv1.App{}}, handler.EnqueueRequestsFromMapFunc(r.frameworkOfWorkload), builder.WithPredicates(workloadChanged())).
+		Watches(&source.Kind{Type: &ketchv1.Job{}}, handler.EnqueueRequestsFromMapFunc(r.frameworkOfWorkload), builder.WithPredicates(workloadChanged())).
+		// the manager's cache holds only deployments and jobs labeled with names of ketch apps and jobs.
+		Watches(&source.Kind{Type: &appsv1.Deployment{}}, handler.EnqueueRequestsFromMapFunc(r.frameworkOfWorkloadResource), builder.WithPredicates(workloadResourceStatusChanged())).
+		Watches(&source.Kind{Type: &batchv1.Job{}}, handler.EnqueueRequestsFromMapFunc(r.frameworkOfWorkloadResource), builder.WithPredicates(workloadResourceStatusChanged())).
 		Complete(r)
 }
@@ -6,10 +6,21 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlFake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 	"github.com/theketchio/ketch/internal/utils/conversions"
@@ -226,3 +237,337 @@ func TestFrameworkReconciler_ReconcileUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestFrameworkReconciler_reconcileResourceQuota(t *testing.T) {
+	cpu := resource.MustParse("2")
+	cpuRequest := resource.MustParse("100m")
+	framework := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", UID: "team-a-uid"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-team-a",
+			ResourceQuota: &ketchv1.FrameworkResourceQuotaSpec{CPU: &cpu, Pods: conversions.Int64Ptr(10)},
+			LimitRange:    &ketchv1.FrameworkLimitRangeSpec{DefaultCPURequest: &cpuRequest},
+		},
+	}
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme()(scheme))
+	cli := ctrlFake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(framework).Build()
+	r := FrameworkReconciler{Client: cli, Scheme: scheme}
+
+	require.Nil(t, r.reconcileResourceQuota(context.Background(), framework))
+	require.Nil(t, r.reconcileLimitRange(context.Background(), framework))
+
+	quota := v1.ResourceQuota{}
+	require.Nil(t, cli.Get(context.Background(), types.NamespacedName{Name: ketchv1.FrameworkResourceQuotaName, Namespace: "ketch-team-a"}, &quota))
+	require.Equal(t, "2", quota.Spec.Hard.Cpu().String())
+	require.Equal(t, int64(10), quota.Spec.Hard.Pods().Value())
+	require.Equal(t, "team-a", quota.OwnerReferences[0].Name)
+
+	limitRange := v1.LimitRange{}
+	require.Nil(t, cli.Get(context.Background(), types.NamespacedName{Name: ketchv1.FrameworkLimitRangeName, Namespace: "ketch-team-a"}, &limitRange))
+	require.Equal(t, []v1.LimitRangeItem{framework.Spec.LimitRange.LimitRangeItem()}, limitRange.Spec.Limits)
+
+	framework.Spec.ResourceQuota = nil
+	framework.Spec.LimitRange = nil
+	require.Nil(t, r.reconcileResourceQuota(context.Background(), framework))
+	require.Nil(t, r.reconcileLimitRange(context.Background(), framework))
+	require.True(t, errors.IsNotFound(cli.Get(context.Background(), types.NamespacedName{Name: ketchv1.FrameworkResourceQuotaName, Namespace: "ketch-team-a"}, &quota)))
+	require.True(t, errors.IsNotFound(cli.Get(context.Background(), types.NamespacedName{Name: ketchv1.FrameworkLimitRangeName, Namespace: "ketch-team-a"}, &limitRange)))
+}
+
+func TestFrameworkReconciler_reconcileNetworkPolicy(t *testing.T) {
+	backend := ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend", UID: "backend-uid"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-backend",
+			IngressController: ketchv1.IngressControllerSpec{
+				IngressType: ketchv1.NginxIngressControllerType,
+			},
+			NetworkPolicy: &ketchv1.FrameworkNetworkPolicySpec{
+				Mode:              ketchv1.NetworkPolicyModeAllowSameFramework,
+				AllowedFrameworks: []string{"frontend"},
+			},
+		},
+	}
+	frontend := ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "frontend"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-frontend",
+			IngressController: ketchv1.IngressControllerSpec{
+				IngressType: ketchv1.TraefikIngressControllerType,
+				Namespace:   "kube-system",
+			},
+			NetworkPolicy: &ketchv1.FrameworkNetworkPolicySpec{
+				Mode: ketchv1.NetworkPolicyModeIsolated,
+			},
+		},
+	}
+	other := ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "other"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-other"},
+	}
+	frameworks := []ketchv1.Framework{backend, frontend, other}
+
+	namespacePeer := func(namespace string) networkingv1.NetworkPolicyPeer {
+		return networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": namespace}},
+		}
+	}
+	require.Equal(t, []networkingv1.NetworkPolicyPeer{
+		namespacePeer("ingress-nginx"),
+		{PodSelector: &metav1.LabelSelector{}},
+		namespacePeer("ketch-frontend"),
+	}, networkPolicySpec(&backend, frameworks).Ingress[0].From)
+	require.Equal(t, []networkingv1.NetworkPolicyPeer{
+		namespacePeer("kube-system"),
+	}, networkPolicySpec(&frontend, frameworks).Ingress[0].From)
+
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme()(scheme))
+	cli := ctrlFake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(&backend, &frontend, &other).Build()
+	r := FrameworkReconciler{Client: cli, Scheme: scheme}
+
+	require.Nil(t, r.reconcileNetworkPolicy(context.Background(), &backend, frameworks))
+	policy := networkingv1.NetworkPolicy{}
+	key := types.NamespacedName{Name: ketchv1.FrameworkNetworkPolicyName, Namespace: "ketch-backend"}
+	require.Nil(t, cli.Get(context.Background(), key, &policy))
+	require.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}, policy.Spec.PolicyTypes)
+
+	require.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "backend"}}}, r.frameworksAllowing(&frontend))
+	require.Nil(t, r.frameworksAllowing(&other))
+
+	backend.Spec.NetworkPolicy.Mode = ketchv1.NetworkPolicyModeOpen
+	require.Nil(t, r.reconcileNetworkPolicy(context.Background(), &backend, frameworks))
+	require.True(t, errors.IsNotFound(cli.Get(context.Background(), key, &policy)))
+}
+
+func TestFrameworkReconciler_reconcileRoleBindings(t *testing.T) {
+	framework := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", UID: "team-a-uid"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-team-a",
+			Members: []ketchv1.FrameworkMember{
+				{Kind: ketchv1.FrameworkMemberUser, Name: "alice@example.com", Role: ketchv1.FrameworkRoleAdmin},
+				{Kind: ketchv1.FrameworkMemberGroup, Name: "team-a-devs", Role: ketchv1.FrameworkRoleDeveloper},
+				{Kind: ketchv1.FrameworkMemberUser, Name: "bob@example.com", Role: ketchv1.FrameworkRoleDeveloper},
+			},
+		},
+	}
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme()(scheme))
+	cli := ctrlFake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(framework).Build()
+	r := FrameworkReconciler{Client: cli, Scheme: scheme}
+
+	require.Nil(t, r.reconcileRoleBindings(context.Background(), framework))
+
+	developers := rbacv1.RoleBinding{}
+	require.Nil(t, cli.Get(context.Background(), types.NamespacedName{Name: "ketch-framework-developer", Namespace: "ketch-team-a"}, &developers))
+	require.Equal(t, rbacv1.RoleRef{APIGroup: "rbac.authorization.k8s.io", Kind: "ClusterRole", Name: "edit"}, developers.RoleRef)
+	require.Equal(t, []rbacv1.Subject{
+		{APIGroup: "rbac.authorization.k8s.io", Kind: "Group", Name: "team-a-devs"},
+		{APIGroup: "rbac.authorization.k8s.io", Kind: "User", Name: "bob@example.com"},
+	}, developers.Subjects)
+
+	admins := rbacv1.RoleBinding{}
+	require.Nil(t, cli.Get(context.Background(), types.NamespacedName{Name: "ketch-framework-admin", Namespace: "ketch-team-a"}, &admins))
+	require.Equal(t, "admin", admins.RoleRef.Name)
+
+	viewers := rbacv1.RoleBinding{}
+	require.True(t, errors.IsNotFound(cli.Get(context.Background(), types.NamespacedName{Name: "ketch-framework-viewer", Namespace: "ketch-team-a"}, &viewers)))
+
+	framework.Spec.Members = framework.Spec.Members[:1]
+	require.Nil(t, r.reconcileRoleBindings(context.Background(), framework))
+	require.True(t, errors.IsNotFound(cli.Get(context.Background(), types.NamespacedName{Name: "ketch-framework-developer", Namespace: "ketch-team-a"}, &developers)))
+}
+
+func TestFrameworkReconciler_finalize(t *testing.T) {
+	now := metav1.Now()
+	framework := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "gke",
+			DeletionTimestamp: &now,
+			Finalizers:        []string{ketchv1.KetchFinalizer},
+		},
+		Spec: ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+	}
+	app := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "app"},
+		Spec:       ketchv1.AppSpec{Framework: "gke"},
+	}
+	job := &ketchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job"},
+		Spec:       ketchv1.JobSpec{Framework: "gke"},
+	}
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme()(scheme))
+	cli := ctrlFake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(framework, app, job).Build()
+	r := FrameworkReconciler{Client: cli, Scheme: scheme}
+
+	got := ketchv1.Framework{}
+	require.Nil(t, cli.Get(context.Background(), types.NamespacedName{Name: "gke"}, &got))
+	require.Nil(t, r.finalize(context.Background(), &got))
+	require.Nil(t, cli.Get(context.Background(), types.NamespacedName{Name: "gke"}, &got))
+	require.Equal(t, []string{ketchv1.KetchFinalizer}, got.Finalizers)
+	require.Equal(t, ketchv1.FrameworkTerminating, got.Status.Phase)
+	require.Equal(t, "waiting for 1 apps and 1 jobs to be removed or moved to another framework", got.Status.Message)
+
+	require.Nil(t, cli.Delete(context.Background(), app))
+	require.Nil(t, cli.Delete(context.Background(), job))
+	require.Nil(t, r.finalize(context.Background(), &got))
+	require.True(t, errors.IsNotFound(cli.Get(context.Background(), types.NamespacedName{Name: "gke"}, &got)))
+}
+
+func TestFrameworkReconciler_frameworkOfWorkload(t *testing.T) {
+	r := FrameworkReconciler{}
+	app := &ketchv1.App{Spec: ketchv1.AppSpec{Framework: "gke"}}
+	require.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "gke"}}}, r.frameworkOfWorkload(app))
+	job := &ketchv1.Job{Spec: ketchv1.JobSpec{Framework: "aws"}}
+	require.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "aws"}}}, r.frameworkOfWorkload(job))
+	require.Nil(t, r.frameworkOfWorkload(&ketchv1.App{}))
+}
+
+func TestFrameworkReconciler_frameworkOfWorkloadResource(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme()(scheme))
+	cli := ctrlFake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(
+		&ketchv1.App{ObjectMeta: metav1.ObjectMeta{Name: "app"}, Spec: ketchv1.AppSpec{Framework: "gke"}},
+		&ketchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "job"}, Spec: ketchv1.JobSpec{Framework: "aws"}},
+	).Build()
+	r := FrameworkReconciler{Client: cli, Scheme: scheme}
+
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"theketch.io/app-name": "app"}}}
+	require.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "gke"}}}, r.frameworkOfWorkloadResource(deployment))
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"theketch.io/job-name": "job"}}}
+	require.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "aws"}}}, r.frameworkOfWorkloadResource(job))
+	missing := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"theketch.io/app-name": "missing"}}}
+	require.Nil(t, r.frameworkOfWorkloadResource(missing))
+	require.Nil(t, r.frameworkOfWorkloadResource(&appsv1.Deployment{}))
+}
+
+func Test_setNamespaceMetadata(t *testing.T) {
+	namespace := v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "ketch-gke",
+			Labels: map[string]string{"team": "platform"},
+		},
+	}
+	spec := ketchv1.FrameworkSpec{
+		NamespaceLabels:      map[string]string{"pod-security.kubernetes.io/enforce": "baseline", "cost-center": "42"},
+		NamespaceAnnotations: map[string]string{"linkerd.io/inject": "enabled"},
+	}
+	setNamespaceMetadata(&namespace, spec)
+	require.Equal(t, map[string]string{"team": "platform", "pod-security.kubernetes.io/enforce": "baseline", "cost-center": "42"}, namespace.Labels)
+	require.Equal(t, map[string]string{
+		"linkerd.io/inject":                     "enabled",
+		managedNamespaceLabelsAnnotation():      "cost-center,pod-security.kubernetes.io/enforce",
+		managedNamespaceAnnotationsAnnotation(): "linkerd.io/inject",
+	}, namespace.Annotations)
+
+	namespace.Annotations["owner"] = "someone"
+	spec = ketchv1.FrameworkSpec{
+		NamespaceLabels: map[string]string{"cost-center": "43"},
+	}
+	setNamespaceMetadata(&namespace, spec)
+	require.Equal(t, map[string]string{"team": "platform", "cost-center": "43"}, namespace.Labels)
+	require.Equal(t, map[string]string{
+		"owner":                            "someone",
+		managedNamespaceLabelsAnnotation(): "cost-center",
+	}, namespace.Annotations)
+}
+
+func TestFrameworkReconciler_frameworkHealth(t *testing.T) {
+	framework := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "gke"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-gke"},
+	}
+	deployments := []ketchv1.AppDeploymentSpec{
+		{Version: 1, Processes: []ketchv1.ProcessSpec{{Name: "web", Units: conversions.IntPtr(1)}}},
+	}
+	replicas := int32(1)
+	objects := []runtime.Object{
+		framework,
+		&ketchv1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: "ready"},
+			Spec:       ketchv1.AppSpec{Framework: "gke", Deployments: deployments},
+		},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "ready-web-1", Namespace: "ketch-gke"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{UpdatedReplicas: 1, ReadyReplicas: 1},
+		},
+		&ketchv1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: "starting"},
+			Spec:       ketchv1.AppSpec{Framework: "gke", Deployments: deployments},
+		},
+		&ketchv1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: "broken"},
+			Spec:       ketchv1.AppSpec{Framework: "gke", Deployments: deployments},
+			Status: ketchv1.AppStatus{
+				Conditions: []ketchv1.Condition{{Type: ketchv1.Scheduled, Status: v1.ConditionFalse, Message: "failed to render chart"}},
+			},
+		},
+		&ketchv1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: "other"},
+			Spec:       ketchv1.AppSpec{Framework: "aws"},
+		},
+		&ketchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "unscheduled"},
+			Spec:       ketchv1.JobSpec{Framework: "gke"},
+			Status: ketchv1.JobStatus{
+				Conditions: []ketchv1.Condition{{Type: ketchv1.Scheduled, Status: v1.ConditionFalse}},
+			},
+		},
+		&ketchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "failed"},
+			Spec:       ketchv1.JobSpec{Framework: "gke"},
+		},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "failed", Namespace: "ketch-gke"},
+			Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: v1.ConditionTrue}},
+			},
+		},
+		&ketchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "pending"},
+			Spec:       ketchv1.JobSpec{Framework: "gke"},
+		},
+		&v1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: ketchv1.FrameworkResourceQuotaName, Namespace: "ketch-gke"},
+			Status: v1.ResourceQuotaStatus{
+				Hard: v1.ResourceList{v1.ResourcePods: resource.MustParse("10")},
+				Used: v1.ResourceList{v1.ResourcePods: resource.MustParse("2")},
+			},
+		},
+	}
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme()(scheme))
+	cli := ctrlFake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()
+	r := FrameworkReconciler{Client: cli, Scheme: scheme}
+
+	health, err := r.frameworkHealth(context.Background(), framework)
+	require.Nil(t, err)
+	want := &ketchv1.FrameworkHealth{
+		Apps: []ketchv1.FrameworkAppHealth{
+			{Name: "broken", Phase: ketchv1.AppError, Message: "failed to render chart"},
+			{Name: "ready", Phase: ketchv1.AppRunning, Ready: true},
+			{Name: "starting", Phase: ketchv1.AppRunning, Message: "waiting for deployments to be ready"},
+		},
+		ReadyApps:  1,
+		FailedJobs: 2,
+		ResourceQuota: &v1.ObjectReference{
+			Kind:            "ResourceQuota",
+			APIVersion:      "v1",
+			Name:            ketchv1.FrameworkResourceQuotaName,
+			Namespace:       "ketch-gke",
+			ResourceVersion: "999",
+		},
+	}
+	require.Equal(t, want, health)
+	require.False(t, health.Healthy())
+}
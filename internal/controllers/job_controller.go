@@ -143,6 +143,13 @@ func (r *JobReconciler) reconcile(ctx context.Context, job *ketchv1.Job) reconci
 		}
 	}
 
+	if !framework.CanAddJob(job.Name) {
+		return reconcileResult{
+			status:  v1.ConditionFalse,
+			message: "you have reached the limit of jobs",
+		}
+	}
+
 	options := []chart.Option{
 		chart.WithTemplates(*tpls),
 	}
@@ -179,6 +186,13 @@ func (r *JobReconciler) reconcile(ctx context.Context, job *ketchv1.Job) reconci
 		}
 	}
 
+	if err := r.removeFromPreviousFrameworks(ctx, job, framework); err != nil {
+		return reconcileResult{
+			status:  v1.ConditionFalse,
+			message: fmt.Sprintf("failed to remove job from previous framework: %v", err),
+		}
+	}
+
 	return reconcileResult{
 		framework: ref,
 		status:    v1.ConditionTrue,
@@ -195,31 +209,9 @@ func (r *JobReconciler) deleteChart(ctx context.Context, job *ketchv1.Job) error
 		if !framework.HasJob(job.Name) {
 			continue
 		}
-
-		if uninstallHelmChart(ketchv1.Group, job.Annotations) {
-			helmClient, err := r.HelmFactoryFn(framework.Spec.NamespaceName)
-			if err != nil {
-				return err
-			}
-			err = helmClient.DeleteChart(job.Name)
-			if err != nil {
-				return err
-			}
-		}
-		patchedFramework := framework
-
-		patchedFramework.Status.Jobs = make([]string, 0, len(patchedFramework.Status.Jobs))
-		for _, name := range framework.Status.Jobs {
-			if name == job.Name {
-				continue
-			}
-			patchedFramework.Status.Jobs = append(patchedFramework.Status.Jobs, name)
-		}
-		mergePatch := client.MergeFrom(&framework)
-		if err := r.Status().Patch(ctx, &patchedFramework, mergePatch); err != nil {
+		if err := r.removeJobFromFramework(ctx, job, framework, true); err != nil {
 			return err
 		}
-		break
 	}
 	controllerutil.RemoveFinalizer(job, ketchv1.KetchFinalizer)
 	if err := r.Update(ctx, job); err != nil {
@@ -228,6 +220,51 @@ func (r *JobReconciler) deleteChart(ctx context.Context, job *ketchv1.Job) error
 	return nil
 }
 
+// removeFromPreviousFrameworks uninstalls the job from frameworks it was moved from.
+func (r *JobReconciler) removeFromPreviousFrameworks(ctx context.Context, job *ketchv1.Job, framework ketchv1.Framework) error {
+	frameworks := ketchv1.FrameworkList{}
+	if err := r.List(ctx, &frameworks); err != nil {
+		return err
+	}
+	for _, item := range frameworks.Items {
+		if item.Name == framework.Name || !item.HasJob(job.Name) {
+			continue
+		}
+		// frameworks sharing a namespace share the job's helm release, which has just been upgraded by the current framework.
+		uninstall := item.Spec.NamespaceName != framework.Spec.NamespaceName
+		if err := r.removeJobFromFramework(ctx, job, item, uninstall); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeJobFromFramework removes the job from the framework's status and, if uninstall is true,
+// uninstalls the job's helm chart from the framework's namespace.
+func (r *JobReconciler) removeJobFromFramework(ctx context.Context, job *ketchv1.Job, framework ketchv1.Framework, uninstall bool) error {
+	if uninstall && uninstallHelmChart(ketchv1.Group, job.Annotations) {
+		helmClient, err := r.HelmFactoryFn(framework.Spec.NamespaceName)
+		if err != nil {
+			return err
+		}
+		err = helmClient.DeleteChart(job.Name)
+		if err != nil {
+			return err
+		}
+	}
+	patchedFramework := framework
+
+	patchedFramework.Status.Jobs = make([]string, 0, len(patchedFramework.Status.Jobs))
+	for _, name := range framework.Status.Jobs {
+		if name == job.Name {
+			continue
+		}
+		patchedFramework.Status.Jobs = append(patchedFramework.Status.Jobs, name)
+	}
+	mergePatch := client.MergeFrom(&framework)
+	return r.Status().Patch(ctx, &patchedFramework, mergePatch)
+}
+
 // String is a Stringer interface implementation
 func (r *JobReconcileReason) String() string {
 	return r.JobName
@@ -9,9 +9,12 @@ import (
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlFake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
@@ -32,6 +35,19 @@ func TestJobReconciler_Reconcile(t *testing.T) {
 				},
 			},
 		},
+		&ketchv1.Framework{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "no-jobs-framework",
+			},
+			Spec: ketchv1.FrameworkSpec{
+				NamespaceName: "no-jobs",
+				AppQuotaLimit: conversions.IntPtr(100),
+				JobQuotaLimit: conversions.IntPtr(0),
+				IngressController: ketchv1.IngressControllerSpec{
+					IngressType: ketchv1.IstioIngressControllerType,
+				},
+			},
+		},
 	}
 	helmMock := &helm{
 		updateChartResults: map[string]error{
@@ -98,6 +114,20 @@ func TestJobReconciler_Reconcile(t *testing.T) {
 			wantConditionStatus:  v1.ConditionFalse,
 			wantConditionMessage: `framework "non-existent-framework" is not found`,
 		},
+		{
+			name: "job quota reached",
+			job: ketchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "quota-job",
+					Namespace: "default",
+				},
+				Spec: ketchv1.JobSpec{
+					Framework: "no-jobs-framework",
+				},
+			},
+			wantConditionStatus:  v1.ConditionFalse,
+			wantConditionMessage: "you have reached the limit of jobs",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -124,3 +154,41 @@ func TestJobReconciler_Reconcile(t *testing.T) {
 	}
 	require.Equal(t, []string{"test-job"}, helmMock.deleteChartCalled)
 }
+
+func TestJobReconciler_removeFromPreviousFrameworksSharingNamespace(t *testing.T) {
+	staging := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-shared"},
+		Status:     ketchv1.FrameworkStatus{Jobs: []string{"job"}},
+	}
+	production := &ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "production"},
+		Spec:       ketchv1.FrameworkSpec{NamespaceName: "ketch-shared"},
+		Status:     ketchv1.FrameworkStatus{Jobs: []string{"job"}},
+	}
+	job := &ketchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "job"},
+		Spec:       ketchv1.JobSpec{Name: "job", Framework: "production"},
+	}
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	require.Nil(t, ketchv1.AddToScheme()(scheme))
+	cli := ctrlFake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(staging, production, job).Build()
+	helmMock := &helm{}
+	r := JobReconciler{
+		Client: cli,
+		HelmFactoryFn: func(namespace string) (Helm, error) {
+			return helmMock, nil
+		},
+	}
+
+	require.Nil(t, r.removeFromPreviousFrameworks(context.Background(), job, *production))
+	// the release in the shared namespace is the one the job runs now
+	require.Nil(t, helmMock.deleteChartCalled)
+
+	framework := ketchv1.Framework{}
+	require.Nil(t, cli.Get(context.Background(), types.NamespacedName{Name: "staging"}, &framework))
+	require.Empty(t, framework.Status.Jobs)
+	require.Nil(t, cli.Get(context.Background(), types.NamespacedName{Name: "production"}, &framework))
+	require.Equal(t, []string{"job"}, framework.Status.Jobs)
+}
@@ -90,9 +90,10 @@ func setup(reader templates.Reader, helm Helm, objects []client.Object) (*testin
 		return nil, err
 	}
 	err = (&AppReconciler{
-		Client:         k8sManager.GetClient(),
-		Log:            ctrl.Log.WithName("controllers").WithName("App"),
-		TemplateReader: reader,
+		Client:             k8sManager.GetClient(),
+		Log:                ctrl.Log.WithName("controllers").WithName("App"),
+		TemplateReader:     reader,
+		TemplatesNamespace: templates.KetchNamespace,
 		HelmFactoryFn: func(namespace string) (Helm, error) {
 			return helm, nil
 		},
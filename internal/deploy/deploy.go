@@ -19,6 +19,7 @@ import (
 	"github.com/theketchio/ketch/internal/build"
 	"github.com/theketchio/ketch/internal/chart"
 	"github.com/theketchio/ketch/internal/errors"
+	"github.com/theketchio/ketch/internal/signature"
 )
 
 const (
@@ -71,7 +72,7 @@ func getAppWithUpdater(ctx context.Context, client Client, cs *ChangeSet) (*ketc
 		}
 		generateDefaultCName := true
 		var cname ketchv1.CnameList
-		if cs.cname != nil {
+		if cs.cname != nil && len(*cs.cname) > 0 {
 			generateDefaultCName = false
 			cname = *cs.cname
 		}
@@ -171,6 +172,51 @@ func getUpdatedApp(ctx context.Context, client Client, cs *ChangeSet) (*ketchv1.
 			return err
 		}
 
+		cnames, err := cs.getCnames()
+		if err := assign(err, func() error {
+			app.Spec.Ingress.Cnames = cnames
+			changed = true
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		labels, err := cs.getLabels()
+		if err := assign(err, func() error {
+			app.Spec.Labels = labels
+			changed = true
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		annotations, err := cs.getAnnotations()
+		if err := assign(err, func() error {
+			app.Spec.Annotations = annotations
+			changed = true
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		serviceBindings, err := cs.getServiceBindings()
+		if err := assign(err, func() error {
+			app.Spec.ServiceBindings = serviceBindings
+			changed = true
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		serviceAccountName, err := cs.getServiceAccountName()
+		if err := assign(err, func() error {
+			app.Spec.ServiceAccountName = serviceAccountName
+			changed = true
+			return nil
+		}); err != nil {
+			return err
+		}
+
 		return updater(ctx, app, changed)
 	})
 	return app, err
@@ -205,7 +251,6 @@ func deployImage(ctx context.Context, svc *Services, app *ketchv1.App, params *C
 	}
 
 	image, _ := params.getImage()
-
 	fromSource := params.sourcePath != nil
 	// build image from source if valid path provided
 	if fromSource {
@@ -221,6 +266,19 @@ func deployImage(ctx context.Context, svc *Services, app *ketchv1.App, params *C
 		secretNamespace: framework.Spec.NamespaceName,
 		client:          svc.KubeClient,
 	}
+	var imageDigest string
+	if svc.GetImageDigest != nil {
+		// pin the image, so scale-ups and canary steps run the same image even if its tag is pushed again
+		if imageDigest, err = svc.GetImageDigest(ctx, imageRequest); err != nil {
+			return err
+		}
+	}
+	// the policy, the config and the signature are checked for the pinned image the app is going to run
+	pinned := ketchv1.AppDeploymentSpec{Image: image, ImageDigest: imageDigest}
+	imageRequest.imageName = pinned.PinnedImage()
+	if err := framework.Spec.ImagePolicy.Validate(imageRequest.imageName); err != nil {
+		return errors.Wrap(err, "image rejected by %q framework", framework.Name)
+	}
 	imgConfig, err := svc.GetImageConfig(ctx, imageRequest)
 	if err != nil {
 		return err
@@ -230,9 +288,19 @@ func deployImage(ctx context.Context, svc *Services, app *ketchv1.App, params *C
 	if err != nil {
 		return err
 	}
+	if framework.Spec.ImagePolicy.RequiresSignatures() {
+		options, err := remoteOptions(ctx, imageRequest)
+		if err != nil {
+			return err
+		}
+		if err := signature.VerifyImagePolicy(ctx, framework.Spec.ImagePolicy, imageRequest.imageName, options...); err != nil {
+			return errors.Wrap(err, "image rejected by %q framework", framework.Name)
+		}
+	}
 	var updateRequest updateAppCRDRequest
 	updateRequest.appVersion = params.appVersion
 	updateRequest.image = image
+	updateRequest.imageDigest = imageDigest
 	steps, _ := params.getSteps()
 	updateRequest.steps = steps
 	stepWeight, _ := params.getStepWeight()
@@ -292,6 +360,7 @@ func makeProcfile(cfg *registryv1.ConfigFile) (*chart.Procfile, error) {
 type updateAppCRDRequest struct {
 	appVersion        *string
 	image             string
+	imageDigest       string
 	steps             int
 	stepWeight        uint8
 	procFile          *chart.Procfile
@@ -373,16 +442,16 @@ func updateAppCRD(ctx context.Context, svc *Services, appName string, args updat
 
 		// default deployment spec for an app
 		deploymentSpec := ketchv1.AppDeploymentSpec{
-			Image:     args.image,
-			Version:   ketchv1.DeploymentVersion(updated.Spec.DeploymentsCount),
-			Processes: processes,
-			KetchYaml: args.ketchYaml,
+			Image:       args.image,
+			ImageDigest: args.imageDigest,
+			Version:     ketchv1.DeploymentVersion(updated.Spec.DeploymentsCount),
+			Processes:   processes,
+			KetchYaml:   args.ketchYaml,
 			RoutingSettings: ketchv1.RoutingSettings{
 				Weight: defaultTrafficWeight,
 			},
 			ExposedPorts: exposedPorts,
 		}
-
 		// update deployment and version only for canary deployment or a new deployment
 		if !usePreviousDeploymentSpecs || args.steps > 1 {
 			deploymentSpec.Version += 1
@@ -419,14 +488,39 @@ func updateAppCRD(ctx context.Context, svc *Services, appName string, args updat
 			}
 		}
 		if args.processes != nil {
-			for _, process := range *args.processes {
-				s := ketchv1.NewSelector(1, process.Name) // no process versions other than 1 w/ app.yaml (potentially multiple args.processes)
-				if err := updated.SetUnits(s, *process.Units); err != nil {
-					return err
-				}
+			deployment := &updated.Spec.Deployments[len(updated.Spec.Deployments)-1]
+			if err := setProcessSpecs(deployment, *args.processes); err != nil {
+				return err
 			}
 		}
 		return svc.Client.Update(ctx, &updated)
 	})
 	return &updated, err
 }
+
+// setProcessSpecs sets units, environment variables, resources, volumes and security context of processes
+// described in an application.yaml file to processes of the deployment.
+func setProcessSpecs(deploymentSpec *ketchv1.AppDeploymentSpec, processes []ketchv1.ProcessSpec) error {
+	for _, process := range processes {
+		found := false
+		for i := range deploymentSpec.Processes {
+			ps := &deploymentSpec.Processes[i]
+			if ps.Name != process.Name {
+				continue
+			}
+			if process.Units != nil {
+				ps.Units = process.Units
+			}
+			ps.Env = process.Env
+			ps.Resources = process.Resources
+			ps.Volumes = process.Volumes
+			ps.VolumeMounts = process.VolumeMounts
+			ps.SecurityContext = process.SecurityContext
+			found = true
+		}
+		if !found {
+			return fmt.Errorf("%w: %q", ketchv1.ErrProcessNotFound, process.Name)
+		}
+	}
+	return nil
+}
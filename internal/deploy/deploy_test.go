@@ -7,6 +7,8 @@ import (
 	registryv1 "github.com/google/go-containerregistry/pkg/v1"
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 	"github.com/theketchio/ketch/internal/chart"
+	"github.com/theketchio/ketch/internal/utils/conversions"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -307,6 +309,108 @@ func Test_updateAppCRD(t *testing.T) {
 				require.Equal(t, mock.app.Spec.Deployments[0].Version, ketchv1.DeploymentVersion(1))
 			},
 		},
+		{
+			name: "processes of application.yaml are set to the new deployment",
+			args: args{
+				ctx:     context.Background(),
+				appName: "test-app",
+				args: updateAppCRDRequest{
+					image: "test/pack-test:v2",
+					procFile: &chart.Procfile{
+						Processes:           map[string][]string{"web": {"web"}, "worker": {"worker"}},
+						RoutableProcessName: "web",
+					},
+					configFile: &registryv1.ConfigFile{
+						Config: registryv1.Config{
+							ExposedPorts: make(map[string]struct{}),
+						},
+					},
+					processes: &[]ketchv1.ProcessSpec{
+						{
+							Name:            "worker",
+							Units:           conversions.IntPtr(3),
+							Env:             []ketchv1.Env{{Name: "QUEUE", Value: "jobs"}},
+							SecurityContext: &v1.SecurityContext{RunAsNonRoot: conversions.BoolPtr(true)},
+						},
+					},
+				},
+				svc: &Services{
+					Client: func() *mockClient {
+						m := newMockClient()
+						m.app.Spec.DeploymentsCount = 1
+						m.app.Spec.Deployments = []ketchv1.AppDeploymentSpec{
+							{Image: "test/pack-test:v1", Version: 1},
+						}
+						return m
+					}(),
+				},
+			},
+			validate: func(t *testing.T, mock *mockClient) {
+				require.Equal(t, mock.app.Spec.Deployments[0].Version, ketchv1.DeploymentVersion(2))
+				web := mock.app.Spec.Deployments[0].Processes[0]
+				require.Nil(t, web.Env)
+				worker := mock.app.Spec.Deployments[0].Processes[1]
+				require.Equal(t, "worker", worker.Name)
+				require.Equal(t, 3, *worker.Units)
+				require.Equal(t, []ketchv1.Env{{Name: "QUEUE", Value: "jobs"}}, worker.Env)
+				require.Equal(t, &v1.SecurityContext{RunAsNonRoot: conversions.BoolPtr(true)}, worker.SecurityContext)
+			},
+		},
+		{
+			name: "units of application.yaml processes are not overwritten by default units",
+			args: args{
+				ctx:     context.Background(),
+				appName: "test-app",
+				args: updateAppCRDRequest{
+					image: "test/pack-test:v2",
+					units: 1,
+					procFile: &chart.Procfile{
+						Processes:           map[string][]string{"web": {"web"}, "worker": {"worker"}},
+						RoutableProcessName: "web",
+					},
+					configFile: &registryv1.ConfigFile{
+						Config: registryv1.Config{
+							ExposedPorts: make(map[string]struct{}),
+						},
+					},
+					processes: &[]ketchv1.ProcessSpec{
+						{Name: "web", Units: conversions.IntPtr(2)},
+						{Name: "worker", Units: conversions.IntPtr(3)},
+					},
+				},
+				svc: &Services{
+					Client: newMockClient(),
+				},
+			},
+			validate: func(t *testing.T, mock *mockClient) {
+				require.Equal(t, 2, *mock.app.Spec.Deployments[0].Processes[0].Units)
+				require.Equal(t, 3, *mock.app.Spec.Deployments[0].Processes[1].Units)
+			},
+		},
+		{
+			name: "process of application.yaml not found",
+			args: args{
+				ctx:     context.Background(),
+				appName: "test-app",
+				args: updateAppCRDRequest{
+					image: "test/pack-test:v2",
+					procFile: &chart.Procfile{
+						Processes:           map[string][]string{"web": {"web"}},
+						RoutableProcessName: "web",
+					},
+					configFile: &registryv1.ConfigFile{
+						Config: registryv1.Config{
+							ExposedPorts: make(map[string]struct{}),
+						},
+					},
+					processes: &[]ketchv1.ProcessSpec{{Name: "worker"}},
+				},
+				svc: &Services{
+					Client: newMockClient(),
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,73 @@
+package deploy
+
+import (
+	"context"
+	"reflect"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DryRunClient is a Client that validates created and updated objects with a server-side dry-run
+// and keeps them in memory instead of persisting them in the cluster.
+type DryRunClient struct {
+	client  Client
+	objects map[dryRunKey]client.Object
+	// created tracks objects that don't exist in the cluster.
+	created map[dryRunKey]bool
+}
+
+type dryRunKey struct {
+	kind reflect.Type
+	key  client.ObjectKey
+}
+
+var _ Client = &DryRunClient{}
+
+// NewDryRunClient returns a DryRunClient reading objects from the client.
+func NewDryRunClient(c Client) *DryRunClient {
+	return &DryRunClient{
+		client:  c,
+		objects: map[dryRunKey]client.Object{},
+		created: map[dryRunKey]bool{},
+	}
+}
+
+func newDryRunKey(obj client.Object) dryRunKey {
+	return dryRunKey{kind: reflect.TypeOf(obj), key: client.ObjectKeyFromObject(obj)}
+}
+
+// Get returns the object kept in memory or reads it from the cluster if it hasn't been created or updated.
+func (c *DryRunClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+	stored, ok := c.objects[dryRunKey{kind: reflect.TypeOf(obj), key: key}]
+	if !ok {
+		return c.client.Get(ctx, key, obj)
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(stored.DeepCopyObject()).Elem())
+	return nil
+}
+
+func (c *DryRunClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := c.client.Create(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+		return err
+	}
+	key := newDryRunKey(obj)
+	c.objects[key] = obj.DeepCopyObject().(client.Object)
+	c.created[key] = true
+	return nil
+}
+
+func (c *DryRunClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	key := newDryRunKey(obj)
+	if c.created[key] {
+		// the object doesn't exist in the cluster, so it's validated as a new one.
+		created := obj.DeepCopyObject().(client.Object)
+		created.SetResourceVersion("")
+		if err := c.client.Create(ctx, created, client.DryRunAll); err != nil {
+			return err
+		}
+	} else if err := c.client.Update(ctx, obj, append(opts, client.DryRunAll)...); err != nil {
+		return err
+	}
+	c.objects[key] = obj.DeepCopyObject().(client.Object)
+	return nil
+}
@@ -0,0 +1,48 @@
+package deploy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlFake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+func TestDryRunClient(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.Nil(t, ketchv1.AddToScheme()(scheme))
+	dashboard := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard"},
+		Spec:       ketchv1.AppSpec{Framework: "gke", Description: "dashboard"},
+	}
+	cli := ctrlFake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(dashboard).Build()
+	dryRun := NewDryRunClient(cli)
+	ctx := context.Background()
+
+	var app ketchv1.App
+	require.Nil(t, dryRun.Get(ctx, types.NamespacedName{Name: "dashboard"}, &app))
+	app.Spec.Description = "updated"
+	require.Nil(t, dryRun.Update(ctx, &app))
+
+	var got ketchv1.App
+	require.Nil(t, dryRun.Get(ctx, types.NamespacedName{Name: "dashboard"}, &got))
+	require.Equal(t, "updated", got.Spec.Description)
+	require.Nil(t, cli.Get(ctx, types.NamespacedName{Name: "dashboard"}, &got))
+	require.Equal(t, "dashboard", got.Spec.Description)
+
+	created := &ketchv1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "backend"},
+		Spec:       ketchv1.AppSpec{Framework: "gke"},
+	}
+	require.Nil(t, dryRun.Create(ctx, created))
+	created.Spec.Description = "updated"
+	require.Nil(t, dryRun.Update(ctx, created))
+	require.Nil(t, dryRun.Get(ctx, types.NamespacedName{Name: "backend"}, &got))
+	require.Equal(t, "updated", got.Spec.Description)
+	require.NotNil(t, cli.Get(ctx, types.NamespacedName{Name: "backend"}, &got))
+}
@@ -26,6 +26,41 @@ func GetImageConfig(ctx context.Context, args ImageConfigRequest) (*registryv1.C
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse reference for image %q", args.imageName)
 	}
+	options, err := remoteOptions(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	img, err := remote.Image(ref, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get config for image %q", args.imageName)
+	}
+	return img.ConfigFile()
+}
+
+type GetImageDigestFn func(ctx context.Context, args ImageConfigRequest) (string, error)
+
+// GetImageDigest resolves the image's tag to the digest of the image in the registry.
+func GetImageDigest(ctx context.Context, args ImageConfigRequest) (string, error) {
+	ref, err := name.ParseReference(args.imageName)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse reference for image %q", args.imageName)
+	}
+	if digest, ok := ref.(name.Digest); ok {
+		return digest.DigestStr(), nil
+	}
+	options, err := remoteOptions(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	descriptor, err := remote.Head(ref, options...)
+	if err != nil {
+		return "", errors.Wrap(err, "could not get digest for image %q", args.imageName)
+	}
+	return descriptor.Digest.String(), nil
+}
+
+// remoteOptions returns options to authenticate to the registry with the image pull secret of the request.
+func remoteOptions(ctx context.Context, args ImageConfigRequest) ([]remote.Option, error) {
 	var options []remote.Option
 	if args.secretName != "" {
 		keychainOpts := k8schain.Options{
@@ -38,9 +73,5 @@ func GetImageConfig(ctx context.Context, args ImageConfigRequest) (*registryv1.C
 		}
 		options = append(options, remote.WithAuthFromKeychain(keychain))
 	}
-	img, err := remote.Image(ref, options...)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not get config for image %q", args.imageName)
-	}
-	return img.ConfigFile()
+	return options, nil
 }
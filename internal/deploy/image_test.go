@@ -0,0 +1,55 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetImageDigest(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	u, err := url.Parse(server.URL)
+	require.Nil(t, err)
+
+	image := fmt.Sprintf("%s/shipasoftware/dashboard:latest", u.Host)
+	ref, err := name.ParseReference(image)
+	require.Nil(t, err)
+	pushImage := func() string {
+		img, err := random.Image(1024, 1)
+		require.Nil(t, err)
+		require.Nil(t, remote.Write(ref, img))
+		digest, err := img.Digest()
+		require.Nil(t, err)
+		return digest.String()
+	}
+
+	first := pushImage()
+	digest, err := GetImageDigest(context.Background(), ImageConfigRequest{imageName: image})
+	require.Nil(t, err)
+	require.Equal(t, first, digest)
+
+	// the tag is pushed again
+	second := pushImage()
+	require.NotEqual(t, first, second)
+	digest, err = GetImageDigest(context.Background(), ImageConfigRequest{imageName: image})
+	require.Nil(t, err)
+	require.Equal(t, second, digest)
+
+	// a digest reference isn't resolved
+	pinned := fmt.Sprintf("%s/shipasoftware/dashboard@%s", u.Host, first)
+	digest, err = GetImageDigest(context.Background(), ImageConfigRequest{imageName: pinned})
+	require.Nil(t, err)
+	require.Equal(t, first, digest)
+
+	_, err = GetImageDigest(context.Background(), ImageConfigRequest{imageName: fmt.Sprintf("%s/shipasoftware/missing:v1", u.Host)})
+	require.NotNil(t, err)
+}
@@ -0,0 +1,142 @@
+package deploy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// environmentField is the field of an application.yaml file whose items are merged by names of env variables.
+const environmentField = "environment"
+
+// listMergeKeys are fields identifying items of lists of objects, the first key present in all items is used to merge lists.
+var listMergeKeys = []string{"name"}
+
+// replacedListFields are fields of lists of objects the overlay replaces rather than merges,
+// e.g. an environment may serve an app under entirely different hostnames.
+var replacedListFields = map[string]bool{"cnames": true}
+
+// OverlayFilename returns the name of the overlay of an application.yaml file for the environment,
+// e.g. application.prod.yaml for application.yaml and prod.
+func OverlayFilename(filename, env string) string {
+	ext := filepath.Ext(filename)
+	return fmt.Sprintf("%s.%s%s", strings.TrimSuffix(filename, ext), env, ext)
+}
+
+// ReadApplicationYaml returns the content of an application.yaml file merged with its overlay if Options.EnvOverlay is set.
+func (o *Options) ReadApplicationYaml(filename string) ([]byte, error) {
+	base, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	if o.EnvOverlay == "" {
+		return base, nil
+	}
+	overlayFilename := OverlayFilename(filename, o.EnvOverlay)
+	overlay, err := os.ReadFile(overlayFilename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q overlay: %w", o.EnvOverlay, err)
+	}
+	merged, err := MergeApplicationYaml(base, overlay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge %s into %s: %w", overlayFilename, filename, err)
+	}
+	return merged, nil
+}
+
+// MergeApplicationYaml merges an overlay into a base application.yaml.
+// Objects are merged recursively and a null value removes a field.
+// Lists of objects are merged by names of items, environment lists are merged by names of env variables,
+// cnames and other lists of the overlay replace lists of the base.
+func MergeApplicationYaml(base, overlay []byte) ([]byte, error) {
+	var baseContent, overlayContent map[string]interface{}
+	if err := yaml.Unmarshal(base, &baseContent); err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(overlay, &overlayContent); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(mergeValues(baseContent, overlayContent, ""))
+}
+
+func mergeValues(base, overlay interface{}, field string) interface{} {
+	switch o := overlay.(type) {
+	case map[string]interface{}:
+		b, ok := base.(map[string]interface{})
+		if !ok {
+			return o
+		}
+		merged := make(map[string]interface{}, len(b)+len(o))
+		for key, value := range b {
+			merged[key] = value
+		}
+		for key, value := range o {
+			if value == nil {
+				delete(merged, key)
+				continue
+			}
+			merged[key] = mergeValues(b[key], value, key)
+		}
+		return merged
+	case []interface{}:
+		b, ok := base.([]interface{})
+		if !ok {
+			return o
+		}
+		if field == environmentField {
+			return mergeLists(b, o, envName)
+		}
+		if replacedListFields[field] {
+			return o
+		}
+		for _, mergeKey := range listMergeKeys {
+			if hasMergeKey(b, mergeKey) && hasMergeKey(o, mergeKey) {
+				return mergeLists(b, o, func(item interface{}) string {
+					return fmt.Sprint(item.(map[string]interface{})[mergeKey])
+				})
+			}
+		}
+		return o
+	}
+	return overlay
+}
+
+// mergeLists merges items of the overlay into items of the base with the same key, new items are appended.
+func mergeLists(base, overlay []interface{}, key func(interface{}) string) []interface{} {
+	merged := make([]interface{}, len(base))
+	copy(merged, base)
+	indexes := make(map[string]int, len(base))
+	for i, item := range base {
+		indexes[key(item)] = i
+	}
+	for _, item := range overlay {
+		if i, ok := indexes[key(item)]; ok {
+			merged[i] = mergeValues(merged[i], item, "")
+			continue
+		}
+		indexes[key(item)] = len(merged)
+		merged = append(merged, item)
+	}
+	return merged
+}
+
+func hasMergeKey(items []interface{}, mergeKey string) bool {
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if _, ok := m[mergeKey]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// envName returns the name of an env variable in the NAME=VALUE format.
+func envName(item interface{}) string {
+	return strings.SplitN(fmt.Sprint(item), "=", 2)[0]
+}
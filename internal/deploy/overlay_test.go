@@ -0,0 +1,152 @@
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/utils/conversions"
+)
+
+func TestOverlayFilename(t *testing.T) {
+	require.Equal(t, "app/application.prod.yaml", OverlayFilename("app/application.yaml", "prod"))
+	require.Equal(t, "app.dev.yml", OverlayFilename("app.yml", "dev"))
+}
+
+func TestMergeApplicationYaml(t *testing.T) {
+	tests := []struct {
+		description string
+		base        string
+		overlay     string
+		want        string
+	}{
+		{
+			description: "scalar fields are overridden",
+			base: `name: dashboard
+image: shipasoftware/dashboard:v1
+framework: dev
+description: dashboard`,
+			overlay: `image: shipasoftware/dashboard:v2
+framework: prod`,
+			want: `description: dashboard
+framework: prod
+image: shipasoftware/dashboard:v2
+name: dashboard
+`,
+		},
+		{
+			description: "null removes a field",
+			base: `name: dashboard
+description: dashboard`,
+			overlay: `description: null`,
+			want: `name: dashboard
+`,
+		},
+		{
+			description: "environment is merged by variable name",
+			base: `environment:
+  - LOG_LEVEL=debug
+  - PORT=8080`,
+			overlay: `environment:
+  - LOG_LEVEL=warn
+  - SENTRY=on`,
+			want: `environment:
+- LOG_LEVEL=warn
+- PORT=8080
+- SENTRY=on
+`,
+		},
+		{
+			description: "processes are merged by name",
+			base: `processes:
+  - name: web
+    units: 1
+    environment:
+      - MODE=web
+  - name: worker
+    units: 1`,
+			overlay: `processes:
+  - name: worker
+    units: 5`,
+			want: `processes:
+- environment:
+  - MODE=web
+  name: web
+  units: 1
+- name: worker
+  units: 5
+`,
+		},
+		{
+			description: "cnames are replaced",
+			base: `cnames:
+  - dnsName: dev.example.com
+  - dnsName: staging.example.com`,
+			overlay: `cnames:
+  - dnsName: example.com
+    secure: true`,
+			want: `cnames:
+- dnsName: example.com
+  secure: true
+`,
+		},
+		{
+			description: "other lists are replaced",
+			base: `buildPacks:
+  - paketo/go
+  - paketo/node`,
+			overlay: `buildPacks:
+  - paketo/java`,
+			want: `buildPacks:
+- paketo/java
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			got, err := MergeApplicationYaml([]byte(tt.base), []byte(tt.overlay))
+			require.Nil(t, err)
+			require.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestGetChangeSetFromYamlWithOverlay(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "application.yaml")
+	require.Nil(t, os.WriteFile(filename, []byte(`name: dashboard
+image: shipasoftware/dashboard:v1
+framework: dev
+environment:
+  - LOG_LEVEL=debug
+processes:
+  - name: web
+    units: 1
+`), 0600))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "application.prod.yaml"), []byte(`image: shipasoftware/dashboard:v2
+framework: prod
+environment:
+  - LOG_LEVEL=warn
+processes:
+  - name: web
+    units: 3
+cname:
+  dnsName: dashboard.example.com
+`), 0600))
+
+	options := &Options{EnvOverlay: "prod"}
+	cs, err := options.GetChangeSetFromYaml(filename)
+	require.Nil(t, err)
+	require.Equal(t, "shipasoftware/dashboard:v2", *cs.image)
+	require.Equal(t, "prod", *cs.framework)
+	require.Equal(t, []string{"LOG_LEVEL=warn"}, *cs.envs)
+	require.Equal(t, []ketchv1.ProcessSpec{{Name: "web", Units: conversions.IntPtr(3)}}, *cs.processes)
+	require.Equal(t, ketchv1.CnameList{{Name: "dashboard.example.com"}}, *cs.cname)
+
+	_, err = (&Options{EnvOverlay: "staging"}).GetChangeSetFromYaml(filename)
+	require.NotNil(t, err)
+	require.Contains(t, err.Error(), `failed to read "staging" overlay`)
+}
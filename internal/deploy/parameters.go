@@ -39,6 +39,8 @@ const (
 	FlagUnits          = "units"
 	FlagVersion        = "unit-version"
 	FlagProcess        = "unit-process"
+	FlagEnvOverlay     = "env-overlay"
+	FlagEnvFile        = "env-file"
 
 	FlagAppShort         = "a"
 	FlagImageShort       = "i"
@@ -64,6 +66,8 @@ type Services struct {
 	Builder SourceBuilderFn
 	// Function that retrieve image config
 	GetImageConfig GetImageConfigFn
+	// Function that resolves an image's tag to a digest, images aren't pinned to digests if it is nil
+	GetImageDigest GetImageDigestFn
 	// Wait is a function that will wait until it detects the a deployment is finished
 	Wait WaitFn
 	// Writer probably points to stdout or stderr, receives textual output
@@ -88,6 +92,7 @@ type Options struct {
 	Framework            string
 	Description          string
 	Envs                 []string
+	EnvFile              string
 	DockerRegistrySecret string
 	Builder              string
 	BuildPacks           []string
@@ -95,6 +100,9 @@ type Options struct {
 	Units   int
 	Version int
 	Process string
+
+	// EnvOverlay is the environment of an application.<env>.yaml overlay merged into an application.yaml file.
+	EnvOverlay string
 }
 
 type ChangeSet struct {
@@ -110,6 +118,7 @@ type ChangeSet struct {
 	subPaths             *[]string
 	description          *string
 	envs                 *[]string
+	envFile              *string
 	framework            *string
 	dockerRegistrySecret *string
 	builder              *string
@@ -119,9 +128,15 @@ type ChangeSet struct {
 	processes            *[]ketchv1.ProcessSpec
 	ketchYamlData        *ketchv1.KetchYamlData
 	cname                *ketchv1.CnameList
+	labels               *[]ketchv1.MetadataItem
+	annotations          *[]ketchv1.MetadataItem
+	serviceBindings      *[]ketchv1.ServiceBinding
+	serviceAccountName   *string
 	units                *int
 	version              *int
 	process              *string
+	// omittedFields are fields an application.yaml file omits, they are removed from the app.
+	omittedFields []string
 }
 
 func (o Options) GetChangeSet(flags *pflag.FlagSet) *ChangeSet {
@@ -160,6 +175,9 @@ func (o Options) GetChangeSet(flags *pflag.FlagSet) *ChangeSet {
 		FlagEnvironment: func(c *ChangeSet) {
 			c.envs = &o.Envs
 		},
+		FlagEnvFile: func(c *ChangeSet) {
+			c.envFile = &o.EnvFile
+		},
 		FlagFramework: func(c *ChangeSet) {
 			c.framework = &o.Framework
 		},
@@ -190,6 +208,30 @@ func (o Options) GetChangeSet(flags *pflag.FlagSet) *ChangeSet {
 	return &cs
 }
 
+// AppName returns the name of the app being deployed.
+func (c *ChangeSet) AppName() string {
+	return c.appName
+}
+
+// RemovedFields returns fields the app has that the deployment removes because an application.yaml file omits them.
+func (c *ChangeSet) RemovedFields(app ketchv1.App) []string {
+	set := map[string]bool{
+		"cnames":             len(app.Spec.Ingress.Cnames) > 0,
+		"labels":             len(app.Spec.Labels) > 0,
+		"annotations":        len(app.Spec.Annotations) > 0,
+		"serviceBindings":    len(app.Spec.ServiceBindings) > 0,
+		"environment":        len(app.Spec.Env) > 0,
+		"serviceAccountName": app.Spec.ServiceAccountName != "",
+	}
+	var removed []string
+	for _, field := range c.omittedFields {
+		if set[field] {
+			removed = append(removed, field)
+		}
+	}
+	return removed
+}
+
 func (c *ChangeSet) getDescription() (string, error) {
 	if c.description == nil {
 		return "", newMissingError(FlagDescription)
@@ -275,15 +317,29 @@ func (c *ChangeSet) getStepWeight() (uint8, error) {
 	return uint8(100 / steps), nil
 }
 
+// getEnvironments returns env variables of the env file overridden by env variables of the env flag.
 func (c *ChangeSet) getEnvironments() ([]ketchv1.Env, error) {
-	if c.envs == nil {
+	if c.envs == nil && c.envFile == nil {
 		return nil, newMissingError(FlagEnvironment)
 	}
-	envs, err := utils.MakeEnvironments(*c.envs)
+	var envs []ketchv1.Env
+	if c.envFile != nil {
+		fileEnvs, err := utils.ReadEnvFile(*c.envFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", newInvalidValueError(FlagEnvFile), err)
+		}
+		envs = fileEnvs
+	}
+	if c.envs == nil {
+		return envs, nil
+	}
+	flagEnvs, err := utils.MakeEnvironments(*c.envs)
 	if err != nil {
 		return nil, newInvalidValueError(FlagEnvironment)
 	}
-	return envs, nil
+	app := ketchv1.App{Spec: ketchv1.AppSpec{Env: envs}}
+	app.SetEnvs(flagEnvs)
+	return app.Spec.Env, nil
 }
 
 func (c *ChangeSet) getWait() (bool, error) {
@@ -326,6 +382,41 @@ func (c *ChangeSet) getBuilder(spec ketchv1.AppSpec) string {
 	return *c.builder
 }
 
+func (c *ChangeSet) getCnames() (ketchv1.CnameList, error) {
+	if c.cname == nil {
+		return nil, newMissingError("cnames")
+	}
+	return *c.cname, nil
+}
+
+func (c *ChangeSet) getLabels() ([]ketchv1.MetadataItem, error) {
+	if c.labels == nil {
+		return nil, newMissingError("labels")
+	}
+	return *c.labels, nil
+}
+
+func (c *ChangeSet) getAnnotations() ([]ketchv1.MetadataItem, error) {
+	if c.annotations == nil {
+		return nil, newMissingError("annotations")
+	}
+	return *c.annotations, nil
+}
+
+func (c *ChangeSet) getServiceBindings() ([]ketchv1.ServiceBinding, error) {
+	if c.serviceBindings == nil {
+		return nil, newMissingError("serviceBindings")
+	}
+	return *c.serviceBindings, nil
+}
+
+func (c *ChangeSet) getServiceAccountName() (string, error) {
+	if c.serviceAccountName == nil {
+		return "", newMissingError("serviceAccountName")
+	}
+	return *c.serviceAccountName, nil
+}
+
 func (c *ChangeSet) getUnits() (int, error) {
 	if c.units == nil {
 		return 1, nil
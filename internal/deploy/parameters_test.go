@@ -1,9 +1,14 @@
 package deploy
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/utils/conversions"
 )
 
 func intRef(i int) *int {
@@ -48,3 +53,53 @@ func TestChangeSet_getStepWeight(t *testing.T) {
 		})
 	}
 }
+
+func TestChangeSet_getEnvironments(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), ".env")
+	require.Nil(t, os.WriteFile(envFile, []byte("LOG_LEVEL=debug\nGREETING=\"hello\\nworld\"\n"), 0600))
+
+	tests := []struct {
+		name    string
+		set     ChangeSet
+		want    []ketchv1.Env
+		wantErr string
+	}{
+		{
+			name: "env flag",
+			set:  ChangeSet{envs: &[]string{"LOG_LEVEL=warn"}},
+			want: []ketchv1.Env{{Name: "LOG_LEVEL", Value: "warn"}},
+		},
+		{
+			name: "env file",
+			set:  ChangeSet{envFile: &envFile},
+			want: []ketchv1.Env{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "GREETING", Value: "hello\nworld"}},
+		},
+		{
+			name: "env flag overrides env file",
+			set:  ChangeSet{envFile: &envFile, envs: &[]string{"LOG_LEVEL=warn", "PORT=8080"}},
+			want: []ketchv1.Env{{Name: "LOG_LEVEL", Value: "warn"}, {Name: "GREETING", Value: "hello\nworld"}, {Name: "PORT", Value: "8080"}},
+		},
+		{
+			name:    "error - missing env file",
+			set:     ChangeSet{envFile: conversions.StrPtr(filepath.Join(t.TempDir(), "missing.env"))},
+			wantErr: `"env-file" invalid value`,
+		},
+		{
+			name:    "error - no envs",
+			set:     ChangeSet{},
+			wantErr: `"env" missing`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envs, err := tt.set.getEnvironments()
+			if len(tt.wantErr) > 0 {
+				require.NotNil(t, err)
+				require.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, envs)
+		})
+	}
+}
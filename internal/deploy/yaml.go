@@ -2,8 +2,8 @@ package deploy
 
 import (
 	"fmt"
-	"os"
 
+	v1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/yaml"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
@@ -13,31 +13,48 @@ import (
 )
 
 // Application represents the fields in an application.yaml file that will be
-// transitioned to a ChangeSet.
+// transitioned to a ChangeSet. An application.yaml file describes the whole app,
+// so cnames, labels, annotations, service bindings, environment and service account omitted from the file
+// are removed from the app. Healthcheck and hooks omitted from the file are read from ketch.yaml of the source directory.
 type Application struct {
-	Version        *string   `json:"version,omitempty"`
-	Type           *string   `json:"type"`
-	Name           *string   `json:"name"`
-	Image          *string   `json:"image,omitempty"`
-	Framework      *string   `json:"framework"`
-	Description    *string   `json:"description,omitempty"`
-	Environment    []string  `json:"environment,omitempty"`
-	RegistrySecret *string   `json:"registrySecret,omitempty"`
-	Builder        *string   `json:"builder,omitempty"`
-	BuildPacks     []string  `json:"buildPacks,omitempty"`
-	Processes      []Process `json:"processes,omitempty"`
-	CName          *CName    `json:"cname,omitempty"`
+	Version            *string                       `json:"version,omitempty"`
+	Type               *string                       `json:"type"`
+	Name               *string                       `json:"name"`
+	Image              *string                       `json:"image,omitempty"`
+	Framework          *string                       `json:"framework"`
+	Description        *string                       `json:"description,omitempty"`
+	Environment        []string                      `json:"environment,omitempty"`
+	RegistrySecret     *string                       `json:"registrySecret,omitempty"`
+	Builder            *string                       `json:"builder,omitempty"`
+	BuildPacks         []string                      `json:"buildPacks,omitempty"`
+	Processes          []Process                     `json:"processes,omitempty"`
+	CName              *CName                        `json:"cname,omitempty"`
+	CNames             []CName                       `json:"cnames,omitempty"`
+	Labels             []ketchv1.MetadataItem        `json:"labels,omitempty"`
+	Annotations        []ketchv1.MetadataItem        `json:"annotations,omitempty"`
+	ServiceBindings    []ketchv1.ServiceBinding      `json:"serviceBindings,omitempty"`
+	ServiceAccountName *string                       `json:"serviceAccountName,omitempty"`
+	Healthcheck        *ketchv1.KetchYamlHealthcheck `json:"healthcheck,omitempty"`
+	Hooks              *Hooks                        `json:"hooks,omitempty"`
 }
 
 type Process struct {
-	Name  string `json:"name"`  // required
-	Units *int   `json:"units"` // default 1
+	Name            string                                  `json:"name"`  // required
+	Units           *int                                    `json:"units"` // default 1
+	Environment     []string                                `json:"environment,omitempty"`
+	Ports           []Port                                  `json:"ports,omitempty"`
+	Internal        *ketchv1.KetchYamlProcessInternalConfig `json:"internal,omitempty"`
+	Resources       *v1.ResourceRequirements                `json:"resources,omitempty"`
+	Volumes         []v1.Volume                             `json:"volumes,omitempty"`
+	VolumeMounts    []v1.VolumeMount                        `json:"volumeMounts,omitempty"`
+	SecurityContext *v1.SecurityContext                     `json:"securityContext,omitempty"`
 }
 
 type Port struct {
-	Protocol   string `json:"protocol"`
-	Port       int    `json:"port"`
-	TargetPort int    `json:"targetPort"`
+	Name       string `json:"name,omitempty"`
+	Protocol   string `json:"protocol,omitempty"`
+	Port       int    `json:"port,omitempty"`
+	TargetPort int    `json:"targetPort,omitempty"`
 }
 
 type Hooks struct {
@@ -45,13 +62,14 @@ type Hooks struct {
 }
 
 type Restart struct {
-	Before string `json:"before"`
-	After  string `json:"after"`
+	Before []string `json:"before,omitempty"`
+	After  []string `json:"after,omitempty"`
 }
 
 type CName struct {
-	DNSName string `json:"dnsName"`
-	Secure  bool   `json:"secure"`
+	DNSName    string `json:"dnsName"`
+	Secure     bool   `json:"secure"`
+	SecretName string `json:"secretName,omitempty"`
 }
 
 const (
@@ -60,37 +78,76 @@ const (
 	typeApplication = "Application"
 )
 
-// GetChangeSetFromYaml reads an application.yaml file and returns a ChangeSet
+// GetChangeSetFromYaml reads an application.yaml file merged with its overlay and returns a ChangeSet
 // from the file's values.
 func (o *Options) GetChangeSetFromYaml(filename string) (*ChangeSet, error) {
-	var application Application
-	b, err := os.ReadFile(filename)
+	b, err := o.ReadApplicationYaml(filename)
 	if err != nil {
 		return nil, err
 	}
-	err = yaml.Unmarshal(b, &application)
+	return o.GetChangeSetFromYamlData(b)
+}
+
+// GetChangeSetFromYamlData returns a ChangeSet from the content of an application.yaml file.
+func (o *Options) GetChangeSetFromYamlData(b []byte) (*ChangeSet, error) {
+	var application Application
+	err := yaml.Unmarshal(b, &application)
 	if err != nil {
 		return nil, err
 	}
 
-	var envs []ketchv1.Env
+	if application.Name == nil {
+		return nil, errors.New("missing required field name")
+	}
 	if application.Environment != nil {
-		envs, err = utils.MakeEnvironments(application.Environment)
-		if err != nil {
+		if _, err = utils.MakeEnvironments(application.Environment); err != nil {
 			return nil, err
 		}
 	}
 	// processes
 	var processes []ketchv1.ProcessSpec
-	if application.Processes != nil {
-		for _, process := range application.Processes {
-			processes = append(processes, ketchv1.ProcessSpec{
-				Name:  process.Name,
-				Units: process.Units,
-				Env:   envs,
-			})
+	for _, process := range application.Processes {
+		processSpec := ketchv1.ProcessSpec{
+			Name:            process.Name,
+			Units:           process.Units,
+			Resources:       process.Resources,
+			Volumes:         process.Volumes,
+			VolumeMounts:    process.VolumeMounts,
+			SecurityContext: process.SecurityContext,
 		}
-
+		if process.Environment != nil {
+			if processSpec.Env, err = utils.MakeEnvironments(process.Environment); err != nil {
+				return nil, fmt.Errorf("process %q: %w", process.Name, err)
+			}
+		}
+		processes = append(processes, processSpec)
+	}
+	serviceBindings := []ketchv1.ServiceBinding{}
+	for _, binding := range application.ServiceBindings {
+		if err = binding.Validate(); err != nil {
+			return nil, err
+		}
+		if binding.Mode == "" {
+			binding.Mode = ketchv1.ServiceBindingModeMount
+		}
+		serviceBindings = append(serviceBindings, binding)
+	}
+	cnames := application.getCNames()
+	labels := []ketchv1.MetadataItem{}
+	if application.Labels != nil {
+		labels = application.Labels
+	}
+	annotations := []ketchv1.MetadataItem{}
+	if application.Annotations != nil {
+		annotations = application.Annotations
+	}
+	environment := []string{}
+	if application.Environment != nil {
+		environment = application.Environment
+	}
+	serviceAccountName := ""
+	if application.ServiceAccountName != nil {
+		serviceAccountName = *application.ServiceAccountName
 	}
 	c := &ChangeSet{
 		appName:              *application.Name,
@@ -101,18 +158,20 @@ func (o *Options) GetChangeSetFromYaml(filename string) (*ChangeSet, error) {
 		framework:            application.Framework,
 		dockerRegistrySecret: application.RegistrySecret,
 		builder:              application.Builder,
+		serviceAccountName:   &serviceAccountName,
+		cname:                &cnames,
+		labels:               &labels,
+		annotations:          &annotations,
+		serviceBindings:      &serviceBindings,
+		envs:                 &environment,
+		ketchYamlData:        application.getKetchYamlData(),
+		omittedFields:        application.omittedFields(),
 		timeout:              &o.Timeout,
 		wait:                 &o.Wait,
 	}
 	if o.AppSourcePath != "" {
 		c.sourcePath = &o.AppSourcePath
 	}
-	if application.CName != nil {
-		c.cname = &ketchv1.CnameList{{Name: application.CName.DNSName, Secure: application.CName.Secure}}
-	}
-	if application.Environment != nil {
-		c.envs = &application.Environment
-	}
 	if application.BuildPacks != nil {
 		c.buildPacks = &application.BuildPacks
 	}
@@ -123,6 +182,30 @@ func (o *Options) GetChangeSetFromYaml(filename string) (*ChangeSet, error) {
 	return c, c.validate()
 }
 
+// omittedFields returns fields of the app the file omits, they are removed from the app.
+func (a Application) omittedFields() []string {
+	var fields []string
+	if a.CName == nil && a.CNames == nil {
+		fields = append(fields, "cnames")
+	}
+	if a.Labels == nil {
+		fields = append(fields, "labels")
+	}
+	if a.Annotations == nil {
+		fields = append(fields, "annotations")
+	}
+	if a.ServiceBindings == nil {
+		fields = append(fields, "serviceBindings")
+	}
+	if a.Environment == nil {
+		fields = append(fields, "environment")
+	}
+	if a.ServiceAccountName == nil {
+		fields = append(fields, "serviceAccountName")
+	}
+	return fields
+}
+
 // apply defaults sets default values for a ChangeSet
 func (c *ChangeSet) applyDefaults() {
 	if c.appVersion == nil {
@@ -153,12 +236,61 @@ func (c *ChangeSet) validate() error {
 	if c.appName == "" {
 		return errors.New("missing required field name")
 	}
-	if c.sourcePath == nil && c.processes != nil {
-		return errors.New("running defined processes require a sourcePath")
-	}
 	return nil
 }
 
+// getCNames returns CNAMEs of both the cname and cnames fields.
+func (a *Application) getCNames() ketchv1.CnameList {
+	cnames := ketchv1.CnameList{}
+	if a.CName != nil {
+		cnames = append(cnames, a.CName.toCname())
+	}
+	for _, cname := range a.CNames {
+		cnames = append(cnames, cname.toCname())
+	}
+	return cnames
+}
+
+// getKetchYamlData returns hooks, healthcheck and ports of processes in the format of ketch.yaml, nil is returned if none is set.
+func (a *Application) getKetchYamlData() *ketchv1.KetchYamlData {
+	var data ketchv1.KetchYamlData
+	if a.Hooks != nil {
+		data.Hooks = &ketchv1.KetchYamlHooks{
+			Restart: ketchv1.KetchYamlRestartHooks{
+				Before: a.Hooks.Restart.Before,
+				After:  a.Hooks.Restart.After,
+			},
+		}
+	}
+	data.Healthcheck = a.Healthcheck
+	for _, process := range a.Processes {
+		if len(process.Ports) == 0 && process.Internal == nil {
+			continue
+		}
+		config := ketchv1.KetchYamlProcessConfig{Internal: process.Internal}
+		for _, port := range process.Ports {
+			config.Ports = append(config.Ports, ketchv1.KetchYamlProcessPortConfig{
+				Name:       port.Name,
+				Protocol:   port.Protocol,
+				Port:       port.Port,
+				TargetPort: port.TargetPort,
+			})
+		}
+		if data.Kubernetes == nil {
+			data.Kubernetes = &ketchv1.KetchYamlKubernetesConfig{Processes: map[string]ketchv1.KetchYamlProcessConfig{}}
+		}
+		data.Kubernetes.Processes[process.Name] = config
+	}
+	if data.Hooks == nil && data.Healthcheck == nil && data.Kubernetes == nil {
+		return nil
+	}
+	return &data
+}
+
+func (c CName) toCname() ketchv1.Cname {
+	return ketchv1.Cname{Name: c.DNSName, Secure: c.Secure, SecretName: c.SecretName}
+}
+
 // GetApplicationFromKetchApp takes an App parameter and returns a yaml-file friendly Application
 func GetApplicationFromKetchApp(app ketchv1.App) *Application {
 	application := &Application{
@@ -172,18 +304,39 @@ func GetApplicationFromKetchApp(app ketchv1.App) *Application {
 	if deployment != nil {
 		application.Image = conversions.StrPtr(deployment.Image)
 		for _, process := range deployment.Processes {
-			application.Processes = append(application.Processes, Process{
-				Name:  process.Name,
-				Units: process.Units,
-			})
+			application.Processes = append(application.Processes, getProcessFromKetchProcess(process, deployment.KetchYaml))
+		}
+		if deployment.KetchYaml != nil {
+			application.Healthcheck = deployment.KetchYaml.Healthcheck
+			if hooks := deployment.KetchYaml.Hooks; hooks != nil {
+				application.Hooks = &Hooks{
+					Restart: Restart{
+						Before: hooks.Restart.Before,
+						After:  hooks.Restart.After,
+					},
+				}
+			}
 		}
 	}
 
-	if len(app.Spec.Ingress.Cnames) > 0 {
-		application.CName = &CName{
-			DNSName: app.Spec.Ingress.Cnames[0].Name,
-			Secure:  app.Spec.Ingress.Cnames[0].Secure,
-		}
+	for _, cname := range app.Spec.Ingress.Cnames {
+		application.CNames = append(application.CNames, CName{
+			DNSName:    cname.Name,
+			Secure:     cname.Secure,
+			SecretName: cname.SecretName,
+		})
+	}
+	if len(app.Spec.Labels) > 0 {
+		application.Labels = app.Spec.Labels
+	}
+	if len(app.Spec.Annotations) > 0 {
+		application.Annotations = app.Spec.Annotations
+	}
+	if len(app.Spec.ServiceBindings) > 0 {
+		application.ServiceBindings = app.Spec.ServiceBindings
+	}
+	if app.Spec.ServiceAccountName != "" {
+		application.ServiceAccountName = &app.Spec.ServiceAccountName
 	}
 	if app.Spec.Description != "" {
 		application.Description = &app.Spec.Description
@@ -206,6 +359,38 @@ func GetApplicationFromKetchApp(app ketchv1.App) *Application {
 	return application
 }
 
+// getProcessFromKetchProcess returns a yaml-file friendly Process with ports of the process found in ketchYaml
+func getProcessFromKetchProcess(process ketchv1.ProcessSpec, ketchYaml *ketchv1.KetchYamlData) Process {
+	p := Process{
+		Name:            process.Name,
+		Units:           process.Units,
+		Resources:       process.Resources,
+		Volumes:         process.Volumes,
+		VolumeMounts:    process.VolumeMounts,
+		SecurityContext: process.SecurityContext,
+	}
+	for _, env := range process.Env {
+		p.Environment = append(p.Environment, fmt.Sprintf("%s=%s", env.Name, env.Value))
+	}
+	if ketchYaml == nil || ketchYaml.Kubernetes == nil {
+		return p
+	}
+	config, ok := ketchYaml.Kubernetes.Processes[process.Name]
+	if !ok {
+		return p
+	}
+	p.Internal = config.Internal
+	for _, port := range config.Ports {
+		p.Ports = append(p.Ports, Port{
+			Name:       port.Name,
+			Protocol:   port.Protocol,
+			Port:       port.Port,
+			TargetPort: port.TargetPort,
+		})
+	}
+	return p
+}
+
 // getLatestDeployment returns the AppDeploymentSpec of the highest Version or nil
 func getLatestDeployment(deployments []ketchv1.AppDeploymentSpec) *ketchv1.AppDeploymentSpec {
 	if len(deployments) == 0 {
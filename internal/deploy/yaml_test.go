@@ -2,9 +2,12 @@ package deploy
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
@@ -38,6 +41,8 @@ processes:
     units: 1
   - name: worker
     units: 1
+    environment:
+      - QUEUE=jobs
 cname:
   dnsName: test.10.10.10.20`,
 			options: &Options{
@@ -63,34 +68,25 @@ cname:
 					{
 						Name:  "web",
 						Units: conversions.IntPtr(1),
-						Env: []ketchv1.Env{
-							{
-								Name:  "PORT",
-								Value: "6666",
-							},
-							{
-								Name:  "FOO",
-								Value: "bar",
-							},
-						},
 					},
 					{
 						Name:  "worker",
 						Units: conversions.IntPtr(1),
 						Env: []ketchv1.Env{
 							{
-								Name:  "PORT",
-								Value: "6666",
-							},
-							{
-								Name:  "FOO",
-								Value: "bar",
+								Name:  "QUEUE",
+								Value: "jobs",
 							},
 						},
 					},
 				},
-				appVersion: conversions.StrPtr("v1"),
-				appType:    conversions.StrPtr("Application"),
+				appVersion:         conversions.StrPtr("v1"),
+				appType:            conversions.StrPtr("Application"),
+				labels:             &[]ketchv1.MetadataItem{},
+				annotations:        &[]ketchv1.MetadataItem{},
+				serviceBindings:    &[]ketchv1.ServiceBinding{},
+				serviceAccountName: conversions.StrPtr(""),
+				omittedFields:      []string{"labels", "annotations", "serviceBindings", "serviceAccountName"},
 			},
 		},
 		{
@@ -108,6 +104,13 @@ image: gcr.io/kubernetes/sample-app:latest`,
 				appType:            conversions.StrPtr("Application"),
 				timeout:            conversions.StrPtr(""),
 				wait:               conversions.BoolPtr(false),
+				cname:              &ketchv1.CnameList{},
+				labels:             &[]ketchv1.MetadataItem{},
+				annotations:        &[]ketchv1.MetadataItem{},
+				serviceBindings:    &[]ketchv1.ServiceBinding{},
+				envs:               &[]string{},
+				serviceAccountName: conversions.StrPtr(""),
+				omittedFields:      []string{"cnames", "labels", "annotations", "serviceBindings", "environment", "serviceAccountName"},
 			},
 		},
 		{
@@ -118,7 +121,7 @@ image: gcr.io/kubernetes/sample-app:latest`,
 			errStr:  "missing required field framework",
 		},
 		{
-			description: "validation error - processes without sourcePath",
+			description: "success - processes without sourcePath",
 			yaml: `name: test
 framework: myframework
 image: gcr.io/kubernetes/sample-app:latest
@@ -126,7 +129,146 @@ processes:
   - name: web
     cmd: python app.py`,
 			options: &Options{},
-			errStr:  "running defined processes require a sourcePath",
+			changeSet: &ChangeSet{
+				appName:            "test",
+				yamlStrictDecoding: true,
+				image:              conversions.StrPtr("gcr.io/kubernetes/sample-app:latest"),
+				framework:          conversions.StrPtr("myframework"),
+				appVersion:         conversions.StrPtr("v1"),
+				appType:            conversions.StrPtr("Application"),
+				timeout:            conversions.StrPtr(""),
+				wait:               conversions.BoolPtr(false),
+				processes: &[]ketchv1.ProcessSpec{
+					{
+						Name:  "web",
+						Units: conversions.IntPtr(1),
+					},
+				},
+				cname:              &ketchv1.CnameList{},
+				labels:             &[]ketchv1.MetadataItem{},
+				annotations:        &[]ketchv1.MetadataItem{},
+				serviceBindings:    &[]ketchv1.ServiceBinding{},
+				envs:               &[]string{},
+				serviceAccountName: conversions.StrPtr(""),
+				omittedFields:      []string{"cnames", "labels", "annotations", "serviceBindings", "environment", "serviceAccountName"},
+			},
+		},
+		{
+			description: "success - all app features",
+			yaml: `name: test
+framework: myframework
+image: gcr.io/kubernetes/sample-app:latest
+serviceAccountName: test-sa
+cname:
+  dnsName: test.com
+cnames:
+  - dnsName: secure.test.com
+    secure: true
+    secretName: test-tls
+labels:
+  - apply:
+      team: payments
+    processName: web
+annotations:
+  - apply:
+      theketch.io/owner: payments
+    target:
+      apiVersion: v1
+      kind: Service
+healthcheck:
+  path: /healthz
+  allowed_failures: 3
+hooks:
+  restart:
+    before:
+      - ./migrate
+    after:
+      - ./warmup
+processes:
+  - name: web
+    units: 2
+    environment:
+      - MODE=web
+    ports:
+      - name: http
+        protocol: TCP
+        port: 80
+        targetPort: 8080
+    internal:
+      port: 80
+      publish_url: true
+    resources:
+      limits:
+        cpu: 500m
+    volumes:
+      - name: cache
+        emptyDir: {}
+    volumeMounts:
+      - name: cache
+        mountPath: /cache
+    securityContext:
+      runAsNonRoot: true
+serviceBindings:
+  - name: db
+    secretName: postgres
+  - name: cache
+    secretName: redis
+    mode: env`,
+			options: &Options{},
+			changeSet: &ChangeSet{
+				appName:            "test",
+				yamlStrictDecoding: true,
+				image:              conversions.StrPtr("gcr.io/kubernetes/sample-app:latest"),
+				framework:          conversions.StrPtr("myframework"),
+				appVersion:         conversions.StrPtr("v1"),
+				appType:            conversions.StrPtr("Application"),
+				timeout:            conversions.StrPtr(""),
+				wait:               conversions.BoolPtr(false),
+				serviceAccountName: conversions.StrPtr("test-sa"),
+				omittedFields:      []string{"environment"},
+				cname: &ketchv1.CnameList{
+					{Name: "test.com"},
+					{Name: "secure.test.com", Secure: true, SecretName: "test-tls"},
+				},
+				labels: &[]ketchv1.MetadataItem{
+					{Apply: map[string]string{"team": "payments"}, ProcessName: "web"},
+				},
+				annotations: &[]ketchv1.MetadataItem{
+					{Apply: map[string]string{"theketch.io/owner": "payments"}, Target: ketchv1.Target{APIVersion: "v1", Kind: "Service"}},
+				},
+				serviceBindings: &[]ketchv1.ServiceBinding{
+					{Name: "db", SecretName: "postgres", Mode: ketchv1.ServiceBindingModeMount},
+					{Name: "cache", SecretName: "redis", Mode: ketchv1.ServiceBindingModeEnv},
+				},
+				processes: &[]ketchv1.ProcessSpec{
+					{
+						Name:      "web",
+						Units:     conversions.IntPtr(2),
+						Env:       []ketchv1.Env{{Name: "MODE", Value: "web"}},
+						Resources: &corev1.ResourceRequirements{Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")}},
+						Volumes: []corev1.Volume{
+							{Name: "cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+						},
+						VolumeMounts:    []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}},
+						SecurityContext: &corev1.SecurityContext{RunAsNonRoot: conversions.BoolPtr(true)},
+					},
+				},
+				ketchYamlData: &ketchv1.KetchYamlData{
+					Hooks: &ketchv1.KetchYamlHooks{
+						Restart: ketchv1.KetchYamlRestartHooks{Before: []string{"./migrate"}, After: []string{"./warmup"}},
+					},
+					Healthcheck: &ketchv1.KetchYamlHealthcheck{Path: "/healthz", AllowedFailures: 3},
+					Kubernetes: &ketchv1.KetchYamlKubernetesConfig{
+						Processes: map[string]ketchv1.KetchYamlProcessConfig{
+							"web": {
+								Ports:    []ketchv1.KetchYamlProcessPortConfig{{Name: "http", Protocol: "TCP", Port: 80, TargetPort: 8080}},
+								Internal: &ketchv1.KetchYamlProcessInternalConfig{Port: 80, PublishURL: true},
+							},
+						},
+					},
+				},
+				envs: &[]string{},
+			},
 		},
 		{
 			description: "success - use appUnits as process.units when units are not specified",
@@ -166,8 +308,15 @@ processes:
 						Units: conversions.IntPtr(1),
 					},
 				},
-				appVersion: conversions.StrPtr("v1"),
-				appType:    conversions.StrPtr("Application"),
+				appVersion:         conversions.StrPtr("v1"),
+				appType:            conversions.StrPtr("Application"),
+				cname:              &ketchv1.CnameList{},
+				labels:             &[]ketchv1.MetadataItem{},
+				annotations:        &[]ketchv1.MetadataItem{},
+				serviceBindings:    &[]ketchv1.ServiceBinding{},
+				envs:               &[]string{},
+				serviceAccountName: conversions.StrPtr(""),
+				omittedFields:      []string{"cnames", "labels", "annotations", "serviceBindings", "environment", "serviceAccountName"},
 			},
 		},
 		{
@@ -186,6 +335,13 @@ image: gcr.io/kubernetes/sample-app:latest
 				appType:            conversions.StrPtr("Application"),
 				timeout:            conversions.StrPtr(""),
 				wait:               conversions.BoolPtr(false),
+				cname:              &ketchv1.CnameList{},
+				labels:             &[]ketchv1.MetadataItem{},
+				annotations:        &[]ketchv1.MetadataItem{},
+				serviceBindings:    &[]ketchv1.ServiceBinding{},
+				envs:               &[]string{},
+				serviceAccountName: conversions.StrPtr(""),
+				omittedFields:      []string{"cnames", "labels", "annotations", "serviceBindings", "environment", "serviceAccountName"},
 			},
 		},
 		{
@@ -199,6 +355,17 @@ environment:
 			options: &Options{},
 			errStr:  "env variables should have NAME=VALUE format",
 		},
+		{
+			description: "error - invalid service binding name",
+			yaml: `name: test
+framework: myframework
+image: gcr.io/kubernetes/sample-app:latest
+serviceBindings:
+  - name: Postgres_DB
+    secretName: postgres`,
+			options: &Options{},
+			errStr:  "service binding name must be a DNS label",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.description, func(t *testing.T) {
@@ -220,6 +387,28 @@ environment:
 	}
 }
 
+func TestGetChangeSetFromYamlReadsKetchYaml(t *testing.T) {
+	sourcePath := t.TempDir()
+	ketchYaml := `hooks:
+  restart:
+    before:
+      - make migrate`
+	require.Nil(t, os.WriteFile(filepath.Join(sourcePath, "ketch.yaml"), []byte(ketchYaml), 0644))
+	application := filepath.Join(t.TempDir(), "application.yaml")
+	require.Nil(t, os.WriteFile(application, []byte(`name: test
+framework: myframework
+image: gcr.io/kubernetes/sample-app:latest`), 0644))
+
+	cs, err := (&Options{AppSourcePath: sourcePath}).GetChangeSetFromYaml(application)
+	require.Nil(t, err)
+	// hooks and healthcheck omitted from the file are read from ketch.yaml of the source directory
+	got, err := cs.getKetchYaml()
+	require.Nil(t, err)
+	require.Equal(t, &ketchv1.KetchYamlData{
+		Hooks: &ketchv1.KetchYamlHooks{Restart: ketchv1.KetchYamlRestartHooks{Before: []string{"make migrate"}}},
+	}, got)
+}
+
 func TestGetApplicationFromKetchApp(t *testing.T) {
 	tests := []struct {
 		description string
@@ -289,8 +478,9 @@ func TestGetApplicationFromKetchApp(t *testing.T) {
 				RegistrySecret: conversions.StrPtr("a_secret"),
 				Builder:        conversions.StrPtr("builder"),
 				BuildPacks:     []string{"test/buildpack"},
-				CName: &CName{
-					DNSName: "test.com",
+				CNames: []CName{
+					{DNSName: "test.com"},
+					{DNSName: "another.com"},
 				},
 				Processes: []Process{
 					{
@@ -308,6 +498,69 @@ func TestGetApplicationFromKetchApp(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "process settings, hooks, healthcheck, ports and metadata",
+			app: ketchv1.App{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "test",
+				},
+				Spec: ketchv1.AppSpec{
+					Framework:          "myframework",
+					ServiceAccountName: "test-sa",
+					Labels:             []ketchv1.MetadataItem{{Apply: map[string]string{"team": "payments"}}},
+					Annotations:        []ketchv1.MetadataItem{{Apply: map[string]string{"theketch.io/owner": "payments"}}},
+					Deployments: []ketchv1.AppDeploymentSpec{
+						{
+							Version: ketchv1.DeploymentVersion(1),
+							Image:   "gcr.io/shipa-ci/sample-go-app:latest",
+							Processes: []ketchv1.ProcessSpec{
+								{
+									Name:            "web",
+									Units:           conversions.IntPtr(2),
+									Env:             []ketchv1.Env{{Name: "MODE", Value: "web"}},
+									SecurityContext: &corev1.SecurityContext{RunAsNonRoot: conversions.BoolPtr(true)},
+								},
+							},
+							KetchYaml: &ketchv1.KetchYamlData{
+								Hooks: &ketchv1.KetchYamlHooks{
+									Restart: ketchv1.KetchYamlRestartHooks{Before: []string{"./migrate"}},
+								},
+								Healthcheck: &ketchv1.KetchYamlHealthcheck{Path: "/healthz"},
+								Kubernetes: &ketchv1.KetchYamlKubernetesConfig{
+									Processes: map[string]ketchv1.KetchYamlProcessConfig{
+										"web": {Ports: []ketchv1.KetchYamlProcessPortConfig{{Protocol: "TCP", Port: 80, TargetPort: 8080}}},
+									},
+								},
+							},
+						},
+					},
+					Ingress:         ketchv1.IngressSpec{Cnames: ketchv1.CnameList{{Name: "test.com", Secure: true, SecretName: "test-tls"}}},
+					ServiceBindings: []ketchv1.ServiceBinding{{Name: "db", SecretName: "postgres", Mode: ketchv1.ServiceBindingModeMount}},
+				},
+			},
+			application: &Application{
+				Type:               conversions.StrPtr(typeApplication),
+				Name:               conversions.StrPtr("test"),
+				Image:              conversions.StrPtr("gcr.io/shipa-ci/sample-go-app:latest"),
+				Framework:          conversions.StrPtr("myframework"),
+				ServiceAccountName: conversions.StrPtr("test-sa"),
+				Labels:             []ketchv1.MetadataItem{{Apply: map[string]string{"team": "payments"}}},
+				Annotations:        []ketchv1.MetadataItem{{Apply: map[string]string{"theketch.io/owner": "payments"}}},
+				CNames:             []CName{{DNSName: "test.com", Secure: true, SecretName: "test-tls"}},
+				ServiceBindings:    []ketchv1.ServiceBinding{{Name: "db", SecretName: "postgres", Mode: ketchv1.ServiceBindingModeMount}},
+				Healthcheck:        &ketchv1.KetchYamlHealthcheck{Path: "/healthz"},
+				Hooks:              &Hooks{Restart: Restart{Before: []string{"./migrate"}}},
+				Processes: []Process{
+					{
+						Name:            "web",
+						Units:           conversions.IntPtr(2),
+						Environment:     []string{"MODE=web"},
+						Ports:           []Port{{Protocol: "TCP", Port: 80, TargetPort: 8080}},
+						SecurityContext: &corev1.SecurityContext{RunAsNonRoot: conversions.BoolPtr(true)},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.description, func(t *testing.T) {
@@ -30,6 +30,7 @@ type Configuration struct {
 	StorageInstance      templates.Client
 
 	ctrlClient client.Client
+	kubeClient kubernetes.Interface
 }
 
 func (cfg *Configuration) Client() client.Client {
@@ -42,13 +43,20 @@ func (cfg *Configuration) Client() client.Client {
 	return cfg.ctrlClient
 }
 
+// Storage returns StorageInstance if it's set, otherwise templates are stored in the fake controller-runtime client.
 func (cfg *Configuration) Storage() templates.Client {
+	if cfg.StorageInstance == nil {
+		cfg.StorageInstance = templates.NewStorage(cfg.Client(), templates.KetchNamespace)
+	}
 	return cfg.StorageInstance
 }
 
 // KubernetesClient returns kubernetes typed client. It's used to work with standard kubernetes types.
 func (cfg *Configuration) KubernetesClient() kubernetes.Interface {
-	return kubeFake.NewSimpleClientset(cfg.KubeClientObjects...)
+	if cfg.kubeClient == nil {
+		cfg.kubeClient = kubeFake.NewSimpleClientset(cfg.KubeClientObjects...)
+	}
+	return cfg.kubeClient
 }
 
 // DynamicClient returns kubernetes dynamic client. It's used to work with CRDs for which we don't have go types like ClusterIssuer.
@@ -0,0 +1,84 @@
+package signature
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/authn/k8schain"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+// VerifyImagePolicy returns an error if the policy requires signatures and the image has no signature verified by the policy.
+// The image must be pinned to a digest, otherwise its tag could be pushed again after the signature is verified.
+// Options configure access to the registry of the image.
+func VerifyImagePolicy(ctx context.Context, policy *ketchv1.ImagePolicy, image string, options ...remote.Option) error {
+	if !policy.RequiresSignatures() {
+		return nil
+	}
+	if _, err := name.NewDigest(image); err != nil {
+		return fmt.Errorf("%w: %q must be pinned to a digest to verify its signature", ketchv1.ErrImageDigestRequired, image)
+	}
+	if _, err := Verify(ctx, image, policyOf(policy.Signatures), options...); err != nil {
+		return fmt.Errorf("%w: %v", ketchv1.ErrImageSignatureNotVerified, err)
+	}
+	return nil
+}
+
+func policyOf(p *ketchv1.SignaturePolicy) Policy {
+	policy := Policy{PublicKeys: p.PublicKeys}
+	for _, id := range p.Keyless {
+		policy.Keyless = append(policy.Keyless, Identity{
+			Issuer:           id.Issuer,
+			Subject:          id.Subject,
+			RootCertificates: id.RootCertificates,
+		})
+	}
+	return policy
+}
+
+// NewWorkloadVerifier returns a verifier of images of apps and jobs used by ketch's webhooks.
+// Registries are accessed with image pull secrets of apps, jobs have no image pull secrets,
+// so registries of job images are accessed anonymously.
+func NewWorkloadVerifier(clientset kubernetes.Interface) ketchv1.SignatureVerifier {
+	return func(ctx context.Context, obj runtime.Object, framework ketchv1.Framework, images []string) error {
+		var options []remote.Option
+		if secrets := imagePullSecrets(obj); len(secrets) > 0 {
+			keychain, err := k8schain.New(ctx, clientset, k8schain.Options{
+				Namespace:        framework.Spec.NamespaceName,
+				ImagePullSecrets: secrets,
+			})
+			if err != nil {
+				return fmt.Errorf("could not get keychain: %w", err)
+			}
+			options = append(options, remote.WithAuthFromKeychain(keychain))
+		}
+		for _, image := range images {
+			if err := VerifyImagePolicy(ctx, framework.Spec.ImagePolicy, image, options...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func imagePullSecrets(obj runtime.Object) []string {
+	app, ok := obj.(*ketchv1.App)
+	if !ok {
+		return nil
+	}
+	var secrets []string
+	if app.Spec.DockerRegistry.SecretName != "" {
+		secrets = append(secrets, app.Spec.DockerRegistry.SecretName)
+	}
+	for _, deployment := range app.Spec.Deployments {
+		for _, secret := range deployment.ImagePullSecrets {
+			secrets = append(secrets, secret.Name)
+		}
+	}
+	return secrets
+}
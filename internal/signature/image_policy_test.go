@@ -0,0 +1,74 @@
+package signature_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+	"github.com/theketchio/ketch/internal/signature"
+	"github.com/theketchio/ketch/internal/signature/signaturetest"
+)
+
+func TestNewWorkloadVerifier(t *testing.T) {
+	host := signaturetest.NewRegistry(t)
+	key, publicKey := signaturetest.NewKey(t)
+	signedTag := host + "/shipa/app:signed"
+	signedDigest := signaturetest.PushImage(t, signedTag)
+	signaturetest.Sign(t, signedTag, signedDigest, key, "")
+	signedImage := signedTag + "@" + signedDigest.String()
+	unsignedTag := host + "/shipa/app:unsigned"
+	unsignedImage := unsignedTag + "@" + signaturetest.PushImage(t, unsignedTag).String()
+	framework := ketchv1.Framework{
+		ObjectMeta: metav1.ObjectMeta{Name: "signed"},
+		Spec: ketchv1.FrameworkSpec{
+			NamespaceName: "ketch-signed",
+			ImagePolicy:   &ketchv1.ImagePolicy{Signatures: &ketchv1.SignaturePolicy{PublicKeys: []string{publicKey}}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		framework ketchv1.Framework
+		images    []string
+		wantErr   error
+	}{
+		{
+			name:      "signed image",
+			framework: framework,
+			images:    []string{signedImage},
+		},
+		{
+			name:      "image without signatures",
+			framework: framework,
+			images:    []string{signedImage, unsignedImage},
+			wantErr:   ketchv1.ErrImageSignatureNotVerified,
+		},
+		{
+			name:      "signed image that isn't pinned to a digest",
+			framework: framework,
+			images:    []string{signedTag},
+			wantErr:   ketchv1.ErrImageDigestRequired,
+		},
+		{
+			name:      "framework without signatures",
+			framework: ketchv1.Framework{ObjectMeta: metav1.ObjectMeta{Name: "open"}},
+			images:    []string{unsignedTag},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verify := signature.NewWorkloadVerifier(fake.NewSimpleClientset())
+			err := verify(context.Background(), &ketchv1.Job{}, tt.framework, tt.images)
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
@@ -0,0 +1,312 @@
+// Package signature verifies cosign signatures of container images.
+//
+// Signatures are read from the registry of an image the way cosign stores them: an image tagged
+// <algorithm>-<digest hex>.sig whose layers are simple signing payloads of the signed image digest,
+// with the signature and, for keyless signatures, the signing certificate in layer annotations.
+package signature
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	registryv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+const (
+	// SignatureAnnotation is the layer annotation with a base64 encoded signature of the layer's payload.
+	SignatureAnnotation = "dev.cosignproject.cosign/signature"
+	// CertificateAnnotation is the layer annotation with a PEM encoded certificate of a keyless signature.
+	CertificateAnnotation = "dev.sigstore.cosign/certificate"
+	// ChainAnnotation is the layer annotation with PEM encoded intermediate certificates of a keyless signature.
+	ChainAnnotation = "dev.sigstore.cosign/chain"
+
+	// SimpleSigningMediaType is the media type of layers with simple signing payloads.
+	SimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+
+	payloadType = "cosign container image signature"
+)
+
+var (
+	// ErrNoSignatures is returned when an image has no signatures in its registry.
+	ErrNoSignatures = errors.New("image has no signatures")
+	// ErrNoValidSignatures is returned when none of an image's signatures is verified by the policy.
+	ErrNoValidSignatures = errors.New("image has no signatures verified by the policy")
+
+	// oidcIssuerOID is the extension of fulcio certificates with the OIDC issuer of the identity as a raw string.
+	oidcIssuerOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	// oidcIssuerV2OID is the extension of fulcio certificates with the OIDC issuer of the identity as a DER encoded string.
+	oidcIssuerV2OID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// Policy describes signatures an image must have, an image is verified if any of its signatures
+// is verified by one of the public keys or keyless identities.
+type Policy struct {
+	// PublicKeys are PEM encoded ECDSA, RSA or ed25519 public keys.
+	PublicKeys []string
+	// Keyless are identities of certificates used to sign images.
+	Keyless []Identity
+}
+
+// Identity is an identity of keyless signatures.
+type Identity struct {
+	// Issuer is the OIDC issuer of the identity.
+	Issuer string
+	// Subject is the email or URI of the identity.
+	Subject string
+	// RootCertificates are PEM encoded certificates of authorities issuing signing certificates.
+	RootCertificates string
+}
+
+// Payload is a simple signing payload of a cosign signature.
+type Payload struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]interface{} `json:"optional"`
+}
+
+// NewPayload returns the simple signing payload cosign signs for the image digest.
+func NewPayload(repository string, digest registryv1.Hash) ([]byte, error) {
+	var p Payload
+	p.Critical.Identity.DockerReference = repository
+	p.Critical.Image.DockerManifestDigest = digest.String()
+	p.Critical.Type = payloadType
+	return json.Marshal(p)
+}
+
+// SignatureTag returns the tag of the image with signatures of the digest.
+func SignatureTag(repository name.Repository, digest registryv1.Hash) name.Tag {
+	return repository.Tag(fmt.Sprintf("%s-%s.sig", digest.Algorithm, digest.Hex))
+}
+
+type verifier struct {
+	keys       []crypto.PublicKey
+	identities []identity
+}
+
+type identity struct {
+	Identity
+	roots *x509.CertPool
+}
+
+// Validate returns an error if the policy has invalid public keys or root certificates.
+func (p Policy) Validate() error {
+	_, err := p.verifier()
+	return err
+}
+
+func (p Policy) verifier() (*verifier, error) {
+	v := &verifier{}
+	for i, key := range p.PublicKeys {
+		block, _ := pem.Decode([]byte(key))
+		if block == nil {
+			return nil, fmt.Errorf("public key %d is not PEM encoded", i+1)
+		}
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key %d: %w", i+1, err)
+		}
+		v.keys = append(v.keys, publicKey)
+	}
+	for _, id := range p.Keyless {
+		if id.Issuer == "" || id.Subject == "" {
+			return nil, errors.New("keyless identities require an issuer and a subject")
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM([]byte(id.RootCertificates)) {
+			return nil, fmt.Errorf("no root certificates of %q identity", id.Subject)
+		}
+		v.identities = append(v.identities, identity{Identity: id, roots: roots})
+	}
+	return v, nil
+}
+
+// Verify checks that the image has a signature verified by the policy and returns the verified digest.
+// Tags are resolved to digests, so the returned digest should be deployed to run the verified image.
+// Keyless certificates are checked against the time they were issued at because the transparency log isn't queried.
+func Verify(ctx context.Context, image string, policy Policy, options ...remote.Option) (registryv1.Hash, error) {
+	v, err := policy.verifier()
+	if err != nil {
+		return registryv1.Hash{}, err
+	}
+	ref, err := name.ParseReference(image)
+	if err != nil {
+		return registryv1.Hash{}, err
+	}
+	options = append(options, remote.WithContext(ctx))
+	var digest registryv1.Hash
+	if d, ok := ref.(name.Digest); ok {
+		if digest, err = registryv1.NewHash(d.DigestStr()); err != nil {
+			return registryv1.Hash{}, err
+		}
+	} else {
+		descriptor, err := remote.Head(ref, options...)
+		if err != nil {
+			return registryv1.Hash{}, fmt.Errorf("failed to resolve %q: %w", image, err)
+		}
+		digest = descriptor.Digest
+	}
+
+	signatures, err := remote.Image(SignatureTag(ref.Context(), digest), options...)
+	if err != nil {
+		var transportErr *transport.Error
+		if errors.As(err, &transportErr) && transportErr.StatusCode == http.StatusNotFound {
+			return registryv1.Hash{}, fmt.Errorf("%w: %s@%s", ErrNoSignatures, ref.Context(), digest)
+		}
+		return registryv1.Hash{}, fmt.Errorf("failed to get signatures of %q: %w", image, err)
+	}
+	manifest, err := signatures.Manifest()
+	if err != nil {
+		return registryv1.Hash{}, err
+	}
+	var reasons []string
+	for _, layer := range manifest.Layers {
+		err := v.verifyLayer(signatures, layer, digest)
+		if err == nil {
+			return digest, nil
+		}
+		reasons = append(reasons, err.Error())
+	}
+	return registryv1.Hash{}, fmt.Errorf("%w: %s@%s: %v", ErrNoValidSignatures, ref.Context(), digest, reasons)
+}
+
+func (v *verifier) verifyLayer(signatures registryv1.Image, layer registryv1.Descriptor, digest registryv1.Hash) error {
+	if layer.MediaType != SimpleSigningMediaType {
+		return fmt.Errorf("unexpected media type %q", layer.MediaType)
+	}
+	signature, err := base64.StdEncoding.DecodeString(layer.Annotations[SignatureAnnotation])
+	if err != nil || len(signature) == 0 {
+		return errors.New("missing signature")
+	}
+	l, err := signatures.LayerByDigest(layer.Digest)
+	if err != nil {
+		return err
+	}
+	rc, err := l.Compressed()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	payload, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+	var p Payload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+	if p.Critical.Type != payloadType || p.Critical.Image.DockerManifestDigest != digest.String() {
+		return errors.New("payload doesn't sign the image digest")
+	}
+
+	if cert := layer.Annotations[CertificateAnnotation]; cert != "" {
+		return v.verifyCertificate(cert, layer.Annotations[ChainAnnotation], payload, signature)
+	}
+	for _, key := range v.keys {
+		if verifySignature(key, payload, signature) {
+			return nil
+		}
+	}
+	return errors.New("signature isn't verified by any of the public keys")
+}
+
+func (v *verifier) verifyCertificate(certPEM, chainPEM string, payload, signature []byte) error {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		return errors.New("certificate is not PEM encoded")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return err
+	}
+	if !verifySignature(cert.PublicKey, payload, signature) {
+		return errors.New("signature isn't verified by the certificate")
+	}
+	intermediates := x509.NewCertPool()
+	intermediates.AppendCertsFromPEM([]byte(chainPEM))
+	issuer := certificateIssuer(cert)
+	for _, id := range v.identities {
+		if id.Issuer != issuer || !hasSubject(cert, id.Subject) {
+			continue
+		}
+		_, err := cert.Verify(x509.VerifyOptions{
+			Roots:         id.roots,
+			Intermediates: intermediates,
+			CurrentTime:   cert.NotBefore,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		})
+		if err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("certificate of %v issued by %q doesn't match any of the keyless identities", certificateSubjects(cert), issuer)
+}
+
+func verifySignature(key crypto.PublicKey, payload, signature []byte) bool {
+	hash := sha256.Sum256(payload)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(k, hash[:], signature)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], signature) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, payload, signature)
+	}
+	return false
+}
+
+func certificateIssuer(cert *x509.Certificate) string {
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oidcIssuerV2OID) {
+			var issuer string
+			if _, err := asn1.Unmarshal(ext.Value, &issuer); err == nil {
+				return issuer
+			}
+		}
+	}
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oidcIssuerOID) {
+			return string(ext.Value)
+		}
+	}
+	return ""
+}
+
+func hasSubject(cert *x509.Certificate, subject string) bool {
+	for _, s := range certificateSubjects(cert) {
+		if s == subject {
+			return true
+		}
+	}
+	return false
+}
+
+func certificateSubjects(cert *x509.Certificate) []string {
+	subjects := append([]string{}, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		subjects = append(subjects, uri.String())
+	}
+	return subjects
+}
@@ -0,0 +1,148 @@
+package signature_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/theketchio/ketch/internal/signature"
+	"github.com/theketchio/ketch/internal/signature/signaturetest"
+)
+
+func TestVerify(t *testing.T) {
+	host := signaturetest.NewRegistry(t)
+	ciKey, ciPublicKey := signaturetest.NewKey(t)
+	_, otherPublicKey := signaturetest.NewKey(t)
+	ca := signaturetest.NewCA(t)
+	otherCA := signaturetest.NewCA(t)
+	const issuer = "https://token.actions.githubusercontent.com"
+
+	tests := []struct {
+		name    string
+		sign    func(t *testing.T, image string)
+		policy  signature.Policy
+		wantErr error
+	}{
+		{
+			name: "signed with a public key",
+			sign: func(t *testing.T, image string) {
+				signaturetest.Sign(t, image, signaturetest.PushImage(t, image), ciKey, "")
+			},
+			policy: signature.Policy{PublicKeys: []string{otherPublicKey, ciPublicKey}},
+		},
+		{
+			name: "signed with another key",
+			sign: func(t *testing.T, image string) {
+				signaturetest.Sign(t, image, signaturetest.PushImage(t, image), ciKey, "")
+			},
+			policy:  signature.Policy{PublicKeys: []string{otherPublicKey}},
+			wantErr: signature.ErrNoValidSignatures,
+		},
+		{
+			name: "not signed",
+			sign: func(t *testing.T, image string) {
+				signaturetest.PushImage(t, image)
+			},
+			policy:  signature.Policy{PublicKeys: []string{ciPublicKey}},
+			wantErr: signature.ErrNoSignatures,
+		},
+		{
+			name: "tag pushed again after signing",
+			sign: func(t *testing.T, image string) {
+				signaturetest.Sign(t, image, signaturetest.PushImage(t, image), ciKey, "")
+				signaturetest.PushImage(t, image)
+			},
+			policy:  signature.Policy{PublicKeys: []string{ciPublicKey}},
+			wantErr: signature.ErrNoSignatures,
+		},
+		{
+			name: "keyless",
+			sign: func(t *testing.T, image string) {
+				key, cert := ca.Issue(t, "ci@example.com", issuer)
+				signaturetest.Sign(t, image, signaturetest.PushImage(t, image), key, cert)
+			},
+			policy: signature.Policy{Keyless: []signature.Identity{
+				{Issuer: issuer, Subject: "ci@example.com", RootCertificates: ca.RootCertificate},
+			}},
+		},
+		{
+			name: "keyless with another subject",
+			sign: func(t *testing.T, image string) {
+				key, cert := ca.Issue(t, "dev@example.com", issuer)
+				signaturetest.Sign(t, image, signaturetest.PushImage(t, image), key, cert)
+			},
+			policy: signature.Policy{Keyless: []signature.Identity{
+				{Issuer: issuer, Subject: "ci@example.com", RootCertificates: ca.RootCertificate},
+			}},
+			wantErr: signature.ErrNoValidSignatures,
+		},
+		{
+			name: "keyless with another issuer",
+			sign: func(t *testing.T, image string) {
+				key, cert := ca.Issue(t, "ci@example.com", "https://accounts.google.com")
+				signaturetest.Sign(t, image, signaturetest.PushImage(t, image), key, cert)
+			},
+			policy: signature.Policy{Keyless: []signature.Identity{
+				{Issuer: issuer, Subject: "ci@example.com", RootCertificates: ca.RootCertificate},
+			}},
+			wantErr: signature.ErrNoValidSignatures,
+		},
+		{
+			name: "keyless certificate of another CA",
+			sign: func(t *testing.T, image string) {
+				key, cert := otherCA.Issue(t, "ci@example.com", issuer)
+				signaturetest.Sign(t, image, signaturetest.PushImage(t, image), key, cert)
+			},
+			policy: signature.Policy{Keyless: []signature.Identity{
+				{Issuer: issuer, Subject: "ci@example.com", RootCertificates: ca.RootCertificate},
+			}},
+			wantErr: signature.ErrNoValidSignatures,
+		},
+		{
+			name: "keyless signature isn't verified by public keys",
+			sign: func(t *testing.T, image string) {
+				_, cert := ca.Issue(t, "ci@example.com", issuer)
+				signaturetest.Sign(t, image, signaturetest.PushImage(t, image), ciKey, cert)
+			},
+			policy:  signature.Policy{PublicKeys: []string{ciPublicKey}},
+			wantErr: signature.ErrNoValidSignatures,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			image := fmt.Sprintf("%s/shipasoftware/app-%d:v1", host, i)
+			tt.sign(t, image)
+			digest, err := signature.Verify(context.Background(), image, tt.policy)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.Nil(t, err)
+
+			// the verified digest is verified as well
+			pinned := fmt.Sprintf("%s/shipasoftware/app-%d:v1@%s", host, i, digest)
+			_, err = signature.Verify(context.Background(), pinned, tt.policy)
+			require.Nil(t, err)
+		})
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	_, publicKey := signaturetest.NewKey(t)
+	ca := signaturetest.NewCA(t)
+
+	require.Nil(t, signature.Policy{PublicKeys: []string{publicKey}}.Validate())
+	require.Nil(t, signature.Policy{Keyless: []signature.Identity{
+		{Issuer: "https://accounts.google.com", Subject: "ci@example.com", RootCertificates: ca.RootCertificate},
+	}}.Validate())
+
+	require.NotNil(t, signature.Policy{PublicKeys: []string{"not a key"}}.Validate())
+	require.NotNil(t, signature.Policy{Keyless: []signature.Identity{
+		{Issuer: "https://accounts.google.com", Subject: "ci@example.com", RootCertificates: "not a certificate"},
+	}}.Validate())
+	require.NotNil(t, signature.Policy{Keyless: []signature.Identity{
+		{Subject: "ci@example.com", RootCertificates: ca.RootCertificate},
+	}}.Validate())
+}
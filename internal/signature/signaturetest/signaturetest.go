@@ -0,0 +1,172 @@
+// Package signaturetest pushes images and cosign signatures to registries in tests.
+package signaturetest
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	registryv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/theketchio/ketch/internal/signature"
+)
+
+// NewRegistry starts an in-memory registry and returns its host.
+func NewRegistry(t *testing.T) string {
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.Nil(t, err)
+	return u.Host
+}
+
+// PushImage pushes a random image and returns its digest.
+func PushImage(t *testing.T, image string) registryv1.Hash {
+	ref, err := name.ParseReference(image)
+	require.Nil(t, err)
+	img, err := random.Image(1024, 1)
+	require.Nil(t, err)
+	require.Nil(t, remote.Write(ref, img))
+	digest, err := img.Digest()
+	require.Nil(t, err)
+	return digest
+}
+
+// NewKey returns an ECDSA key like "cosign generate-key-pair" does and its PEM encoded public key.
+func NewKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.Nil(t, err)
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// CA issues keyless signing certificates.
+type CA struct {
+	// RootCertificate is the PEM encoded certificate of the CA.
+	RootCertificate string
+	cert            *x509.Certificate
+	key             *ecdsa.PrivateKey
+}
+
+// NewCA returns a certificate authority like fulcio.
+func NewCA(t *testing.T) *CA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "signaturetest"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.Nil(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.Nil(t, err)
+	return &CA{
+		RootCertificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		cert:            cert,
+		key:             key,
+	}
+}
+
+// Issue returns a short-lived signing key and its PEM encoded certificate issued to the email by the OIDC issuer.
+func (ca *CA) Issue(t *testing.T, email, issuer string) (*ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err)
+	issuerValue, err := asn1.Marshal(issuer)
+	require.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber:   big.NewInt(time.Now().UnixNano()),
+		NotBefore:      time.Now().Add(-20 * time.Minute),
+		NotAfter:       time.Now().Add(-10 * time.Minute),
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		EmailAddresses: []string{email},
+		ExtraExtensions: []pkix.Extension{
+			{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}, Value: issuerValue},
+		},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.Nil(t, err)
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+// Sign pushes a cosign signature of the image digest made with the key.
+// The certificate is set for keyless signatures and is empty for signatures made with a key pair.
+func Sign(t *testing.T, image string, digest registryv1.Hash, key crypto.Signer, certificate string) {
+	ref, err := name.ParseReference(image)
+	require.Nil(t, err)
+	payload, err := signature.NewPayload(ref.Context().Name(), digest)
+	require.Nil(t, err)
+	hash := sha256.Sum256(payload)
+	sig, err := key.Sign(rand.Reader, hash[:], crypto.SHA256)
+	require.Nil(t, err)
+	annotations := map[string]string{signature.SignatureAnnotation: base64.StdEncoding.EncodeToString(sig)}
+	if certificate != "" {
+		annotations[signature.CertificateAnnotation] = certificate
+	}
+
+	tag := signature.SignatureTag(ref.Context(), digest)
+	base, err := remote.Image(tag)
+	if err != nil {
+		base = empty.Image
+	}
+	img, err := mutate.Append(base, mutate.Addendum{Layer: newPayloadLayer(payload), Annotations: annotations})
+	require.Nil(t, err)
+	require.Nil(t, remote.Write(tag, img))
+}
+
+// payloadLayer is an uncompressed layer with a simple signing payload.
+type payloadLayer struct {
+	payload []byte
+	digest  registryv1.Hash
+}
+
+func newPayloadLayer(payload []byte) *payloadLayer {
+	sum := sha256.Sum256(payload)
+	return &payloadLayer{
+		payload: payload,
+		digest:  registryv1.Hash{Algorithm: "sha256", Hex: fmt.Sprintf("%x", sum)},
+	}
+}
+
+func (l *payloadLayer) Digest() (registryv1.Hash, error) { return l.digest, nil }
+func (l *payloadLayer) DiffID() (registryv1.Hash, error) { return l.digest, nil }
+func (l *payloadLayer) Size() (int64, error)             { return int64(len(l.payload)), nil }
+func (l *payloadLayer) Compressed() (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(l.payload)), nil
+}
+func (l *payloadLayer) Uncompressed() (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(l.payload)), nil
+}
+func (l *payloadLayer) MediaType() (types.MediaType, error) {
+	return signature.SimpleSigningMediaType, nil
+}
@@ -16,15 +16,22 @@ package templates
 
 import (
 	"context"
+	"crypto/sha256"
 	"fmt"
+	"sort"
 
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
 )
 
+// KetchNamespace is the namespace where ketch runs and stores templates.
+const KetchNamespace = "ketch-system"
+
 // Templates represents a helm chart's "templates/" folder.
 type Templates struct {
 
@@ -41,6 +48,9 @@ type Storage struct {
 // Updater knows how to update and delete templates.
 type Updater interface {
 	Update(name string, templates Templates) error
+	// Reset replaces templates with the defaults and marks them as not modified by a user.
+	Reset(name string, defaults Templates) error
+	Delete(name string) error
 }
 
 // Reader knows how to get templates.
@@ -52,6 +62,14 @@ type Reader interface {
 type Client interface {
 	Reader
 	Updater
+	// Modified returns true if templates stored with the provided name differ from the defaults ketch stored there.
+	Modified(name string) (bool, error)
+}
+
+// DefaultsChecksumAnnotation returns an annotation set on a configmap with templates provided by ketch to a checksum of these templates.
+// Once the content of the configmap doesn't match the checksum, the templates are considered modified by a user.
+func DefaultsChecksumAnnotation() string {
+	return ketchv1.Group + "/templates-defaults-checksum"
 }
 
 // NewStorage returns a Storage instance.
@@ -77,6 +95,19 @@ var (
 	}
 )
 
+// IngressDefaultTemplates returns ketch's default templates for the ingress controller type.
+func IngressDefaultTemplates(ingress string) (Templates, bool) {
+	switch ingress {
+	case "istio":
+		return IstioDefaultTemplates, true
+	case "traefik":
+		return TraefikDefaultTemplates, true
+	case "nginx":
+		return NginxDefaultTemplates, true
+	}
+	return Templates{}, false
+}
+
 // IngressConfigMapName returns a name of a configmap to store the ingress' templates to render helm chart.
 func IngressConfigMapName(ingress string) string {
 	return fmt.Sprintf("ingress-%s-templates", ingress)
@@ -87,6 +118,23 @@ func JobConfigMapName() string {
 	return "job-templates"
 }
 
+// WithOverrides returns a copy of the templates with files replaced by files with the same name from overrides.
+// A file with empty content in overrides removes the file.
+func (tpl Templates) WithOverrides(overrides Templates) *Templates {
+	yamls := make(map[string]string, len(tpl.Yamls)+len(overrides.Yamls))
+	for name, value := range tpl.Yamls {
+		yamls[name] = value
+	}
+	for name, value := range overrides.Yamls {
+		if len(value) == 0 {
+			delete(yamls, name)
+			continue
+		}
+		yamls[name] = value
+	}
+	return &Templates{Yamls: yamls}
+}
+
 // Get returns templates stored in a configmap with the provided name.
 func (s *Storage) Get(name string) (*Templates, error) {
 	ctx := context.TODO()
@@ -99,16 +147,87 @@ func (s *Storage) Get(name string) (*Templates, error) {
 }
 
 // Update creates or updates a configmap with the new templates.
+// A configmap created by Update is considered modified by a user, so Seed doesn't replace it.
 func (s *Storage) Update(name string, templates Templates) error {
+	return s.update(name, templates, nil)
+}
+
+// Reset creates or updates a configmap with the default templates.
+func (s *Storage) Reset(name string, defaults Templates) error {
+	return s.update(name, defaults, map[string]string{DefaultsChecksumAnnotation(): defaults.Checksum()})
+}
+
+// Seed stores the default templates unless the configmap contains templates modified by a user.
+// It returns true if the defaults were stored.
+func (s *Storage) Seed(name string, defaults Templates) (bool, error) {
+	modified, err := s.Modified(name)
+	if err != nil || modified {
+		return false, err
+	}
+	return true, s.Reset(name, defaults)
+}
+
+// Modified returns true if the configmap's templates differ from the defaults ketch stored in it.
+// Configmaps created by previous versions of ketch don't have a checksum and are considered not modified,
+// these versions overwrote the configmaps with their defaults on every start.
+func (s *Storage) Modified(name string) (bool, error) {
+	cm := v1.ConfigMap{}
+	if err := s.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: s.namespace}, &cm); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	checksum, ok := cm.Annotations[DefaultsChecksumAnnotation()]
+	if !ok {
+		return false, nil
+	}
+	return checksum != Templates{Yamls: cm.Data}.Checksum(), nil
+}
+
+// Delete deletes a configmap with templates. It doesn't return an error if the configmap doesn't exist.
+func (s *Storage) Delete(name string) error {
+	cm := v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: s.namespace},
+	}
+	return client.IgnoreNotFound(s.client.Delete(context.TODO(), &cm))
+}
+
+// update stores the templates keeping the configmap's metadata, annotations are set on top of the existing ones.
+func (s *Storage) update(name string, templates Templates, annotations map[string]string) error {
 	namespacedName := types.NamespacedName{Name: name, Namespace: s.namespace}
 	ctx := context.TODO()
+	updated := templates.toConfigMap(name, s.namespace)
 	cm := v1.ConfigMap{}
 	if err := s.client.Get(ctx, namespacedName, &cm); err != nil {
-		if apierrors.IsNotFound(err) {
-			return s.client.Create(ctx, templates.toConfigMap(name, s.namespace))
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		updated.Annotations = annotations
+		if updated.Annotations == nil {
+			updated.Annotations = map[string]string{DefaultsChecksumAnnotation(): ""}
+		}
+		return s.client.Create(ctx, updated)
+	}
+	updated.ObjectMeta = cm.ObjectMeta
+	for key, value := range annotations {
+		if updated.Annotations == nil {
+			updated.Annotations = map[string]string{}
 		}
+		updated.Annotations[key] = value
+	}
+	return s.client.Update(ctx, updated)
+}
+
+// Checksum returns a checksum of the templates' names and content.
+func (tpl Templates) Checksum() string {
+	names := make([]string, 0, len(tpl.Yamls))
+	for name := range tpl.Yamls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(h, "%s\x00%s\x00", name, tpl.Yamls[name])
 	}
-	return s.client.Update(ctx, templates.toConfigMap(name, s.namespace))
+	return fmt.Sprintf("%x", h.Sum(nil))
 }
 
 func (tpl Templates) toConfigMap(name string, namespace string) *v1.ConfigMap {
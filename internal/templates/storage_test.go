@@ -4,6 +4,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrlFake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestIngressConfigMapName(t *testing.T) {
@@ -31,3 +36,94 @@ func TestIngressConfigMapName(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplates_WithOverrides(t *testing.T) {
+	defaults := Templates{Yamls: map[string]string{
+		"deployment.yaml": "deployment",
+		"service.yaml":    "service",
+		"ingress.yaml":    "ingress",
+	}}
+	overrides := Templates{Yamls: map[string]string{
+		"deployment.yaml": "custom deployment",
+		"ingress.yaml":    "",
+		"pdb.yaml":        "pdb",
+	}}
+	got := defaults.WithOverrides(overrides)
+	require.Equal(t, map[string]string{
+		"deployment.yaml": "custom deployment",
+		"service.yaml":    "service",
+		"pdb.yaml":        "pdb",
+	}, got.Yamls)
+	require.Equal(t, "deployment", defaults.Yamls["deployment.yaml"])
+}
+
+func TestStorage_Seed(t *testing.T) {
+	legacy := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "ketch-system"},
+		Data:       map[string]string{"deployment.yaml": "old deployment"},
+	}
+	scheme := runtime.NewScheme()
+	require.Nil(t, clientgoscheme.AddToScheme(scheme))
+	cli := ctrlFake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(legacy).Build()
+	storage := NewStorage(cli, "ketch-system")
+
+	defaults := Templates{Yamls: map[string]string{"deployment.yaml": "deployment"}}
+	newDefaults := Templates{Yamls: map[string]string{"deployment.yaml": "new deployment"}}
+
+	// previous versions of ketch overwrote templates on every start, so their templates are the previous defaults
+	seeded, err := storage.Seed("legacy", defaults)
+	require.Nil(t, err)
+	require.True(t, seeded)
+	got, err := storage.Get("legacy")
+	require.Nil(t, err)
+	require.Equal(t, defaults.Yamls, got.Yamls)
+
+	seeded, err = storage.Seed("templates", defaults)
+	require.Nil(t, err)
+	require.True(t, seeded)
+	modified, err := storage.Modified("templates")
+	require.Nil(t, err)
+	require.False(t, modified)
+
+	// new defaults replace templates that weren't modified
+	seeded, err = storage.Seed("templates", newDefaults)
+	require.Nil(t, err)
+	require.True(t, seeded)
+
+	custom := Templates{Yamls: map[string]string{"deployment.yaml": "custom deployment"}}
+	require.Nil(t, storage.Update("templates", custom))
+	modified, err = storage.Modified("templates")
+	require.Nil(t, err)
+	require.True(t, modified)
+
+	seeded, err = storage.Seed("templates", defaults)
+	require.Nil(t, err)
+	require.False(t, seeded)
+	got, err = storage.Get("templates")
+	require.Nil(t, err)
+	require.Equal(t, custom.Yamls, got.Yamls)
+
+	require.Nil(t, storage.Reset("templates", defaults))
+	modified, err = storage.Modified("templates")
+	require.Nil(t, err)
+	require.False(t, modified)
+
+	require.Nil(t, storage.Delete("templates"))
+	require.Nil(t, storage.Delete("templates"))
+	modified, err = storage.Modified("templates")
+	require.Nil(t, err)
+	require.False(t, modified)
+
+	require.Nil(t, storage.Update("templates", custom))
+	seeded, err = storage.Seed("templates", defaults)
+	require.Nil(t, err)
+	require.False(t, seeded)
+}
+
+func TestTemplates_Checksum(t *testing.T) {
+	a := Templates{Yamls: map[string]string{"a.yaml": "a", "b.yaml": "b"}}
+	b := Templates{Yamls: map[string]string{"b.yaml": "b", "a.yaml": "a"}}
+	c := Templates{Yamls: map[string]string{"a.yaml": "ab", "b.yaml": ""}}
+	require.Equal(t, a.Checksum(), b.Checksum())
+	require.NotEqual(t, a.Checksum(), c.Checksum())
+}
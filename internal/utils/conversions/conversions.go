@@ -11,3 +11,7 @@ func StrPtr(s string) *string {
 func BoolPtr(b bool) *bool {
 	return &b
 }
+
+func Int64Ptr(i int64) *int64 {
+	return &i
+}
@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+var (
+	// envNameRegexp matches names of env variables accepted by kubernetes.
+	envNameRegexp = regexp.MustCompile(`^[-._a-zA-Z][-._a-zA-Z0-9]*$`)
+
+	// plainEnvValueRegexp matches values written to a dotenv file without quotes.
+	plainEnvValueRegexp = regexp.MustCompile(`^[-._a-zA-Z0-9/:@%+,=?]*$`)
+
+	dotenvEscaper   = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	dotenvUnescaper = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\$`, `$`, `\n`, "\n", `\r`, "\r", `\t`, "\t")
+)
+
+// ReadEnvFile reads env variables from a file.
+// Files with .json, .yaml or .yml extensions contain an object of names and values, other files are parsed as dotenv files.
+func ReadEnvFile(filename string) ([]ketchv1.Env, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]string
+	switch filepath.Ext(filename) {
+	case ".json":
+		err = json.Unmarshal(content, &values)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &values)
+	default:
+		return ParseDotenv(content)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("env variables should be an object of string values: %w", err)
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		if !envNameRegexp.MatchString(name) {
+			return nil, fmt.Errorf("invalid env variable name %q", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	envs := make([]ketchv1.Env, 0, len(names))
+	for _, name := range names {
+		envs = append(envs, ketchv1.Env{Name: name, Value: values[name]})
+	}
+	return envs, nil
+}
+
+// ParseDotenv parses env variables in the dotenv format.
+// Blank lines, comments and the "export" prefix are skipped. Values in single quotes are taken literally,
+// values in double quotes support \n, \r, \t, \", \$ and \\ escapes, and both can span multiple lines.
+// Unquoted values are trimmed and end at a " #" comment. A variable set more than once takes the last value.
+func ParseDotenv(content []byte) ([]ketchv1.Env, error) {
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	var envs []ketchv1.Env
+	indexes := make(map[string]int)
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimLeft(lines[i], " \t")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("line %d: env variables should have NAME=VALUE format", lineNumber)
+		}
+		name := strings.TrimSpace(parts[0])
+		if !envNameRegexp.MatchString(name) {
+			return nil, fmt.Errorf("line %d: invalid env variable name %q", lineNumber, name)
+		}
+		value := strings.TrimLeft(parts[1], " \t")
+		if value == "" || (value[0] != '"' && value[0] != '\'') {
+			value = strings.TrimSpace(value)
+			if comment := strings.Index(value, " #"); comment >= 0 {
+				value = strings.TrimSpace(value[:comment])
+			}
+		} else {
+			quote := value[0]
+			rest := value[1:]
+			for {
+				end := closingQuoteIndex(rest, quote)
+				if end >= 0 {
+					if tail := strings.TrimSpace(rest[end+1:]); tail != "" && !strings.HasPrefix(tail, "#") {
+						return nil, fmt.Errorf("line %d: unexpected characters after the quoted value of %s", lineNumber, name)
+					}
+					value = rest[:end]
+					break
+				}
+				i++
+				if i == len(lines) {
+					return nil, fmt.Errorf("line %d: unterminated quoted value of %s", lineNumber, name)
+				}
+				rest += "\n" + lines[i]
+			}
+			if quote == '"' {
+				value = dotenvUnescaper.Replace(value)
+			}
+		}
+		if index, ok := indexes[name]; ok {
+			envs[index].Value = value
+			continue
+		}
+		indexes[name] = len(envs)
+		envs = append(envs, ketchv1.Env{Name: name, Value: value})
+	}
+	return envs, nil
+}
+
+// FormatDotenv formats env variables in the dotenv format, ParseDotenv returns the same env variables back.
+func FormatDotenv(envs []ketchv1.Env) []byte {
+	var b strings.Builder
+	for _, env := range envs {
+		if plainEnvValueRegexp.MatchString(env.Value) {
+			fmt.Fprintf(&b, "%s=%s\n", env.Name, env.Value)
+			continue
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"\n", env.Name, dotenvEscaper.Replace(env.Value))
+	}
+	return []byte(b.String())
+}
+
+// closingQuoteIndex returns the index of the quote closing a value, backslash escapes are skipped in double quoted values.
+func closingQuoteIndex(value string, quote byte) int {
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			if quote == '"' {
+				i++
+			}
+		case quote:
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	ketchv1 "github.com/theketchio/ketch/internal/api/v1beta1"
+)
+
+func TestParseDotenv(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []ketchv1.Env
+		wantErr string
+	}{
+		{
+			name: "plain values and comments",
+			content: `# database
+DB_HOST=db.example.com
+export DB_PORT = 5432 # default port
+
+URL=https://example.com/?a=b#anchor
+EMPTY=
+`,
+			want: []ketchv1.Env{
+				{Name: "DB_HOST", Value: "db.example.com"},
+				{Name: "DB_PORT", Value: "5432"},
+				{Name: "URL", Value: "https://example.com/?a=b#anchor"},
+				{Name: "EMPTY", Value: ""},
+			},
+		},
+		{
+			name: "quoted values",
+			content: `SINGLE='it has $HOME and \n'
+DOUBLE="say \"hi\"\n\ttab \$HOME \\" # comment
+SPACES="  padded  "`,
+			want: []ketchv1.Env{
+				{Name: "SINGLE", Value: `it has $HOME and \n`},
+				{Name: "DOUBLE", Value: "say \"hi\"\n\ttab $HOME \\"},
+				{Name: "SPACES", Value: "  padded  "},
+			},
+		},
+		{
+			name: "multiline values",
+			content: `KEY="-----BEGIN KEY-----
+abc
+-----END KEY-----"
+NOTE='first
+second'
+`,
+			want: []ketchv1.Env{
+				{Name: "KEY", Value: "-----BEGIN KEY-----\nabc\n-----END KEY-----"},
+				{Name: "NOTE", Value: "first\nsecond"},
+			},
+		},
+		{
+			name:    "last value wins",
+			content: "A=1\nB=2\nA=3\n",
+			want: []ketchv1.Env{
+				{Name: "A", Value: "3"},
+				{Name: "B", Value: "2"},
+			},
+		},
+		{
+			name:    "missing value",
+			content: "A=1\nB\n",
+			wantErr: "line 2: env variables should have NAME=VALUE format",
+		},
+		{
+			name:    "unterminated quote",
+			content: "A=\"1\nB=2\n",
+			wantErr: "line 1: unterminated quoted value of A",
+		},
+		{
+			name:    "characters after quote",
+			content: "A='1' 2\n",
+			wantErr: "line 1: unexpected characters after the quoted value of A",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDotenv([]byte(tt.content))
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatDotenv(t *testing.T) {
+	envs := []ketchv1.Env{
+		{Name: "PLAIN", Value: "postgres://db:5432/app?ssl=on"},
+		{Name: "EMPTY", Value: ""},
+		{Name: "SPACES", Value: " a b "},
+		{Name: "SPECIAL", Value: "say \"hi\" to $USER \\ # not a comment"},
+		{Name: "MULTILINE", Value: "first\nsecond\r\n\tthird"},
+	}
+	content := FormatDotenv(envs)
+	require.Equal(t, `PLAIN=postgres://db:5432/app?ssl=on
+EMPTY=
+SPACES=" a b "
+SPECIAL="say \"hi\" to \$USER \\ # not a comment"
+MULTILINE="first\nsecond\r\n\tthird"
+`, string(content))
+
+	got, err := ParseDotenv(content)
+	require.Nil(t, err)
+	require.Equal(t, envs, got)
+}
+
+func TestReadEnvFile(t *testing.T) {
+	dir := t.TempDir()
+	want := []ketchv1.Env{
+		{Name: "A", Value: "1"},
+		{Name: "B", Value: "two words"},
+	}
+	files := map[string]string{
+		".env":      "B='two words'\nA=1\n",
+		"env.json":  `{"B": "two words", "A": "1"}`,
+		"env.yaml":  "B: two words\nA: \"1\"\n",
+		"bad.json":  `{"A": 1}`,
+		"bad.yml":   "not a name: 1\n",
+		"empty.env": "",
+	}
+	for name, content := range files {
+		require.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+
+	got, err := ReadEnvFile(filepath.Join(dir, ".env"))
+	require.Nil(t, err)
+	require.Equal(t, []ketchv1.Env{want[1], want[0]}, got)
+
+	for _, name := range []string{"env.json", "env.yaml"} {
+		got, err = ReadEnvFile(filepath.Join(dir, name))
+		require.Nil(t, err)
+		require.Equal(t, want, got)
+	}
+
+	got, err = ReadEnvFile(filepath.Join(dir, "empty.env"))
+	require.Nil(t, err)
+	require.Empty(t, got)
+
+	_, err = ReadEnvFile(filepath.Join(dir, "bad.json"))
+	require.NotNil(t, err)
+	_, err = ReadEnvFile(filepath.Join(dir, "bad.yml"))
+	require.NotNil(t, err)
+	_, err = ReadEnvFile(filepath.Join(dir, "missing.env"))
+	require.NotNil(t, err)
+}